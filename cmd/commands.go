@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/bulk"
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/middleware"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/service"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	importCommand = "import"
+	exportCommand = "export"
+	verifyCommand = "verify"
+	configCommand = "config"
+
+	printSubcommand = "print"
+
+	importTypeAccounts           = "accounts"
+	importTypeLimitOffers        = "limit_offers"
+	importTypeLimitOfferStatuses = "limit_offer_statuses"
+
+	reportFormatJSON = "json"
+	reportFormatCSV  = "csv"
+)
+
+// runCommand executes the cli subcommand and returns the exit code of the process
+func runCommand(client *service.CreditCardLimitOfferService, args []string) int {
+	switch args[0] {
+	case importCommand:
+		return runImport(client, args[1:])
+	case exportCommand:
+		return runExport(client, args[1:])
+	case verifyCommand:
+		return runVerify(client)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, supported commands are %q, %q, %q and %q\n", args[0], importCommand, exportCommand, verifyCommand, configCommand)
+		return 2
+	}
+}
+
+// runImport imports a csv file of accounts, limit offers or offer decisions and prints the per row report
+func runImport(client *service.CreditCardLimitOfferService, args []string) int {
+	flags := flag.NewFlagSet(importCommand, flag.ContinueOnError)
+	importType := flags.String("type", "", "what the csv contains: accounts, limit_offers or limit_offer_statuses")
+	file := flags.String("file", "", "path of the csv file, stdin is read when empty")
+	format := flags.String("format", reportFormatJSON, "format of the report printed to stdout: json or csv")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var importCSV func(*gin.Context, io.Reader) (models.BulkImportReport, *limitoffererror.CreditCardError)
+	switch *importType {
+	case importTypeAccounts:
+		importCSV = client.ImportAccountsCSV
+	case importTypeLimitOffers:
+		importCSV = client.ImportLimitOffersCSV
+	case importTypeLimitOfferStatuses:
+		importCSV = client.ImportLimitOfferStatusesCSV
+	default:
+		fmt.Fprintf(os.Stderr, "invalid -type %q\n", *importType)
+		return 2
+	}
+
+	input := io.Reader(os.Stdin)
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to open %v : %v\n", *file, err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	}
+
+	report, creditCardErr := importCSV(utils.NewBackgroundContext(), input)
+	if creditCardErr != nil {
+		fmt.Fprintln(os.Stderr, creditCardErr.Message)
+		return 1
+	}
+
+	var err error
+	if *format == reportFormatCSV {
+		err = bulk.WriteReport(os.Stdout, report)
+	} else {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write the report : %v\n", err)
+		return 1
+	}
+
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runExport writes the limit offers matching the filter flags as csv
+func runExport(client *service.CreditCardLimitOfferService, args []string) int {
+	flags := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	accountID := flags.String("account_id", "", "only export the offers of this account")
+	statuses := flags.String("status", "", "comma separated offer statuses to export")
+	from := flags.String("from", "", "RFC3339 lower bound of the offer activation time")
+	to := flags.String("to", "", "RFC3339 upper bound of the offer activation time")
+	file := flags.String("file", "", "path of the output csv file, stdout is used when empty")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	filter := models.LimitOfferFilter{AccountID: *accountID}
+	if *statuses != "" {
+		for _, status := range strings.Split(*statuses, ",") {
+			filter.Status = append(filter.Status, models.OfferStatus(strings.TrimSpace(status)))
+		}
+	}
+	for _, bound := range []struct {
+		value  string
+		target **time.Time
+	}{{*from, &filter.ActivationFrom}, {*to, &filter.ActivationTo}} {
+		if bound.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid timestamp %q : %v\n", bound.value, err)
+			return 2
+		}
+		*bound.target = &parsed
+	}
+	// the filter is checked as the http export checks it, before the output file is created
+	if filter.AccountID != "" {
+		if _, err := uuid.Parse(filter.AccountID); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -account_id %q\n", filter.AccountID)
+			return 2
+		}
+	}
+	if err := middleware.ValidateLimitOfferFilter(filter); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	output := io.Writer(os.Stdout)
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to create %v : %v\n", *file, err)
+			return 1
+		}
+		defer f.Close()
+		output = f
+	}
+
+	creditCardErr := client.ExportLimitOffersCSV(utils.NewBackgroundContext(), filter, output)
+	if creditCardErr != nil {
+		fmt.Fprintln(os.Stderr, creditCardErr.Message)
+		return 1
+	}
+	return 0
+}
+
+// runVerify checks the hash chain of the audit log, the exit code is 1 when the log was tampered with
+func runVerify(client *service.CreditCardLimitOfferService) int {
+	verified, err := client.VerifyAuditLog(utils.NewBackgroundContext())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit log verification failed after %v valid events : %v\n", verified, err)
+		return 1
+	}
+	fmt.Printf("audit log is intact, %v events verified\n", verified)
+	return 0
+}
+
+// stringList is a flag which can be repeated, its values are kept in order
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runConfig runs the config subcommands: print writes the effective config with its secrets masked
+func runConfig(args []string) int {
+	if len(args) != 1 || args[0] != printSubcommand {
+		fmt.Fprintf(os.Stderr, "usage : %v %v\n", configCommand, printSubcommand)
+		return 2
+	}
+	printed, err := config.Print(config.GetConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to print the config : %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(printed)
+	return 0
+}
@@ -1,14 +1,27 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"os"
+	"sync"
+	"time"
 
+	"github.com/ankit/project/credit-card-offer-limit/internal/auth"
 	"github.com/ankit/project/credit-card-offer-limit/internal/config"
 	"github.com/ankit/project/credit-card-offer-limit/internal/db"
+	"github.com/ankit/project/credit-card-offer-limit/internal/health"
+	"github.com/ankit/project/credit-card-offer-limit/internal/notify"
+	"github.com/ankit/project/credit-card-offer-limit/internal/outbox"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
 	"github.com/ankit/project/credit-card-offer-limit/internal/server"
 	"github.com/ankit/project/credit-card-offer-limit/internal/service"
+	"github.com/ankit/project/credit-card-offer-limit/internal/stream"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/ankit/project/credit-card-offer-limit/internal/webhook"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -16,21 +29,129 @@ func main() {
 	// Initializing the Log client
 	utils.InitLogClient()
 
+	// Parsing the flags, the arguments left are the cli subcommand, if any
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	var configFiles stringList
+	flags.Var(&configFiles, "config", "config file, repeat it to load several files each overriding the keys set by the ones before it")
+	environment := flags.String("env", os.Getenv(config.EnvPrefix+"_ENV"), "environment whose <env>.toml, next to the first config file, overrides the config files")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
 	// Initializing the GlobalConfig
-	err := config.InitGlobalConfig()
+	files, err := config.Files(configFiles, *environment, os.LookupEnv)
+	if err != nil {
+		utils.Logger.Fatal("Unable to find the config", zap.Error(err))
+	}
+	err = config.InitGlobalConfig(files)
 	if err != nil {
-		log.Fatalf("Unable to initialize global config")
+		utils.Logger.Fatal("Unable to initialize global config", zap.Error(err))
+	}
+
+	// Printing the effective config does not need anything else
+	if len(args) > 0 && args[0] == configCommand {
+		os.Exit(runConfig(args[1:]))
+	}
+
+	// Replacing the development logger by the configured one
+	if err := utils.InitLogger(config.GetConfig().Logging); err != nil {
+		utils.Logger.Fatal("Unable to initialize the logger", zap.Error(err))
+	}
+
+	// Initializing the export of the spans
+	shutdownTracing, err := tracing.Init(config.GetConfig().Tracing)
+	if err != nil {
+		utils.Logger.Fatal("Unable to initialize tracing", zap.Error(err))
+	}
+	flushSpans := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			utils.Logger.Error("Unable to flush the spans", zap.Error(err))
+		}
 	}
 
 	// Establishing the connection to DB.
 	postgres, err := db.New()
 	if err != nil {
-		log.Fatal("Unable to connect to DB : ", err)
+		utils.Logger.Fatal("Unable to connect to DB", zap.Error(err))
 	}
 
 	// Initializing the client for notes service
-	_ = service.NewCreditCardLimitOfferService(postgres)
+	client := service.NewCreditCardLimitOfferService(postgres)
+
+	// Initializing the probes of the orchestrator, the readiness probe follows the workers
+	probe := health.NewProbe(postgres, time.Duration(config.GetConfig().Server.ReadinessTimeout)*time.Second)
+
+	// Running the cli subcommand, if any, instead of the server
+	if len(args) > 0 {
+		code := runCommand(client, args)
+		flushSpans()
+		utils.Logger.Sync()
+		os.Exit(code)
+	}
+
+	// Building the authenticator of the api requests
+	authenticator, err := auth.New(config.GetConfig().Auth, postgres)
+	if err != nil {
+		utils.Logger.Fatal("Unable to initialize authentication", zap.Error(err))
+	}
+
+	// Starting the background workers, they are stopped once the server is shut down
+	workersCtx, cancelWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	runWorker := func(name string, work func(context.Context)) {
+		workers.Add(1)
+		stopped := probe.WorkerStarted(name)
+		go func() {
+			defer workers.Done()
+			defer stopped()
+			work(workersCtx)
+		}()
+	}
+
+	// Reloading the settings which can change without a restart, a SIGHUP no longer terminates the process
+	go watchConfig(workersCtx, files, time.Duration(config.GetConfig().Server.ConfigReloadInterval)*time.Second)
+
+	if interval := config.GetConfig().LimitOffer.ExpirySweepInterval; interval > 0 {
+		runWorker("expiry_sweeper", func(ctx context.Context) { client.RunExpirySweeper(ctx, time.Duration(interval)*time.Second) })
+	}
+
+	if outboxConfig := config.GetConfig().Outbox; outboxConfig.Enabled {
+		publisher, err := outbox.NewPublisher(outboxConfig)
+		if err != nil {
+			utils.Logger.Fatal("Unable to initialize the outbox publisher", zap.Error(err))
+		}
+		// the webhook subscriptions and the notifications get the events through the relay along with the configured publisher
+		publishers := outbox.MultiPublisher{publisher}
+		if webhooksConfig := config.GetConfig().Webhooks; webhooksConfig.Enabled {
+			publishers = append(publishers, webhook.NewSubscriptionPublisher(postgres))
+			runWorker("webhook_dispatcher", webhook.NewDispatcher(webhooksConfig, postgres).Run)
+		}
+		if notificationsConfig := config.GetConfig().Notifications; notificationsConfig.Enabled {
+			renderer, err := notify.NewRenderer(notificationsConfig.DefaultLocale)
+			if err != nil {
+				utils.Logger.Fatal("Unable to load the notification templates", zap.Error(err))
+			}
+			notifier, err := notify.NewNotifier(notificationsConfig)
+			if err != nil {
+				utils.Logger.Fatal("Unable to initialize the notifier", zap.Error(err))
+			}
+			publishers = append(publishers, notify.NewEventPublisher(postgres))
+			runWorker("notification_dispatcher", notify.NewDispatcher(notificationsConfig, postgres, renderer, notifier).Run)
+		}
+		runWorker("outbox_relay", outbox.NewRelay(outboxConfig, postgres, publishers).Run)
+	}
+
+	if streamConfig := config.GetConfig().Stream; streamConfig.Enabled {
+		broker := stream.NewBroker(streamConfig)
+		client.SetEventBroker(broker)
+		runWorker("event_stream", func(ctx context.Context) { broker.Run(ctx, postgres) })
+	}
 
 	// Starting the server
-	server.Start()
+	// the rate limits of the requests are counted by each replica, a store shared by the replicas implements ratelimit.Store
+	server.Start(authenticator, ratelimit.NewMemoryStore(), probe, func() {
+		cancelWorkers()
+		workers.Wait()
+	}, flushSpans)
 }
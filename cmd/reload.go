@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"go.uber.org/zap"
+)
+
+// watchConfig reloads the config files on a SIGHUP and, when the interval is positive, once one of them is
+// modified, until ctx is cancelled
+func watchConfig(ctx context.Context, files []string, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var changes <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		changes = ticker.C
+	}
+	modTimes := configModTimes(files)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			utils.Logger.Info("received SIGHUP, reloading the config")
+		case <-changes:
+			current := configModTimes(files)
+			if reflect.DeepEqual(current, modTimes) {
+				continue
+			}
+			// an invalid edit is reported once, the files are reloaded again at their next change
+			modTimes = current
+		}
+		reloadConfig(files)
+	}
+}
+
+// configModTimes returns the modification time of the files, a missing file has none
+func configModTimes(files []string) map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// reloadConfig swaps the reloadable settings of the configuration in use for the ones of the files, the changes
+// of the other settings are logged and ignored until the next restart
+func reloadConfig(files []string) {
+	applied, rejected, err := config.Reload(files, os.LookupEnv)
+	if err != nil {
+		utils.Logger.Error("unable to reload the config, the current one is still used", zap.Error(err))
+		return
+	}
+	for _, key := range rejected {
+		utils.Logger.Warn("the setting can not be changed without a restart, its current value is still used", zap.String("setting", key))
+	}
+	if err := utils.SetLogLevel(config.GetConfig().Logging.Level); err != nil {
+		utils.Logger.Error("unable to change the log level", zap.Error(err))
+	}
+	utils.Logger.Info("reloaded the config", zap.Strings("settings", applied))
+}
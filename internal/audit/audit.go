@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+)
+
+// hashedFields are the fields of an audit event covered by its hash, the previous hash chains the events together
+type hashedFields struct {
+	Seq           int64           `json:"seq"`
+	PrevHash      string          `json:"prev_hash"`
+	TransactionID string          `json:"transaction_id"`
+	ClientID      string          `json:"client_id"`
+	Subject       string          `json:"subject"`
+	Action        string          `json:"action"`
+	EntityType    string          `json:"entity_type"`
+	EntityID      string          `json:"entity_id"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+// Hash returns the sha256 hex digest of the event chained to the hash of the previous event.
+// The timestamp is hashed with microsecond precision, which is what postgres stores.
+func Hash(event models.AuditEvent) string {
+	fields := hashedFields{
+		Seq:           event.Seq,
+		PrevHash:      event.PrevHash,
+		TransactionID: event.TransactionID,
+		ClientID:      event.ClientID,
+		Subject:       event.Subject,
+		Action:        event.Action,
+		EntityType:    event.EntityType,
+		EntityID:      event.EntityID,
+		Before:        rawOrNull(event.Before),
+		After:         rawOrNull(event.After),
+		CreatedAt:     event.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	}
+	// marshalling a struct of strings and raw messages can not fail
+	content, _ := json.Marshal(fields)
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:])
+}
+
+func rawOrNull(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return raw
+}
+
+// Verifier checks that the events, fed in the order of their sequence numbers, form an unbroken hash chain
+type Verifier struct {
+	prevHash string
+	verified int
+}
+
+// Verify checks the next event, the error tells which event breaks the chain and how
+func (v *Verifier) Verify(event models.AuditEvent) error {
+	if event.PrevHash != v.prevHash {
+		return fmt.Errorf("audit event %v does not link to the previous event, an event was removed or altered before it", event.Seq)
+	}
+	if Hash(event) != event.Hash {
+		return fmt.Errorf("audit event %v was altered, its hash does not match its content", event.Seq)
+	}
+	v.prevHash = event.Hash
+	v.verified++
+	return nil
+}
+
+// Verified returns the number of events verified so far
+func (v *Verifier) Verified() int {
+	return v.verified
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// chain returns audit events hashed and linked the way they are appended to the audit log
+func chain(count int) []models.AuditEvent {
+	events := []models.AuditEvent{}
+	prevHash := ""
+	for i := 1; i <= count; i++ {
+		event := models.AuditEvent{
+			Seq:           int64(i),
+			TransactionID: "288a59c1-b826-42f7-a3cd-bf2911a5c351",
+			ClientID:      "back-office",
+			Subject:       "officer-1",
+			Action:        "limit_offer.status_updated",
+			EntityType:    "limit_offer",
+			EntityID:      "abfe7bda-d59d-49ca-b2d0-39e3ecb12fb5",
+			Before:        json.RawMessage(`{"status":"PENDING"}`),
+			After:         json.RawMessage(`{"status":"ACCEPTED"}`),
+			CreatedAt:     time.Date(2023, 8, 24, 2, 17, 0, 17507000, time.UTC),
+			PrevHash:      prevHash,
+		}
+		event.Hash = Hash(event)
+		prevHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
+
+func verify(events []models.AuditEvent) (int, error) {
+	var verifier Verifier
+	for _, event := range events {
+		if err := verifier.Verify(event); err != nil {
+			return verifier.Verified(), err
+		}
+	}
+	return verifier.Verified(), nil
+}
+
+func TestHash(t *testing.T) {
+	event := chain(1)[0]
+
+	// case 1 : the hash only depends on the content
+	assert.Equal(t, event.Hash, Hash(event))
+
+	// case 2 : the timestamp is hashed with the precision postgres stores
+	event.CreatedAt = event.CreatedAt.Add(300 * time.Nanosecond).In(time.FixedZone("IST", 19800))
+	assert.Equal(t, event.Hash, Hash(event))
+
+	// case 3 : a missing snapshot hashes as null
+	event.Before = nil
+	withoutBefore := Hash(event)
+	event.Before = json.RawMessage("null")
+	assert.Equal(t, withoutBefore, Hash(event))
+}
+
+func TestVerifier(t *testing.T) {
+	// case 1 : intact chain
+	verified, err := verify(chain(3))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, verified)
+
+	// case 2 : altered snapshot
+	events := chain(3)
+	events[1].After = json.RawMessage(`{"status":"REJECTED"}`)
+	verified, err = verify(events)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, verified)
+
+	// case 3 : removed event
+	events = chain(3)
+	verified, err = verify(append(events[:1], events[2:]...))
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, verified)
+
+	// case 4 : altered event whose hash was recomputed breaks the link of the next event
+	events = chain(3)
+	events[1].Subject = "someone-else"
+	events[1].Hash = Hash(events[1])
+	verified, err = verify(events)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, verified)
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// configuredKey is an api key of the configuration, only its digest is known
+type configuredKey struct {
+	digest    []byte
+	principal models.Principal
+}
+
+// apiKeyAuthenticator verifies the X-API-Key header against the configured keys and optionally the api_key table
+type apiKeyAuthenticator struct {
+	keys  []configuredKey
+	store APIKeyStore
+}
+
+// HashAPIKey returns the sha256 hex digest of the key, which is what gets configured or stored instead of the key
+func HashAPIKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+func newAPIKeyAuthenticator(cfg config.Auth, store APIKeyStore) (*apiKeyAuthenticator, error) {
+	authenticator := &apiKeyAuthenticator{}
+	if cfg.APIKeysFromDB {
+		authenticator.store = store
+	}
+
+	for _, key := range cfg.APIKeys {
+		digest := strings.ToLower(strings.TrimSpace(key.KeySHA256))
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != sha256.Size*2 {
+			return nil, fmt.Errorf("key_sha256 of api key of %q is not a sha256 hex digest", key.ClientID)
+		}
+		if key.ClientID == "" {
+			return nil, fmt.Errorf("client_id of api key %v is missing", digest)
+		}
+		if err := validateRoles(key.Roles); err != nil {
+			return nil, fmt.Errorf("api key of %q has an %w", key.ClientID, err)
+		}
+		authenticator.keys = append(authenticator.keys, configuredKey{
+			digest: []byte(digest),
+			principal: models.Principal{
+				ClientID:   key.ClientID,
+				CustomerID: key.CustomerID,
+				Subject:    key.ClientID,
+				Scopes:     key.Scopes,
+				Roles:      key.Roles,
+				AuthMethod: constants.AuthMethodAPIKey,
+			},
+		})
+	}
+	return authenticator, nil
+}
+
+func (a *apiKeyAuthenticator) Authenticate(ctx *gin.Context) (*models.Principal, error) {
+	key := ctx.GetHeader(constants.APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+	digest := HashAPIKey(key)
+
+	// every configured key is compared so that the time taken does not tell which one matched
+	var matched *models.Principal
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(a.keys[i].digest, []byte(digest)) == 1 {
+			matched = &a.keys[i].principal
+		}
+	}
+	if matched != nil {
+		principal := *matched
+		return &principal, nil
+	}
+
+	if a.store != nil {
+		principal, err := a.store.GetAPIKeyPrincipal(ctx, digest)
+		if err == nil {
+			return &principal, nil
+		}
+		if err.Code != http.StatusNotFound {
+			return nil, err
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidCredentials is returned when the request carries credentials which can not be verified
+var ErrInvalidCredentials = errors.New(constants.Unauthorized)
+
+// AllScopes are the scopes known to the service, the anonymous principal holds all of them
+var AllScopes = []string{
+	constants.ScopeAccountsRead,
+	constants.ScopeAccountsWrite,
+	constants.ScopeOffersRead,
+	constants.ScopeOffersWrite,
+	constants.ScopeOffersDecide,
+	constants.ScopeOffersApprove,
+	constants.ScopeAuditRead,
+	constants.ScopeWebhooksManage,
+}
+
+// AllRoles are the roles known to the service, the anonymous principal holds all of them
+var AllRoles = []string{constants.RoleCustomer, constants.RoleRiskOfficer, constants.RoleAdmin}
+
+// Authenticator identifies the caller of a request. It returns a nil principal without an error when the
+// request does not carry the kind of credentials it handles, so that the next authenticator gets a chance.
+type Authenticator interface {
+	Authenticate(ctx *gin.Context) (*models.Principal, error)
+}
+
+// APIKeyStore looks up api keys which are not part of the configuration
+type APIKeyStore interface {
+	GetAPIKeyPrincipal(*gin.Context, string) (models.Principal, *limitoffererror.CreditCardError)
+}
+
+// Chain tries the authenticators in order until one of them recognises the credentials of the request
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx *gin.Context) (*models.Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(ctx)
+		if err != nil || principal != nil {
+			return principal, err
+		}
+	}
+	return nil, nil
+}
+
+// anonymous serves every request as the same principal holding all the scopes, it is used when authentication is disabled
+type anonymous struct{}
+
+func (anonymous) Authenticate(ctx *gin.Context) (*models.Principal, error) {
+	return &models.Principal{
+		ClientID:   constants.AnonymousClientID,
+		Subject:    constants.AnonymousClientID,
+		Scopes:     AllScopes,
+		Roles:      AllRoles,
+		AuthMethod: constants.AuthMethodAnonymous,
+	}, nil
+}
+
+// New builds the authenticator described by the configuration, api keys are always accepted while
+// bearer tokens are only accepted when at least one verification key is configured and client certificates
+// when their subjects are configured.
+func New(cfg config.Auth, store APIKeyStore) (Authenticator, error) {
+	if !cfg.Enabled {
+		return anonymous{}, nil
+	}
+
+	apiKeys, err := newAPIKeyAuthenticator(cfg, store)
+	if err != nil {
+		return nil, err
+	}
+	chain := Chain{apiKeys}
+
+	bearer, err := newJWTAuthenticator(cfg.JWT)
+	if err != nil {
+		return nil, err
+	}
+	if bearer != nil {
+		chain = append(chain, bearer)
+	}
+
+	// the credentials sent along with the request take precedence over the certificate of the connection
+	if len(cfg.ClientCertificates) > 0 {
+		certificates, err := newClientCertificateAuthenticator(cfg.ClientCertificates)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, certificates)
+	}
+	return chain, nil
+}
+
+// validateRoles makes sure that only the known roles are granted
+func validateRoles(roles []string) error {
+	for _, role := range roles {
+		known := false
+		for _, knownRole := range AllRoles {
+			known = known || role == knownRole
+		}
+		if !known {
+			return fmt.Errorf("unknown role %q", role)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func requestContext(header, value string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/get_account/1", nil)
+	if header != "" {
+		ctx.Request.Header.Set(header, value)
+	}
+	return ctx
+}
+
+type apiKeyStore map[string]models.Principal
+
+func (s apiKeyStore) GetAPIKeyPrincipal(ctx *gin.Context, digest string) (models.Principal, *limitoffererror.CreditCardError) {
+	if principal, ok := s[digest]; ok {
+		return principal, nil
+	}
+	return models.Principal{}, &limitoffererror.CreditCardError{Code: http.StatusNotFound, Message: "api key not found"}
+}
+
+func TestDisabledAuthentication(t *testing.T) {
+	authenticator, err := New(config.Auth{Enabled: false}, nil)
+	assert.Nil(t, err)
+
+	principal, err := authenticator.Authenticate(requestContext("", ""))
+	assert.Nil(t, err)
+	assert.Equal(t, constants.AuthMethodAnonymous, principal.AuthMethod)
+	assert.True(t, principal.HasScope(constants.ScopeOffersDecide))
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	cfg := config.Auth{
+		Enabled:       true,
+		APIKeysFromDB: true,
+		APIKeys: []config.APIKey{
+			{ClientID: "back-office", KeySHA256: HashAPIKey("configured-key"), Scopes: []string{constants.ScopeAccountsRead}, Roles: []string{constants.RoleRiskOfficer}},
+		},
+	}
+	store := apiKeyStore{HashAPIKey("stored-key"): {ClientID: "mobile", CustomerID: "customer-1", AuthMethod: constants.AuthMethodAPIKey}}
+	authenticator, err := New(cfg, store)
+	assert.Nil(t, err)
+
+	// case 1 : configured key
+	principal, err := authenticator.Authenticate(requestContext(constants.APIKeyHeader, "configured-key"))
+	assert.Nil(t, err)
+	assert.Equal(t, "back-office", principal.ClientID)
+	assert.True(t, principal.HasScope(constants.ScopeAccountsRead))
+	assert.False(t, principal.HasScope(constants.ScopeAccountsWrite))
+	assert.True(t, principal.HasRole(constants.RoleRiskOfficer))
+
+	// case 2 : key from the store
+	principal, err = authenticator.Authenticate(requestContext(constants.APIKeyHeader, "stored-key"))
+	assert.Nil(t, err)
+	assert.Equal(t, "customer-1", principal.CustomerID)
+
+	// case 3 : unknown key
+	_, err = authenticator.Authenticate(requestContext(constants.APIKeyHeader, "unknown-key"))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// case 4 : no credentials
+	principal, err = authenticator.Authenticate(requestContext("", ""))
+	assert.Nil(t, err)
+	assert.Nil(t, principal)
+
+	// case 5 : unknown role
+	cfg.APIKeys[0].Roles = []string{"SUPERUSER"}
+	_, err = New(cfg, store)
+	assert.NotNil(t, err)
+
+	// case 6 : misconfigured digest
+	cfg.APIKeys[0].Roles = nil
+	cfg.APIKeys[0].KeySHA256 = "not-a-digest"
+	_, err = New(cfg, store)
+	assert.NotNil(t, err)
+}
+
+func TestHS256Authentication(t *testing.T) {
+	cfg := config.Auth{Enabled: true, JWT: config.JWT{HS256Secret: "secret", Issuer: "issuer", Audience: "credit-card"}}
+	authenticator, err := New(cfg, nil)
+	assert.Nil(t, err)
+
+	sign := func(claims jwt.MapClaims, secret string) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		return constants.BearerPrefix + token
+	}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "officer-1", "azp": "risk-console", "iss": "issuer", "aud": "credit-card",
+			"scope": "offers:read offers:decide", "exp": time.Now().Add(time.Minute).Unix(),
+		}
+	}
+
+	// case 1 : valid token
+	principal, err := authenticator.Authenticate(requestContext(constants.Authorization, sign(validClaims(), "secret")))
+	assert.Nil(t, err)
+	assert.Equal(t, "officer-1", principal.Subject)
+	assert.Equal(t, "risk-console", principal.ClientID)
+	assert.Equal(t, constants.AuthMethodJWT, principal.AuthMethod)
+	assert.True(t, principal.HasScope(constants.ScopeOffersDecide))
+
+	// case 2 : wrong secret
+	_, err = authenticator.Authenticate(requestContext(constants.Authorization, sign(validClaims(), "other")))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// case 3 : expired token
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = authenticator.Authenticate(requestContext(constants.Authorization, sign(claims, "secret")))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// case 4 : wrong audience
+	claims = validClaims()
+	claims["aud"] = "other"
+	_, err = authenticator.Authenticate(requestContext(constants.Authorization, sign(claims, "secret")))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// case 5 : unsigned token
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	_, err = authenticator.Authenticate(requestContext(constants.Authorization, constants.BearerPrefix+unsigned))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestRS256JWKSAuthentication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	jwks := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "key-1", "use": "sig",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	content, _ := json.Marshal(jwks)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(jwksFile, content, 0600))
+
+	authenticator, err := New(config.Auth{Enabled: true, JWT: config.JWT{JWKSFile: jwksFile}}, nil)
+	assert.Nil(t, err)
+
+	sign := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "customer-1", "customer_id": "customer-1", "scopes": []string{"offers:read"},
+			"exp": time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = kid
+		signed, _ := token.SignedString(key)
+		return constants.BearerPrefix + signed
+	}
+
+	// case 1 : known key id
+	principal, err := authenticator.Authenticate(requestContext(constants.Authorization, sign("key-1")))
+	assert.Nil(t, err)
+	assert.Equal(t, "customer-1", principal.CustomerID)
+	assert.True(t, principal.HasScope(constants.ScopeOffersRead))
+
+	// case 2 : unknown key id
+	_, err = authenticator.Authenticate(requestContext(constants.Authorization, sign("key-2")))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestClientCertificateAuthentication(t *testing.T) {
+	cfg := config.Auth{
+		Enabled:            true,
+		APIKeys:            []config.APIKey{{ClientID: "back-office", KeySHA256: HashAPIKey("configured-key"), Scopes: []string{constants.ScopeAccountsRead}}},
+		ClientCertificates: []config.ClientCertificate{{CommonName: "offers-batch", Scopes: []string{constants.ScopeOffersWrite}}},
+	}
+	authenticator, err := New(cfg, nil)
+	assert.Nil(t, err)
+	verified := func(ctx *gin.Context, commonName string) *gin.Context {
+		certificate := &x509.Certificate{Subject: pkix.Name{CommonName: commonName, Organization: []string{"bank"}}}
+		ctx.Request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+		return ctx
+	}
+
+	// case 1 : the subject of the verified certificate is the principal
+	principal, err := authenticator.Authenticate(verified(requestContext("", ""), "offers-batch"))
+	assert.Nil(t, err)
+	assert.Equal(t, "offers-batch", principal.ClientID)
+	assert.Equal(t, "CN=offers-batch,O=bank", principal.Subject)
+	assert.Equal(t, constants.AuthMethodClientCert, principal.AuthMethod)
+	assert.True(t, principal.HasScope(constants.ScopeOffersWrite))
+
+	// case 2 : the api key sent along with the certificate takes precedence
+	principal, err = authenticator.Authenticate(verified(requestContext(constants.APIKeyHeader, "configured-key"), "offers-batch"))
+	assert.Nil(t, err)
+	assert.Equal(t, "back-office", principal.ClientID)
+
+	// case 3 : the subject is not configured
+	principal, err = authenticator.Authenticate(verified(requestContext("", ""), "unknown"))
+	assert.Nil(t, err)
+	assert.Nil(t, principal)
+
+	// case 4 : a certificate which was not verified is ignored
+	ctx := requestContext("", "")
+	ctx.Request.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "offers-batch"}}}}
+	principal, err = authenticator.Authenticate(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, principal)
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// clientCertificateAuthenticator identifies the callers by the certificate verified by the tls of the server,
+// the common name of its subject has to be configured
+type clientCertificateAuthenticator struct {
+	principals map[string]models.Principal
+}
+
+func newClientCertificateAuthenticator(certificates []config.ClientCertificate) (*clientCertificateAuthenticator, error) {
+	authenticator := &clientCertificateAuthenticator{principals: map[string]models.Principal{}}
+	for _, certificate := range certificates {
+		if certificate.CommonName == "" {
+			return nil, errors.New("common_name of a client certificate is missing")
+		}
+		if err := validateRoles(certificate.Roles); err != nil {
+			return nil, fmt.Errorf("client certificate of %q has an %w", certificate.CommonName, err)
+		}
+		authenticator.principals[certificate.CommonName] = models.Principal{
+			ClientID:   certificate.CommonName,
+			Scopes:     certificate.Scopes,
+			Roles:      certificate.Roles,
+			AuthMethod: constants.AuthMethodClientCert,
+		}
+	}
+	return authenticator, nil
+}
+
+func (a *clientCertificateAuthenticator) Authenticate(ctx *gin.Context) (*models.Principal, error) {
+	// the chains are only set once the certificate of the client was verified against the client CAs
+	if ctx.Request.TLS == nil || len(ctx.Request.TLS.VerifiedChains) == 0 {
+		return nil, nil
+	}
+	certificate := ctx.Request.TLS.VerifiedChains[0][0]
+	principal, ok := a.principals[certificate.Subject.CommonName]
+	if !ok {
+		return nil, nil
+	}
+	principal.Subject = certificate.Subject.String()
+	return &principal, nil
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claims of the bearer tokens, scopes are read from the space separated "scope" claim and the "scopes" array
+type claims struct {
+	jwt.RegisteredClaims
+	ClientID        string   `json:"client_id"`
+	AuthorizedParty string   `json:"azp"`
+	CustomerID      string   `json:"customer_id"`
+	Scope           string   `json:"scope"`
+	Scopes          []string `json:"scopes"`
+	Roles           []string `json:"roles"`
+}
+
+// jwtAuthenticator verifies "Authorization: Bearer" tokens signed with HS256 or RS256
+type jwtAuthenticator struct {
+	hmacSecret []byte
+	// rsa keys by key id, the key of rs256_public_key_file has an empty key id
+	rsaKeys map[string]*rsa.PublicKey
+	parser  *jwt.Parser
+}
+
+// newJWTAuthenticator returns nil when no verification key is configured
+func newJWTAuthenticator(cfg config.JWT) (*jwtAuthenticator, error) {
+	authenticator := &jwtAuthenticator{rsaKeys: map[string]*rsa.PublicKey{}}
+
+	secret := cfg.HS256Secret
+	if cfg.HS256SecretFile != "" {
+		content, err := os.ReadFile(cfg.HS256SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read hs256_secret_file : %w", err)
+		}
+		secret = strings.TrimSpace(string(content))
+	}
+	if secret != "" {
+		authenticator.hmacSecret = []byte(secret)
+	}
+
+	if cfg.RS256PublicKeyFile != "" {
+		content, err := os.ReadFile(cfg.RS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read rs256_public_key_file : %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse rs256_public_key_file : %w", err)
+		}
+		authenticator.rsaKeys[""] = key
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := readJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		for kid, key := range keys {
+			authenticator.rsaKeys[kid] = key
+		}
+	}
+
+	methods := []string{}
+	if authenticator.hmacSecret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(authenticator.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, nil
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Duration(cfg.Leeway) * time.Second),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	authenticator.parser = jwt.NewParser(options...)
+	return authenticator, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(ctx *gin.Context) (*models.Principal, error) {
+	header := ctx.GetHeader(constants.Authorization)
+	if !strings.HasPrefix(header, constants.BearerPrefix) {
+		return nil, nil
+	}
+
+	var tokenClaims claims
+	_, err := a.parser.ParseWithClaims(strings.TrimPrefix(header, constants.BearerPrefix), &tokenClaims, a.key)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidCredentials, err)
+	}
+
+	if err := validateRoles(tokenClaims.Roles); err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidCredentials, err)
+	}
+
+	principal := &models.Principal{
+		ClientID:   tokenClaims.ClientID,
+		CustomerID: tokenClaims.CustomerID,
+		Subject:    tokenClaims.Subject,
+		Scopes:     append(strings.Fields(tokenClaims.Scope), tokenClaims.Scopes...),
+		Roles:      tokenClaims.Roles,
+		AuthMethod: constants.AuthMethodJWT,
+	}
+	if principal.ClientID == "" {
+		principal.ClientID = tokenClaims.AuthorizedParty
+	}
+	if principal.ClientID == "" {
+		principal.ClientID = tokenClaims.Subject
+	}
+	return principal, nil
+}
+
+// key returns the verification key for the token based on its algorithm and key id
+func (a *jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if a.hmacSecret == nil {
+			return nil, errors.New("hs256 tokens are not accepted")
+		}
+		return a.hmacSecret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := a.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %v", token.Method.Alg())
+	}
+}
+
+// jwks is the json web key set document, only the RSA keys are used
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// readJWKSFile returns the RSA signing keys of the jwks file by key id
+func readJWKSFile(path string) (map[string]*rsa.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read jwks_file : %w", err)
+	}
+
+	var keySet jwks
+	if err := json.Unmarshal(content, &keySet); err != nil {
+		return nil, fmt.Errorf("unable to parse jwks_file : %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(key.N)
+		e, errE := base64.RawURLEncoding.DecodeString(key.E)
+		if errN != nil || errE != nil {
+			return nil, fmt.Errorf("invalid modulus or exponent of key %q in jwks_file", key.Kid)
+		}
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks_file does not contain any RSA signing key")
+	}
+	return keys, nil
+}
@@ -0,0 +1,318 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+)
+
+// csv column names, they are the same as the json field names of the corresponding api
+const (
+	columnCustomerID              = "customer_id"
+	columnEmail                   = "email"
+	columnLocale                  = "locale"
+	columnAccountLimit            = "account_limit"
+	columnPerTransactionLimit     = "per_transaction_limit"
+	columnLastAccountLimit        = "last_account_limit"
+	columnLastPerTransactionLimit = "last_per_transaction_limit"
+	columnID                      = "id"
+	columnAccountID               = "account_id"
+	columnLimitType               = "limit_type"
+	columnNewLimit                = "new_limit"
+	columnOfferActivationTime     = "offer_activation_time"
+	columnOfferExpiryTime         = "offer_expiry_time"
+	columnLimitOfferID            = "limit_offer_id"
+	columnStatus                  = "status"
+	columnDecisionChannel         = "decision_channel"
+	columnDecidedBy               = "decided_by"
+	columnDecidedAt               = "decided_at"
+	columnCreatedAt               = "created_at"
+	columnUpdatedAt               = "updated_at"
+	columnCreatedBy               = "created_by"
+	columnReviewedAt              = "reviewed_at"
+	columnReviewedBy              = "reviewed_by"
+	columnRow                     = "row"
+	columnError                   = "error"
+)
+
+var (
+	accountColumns          = []string{columnAccountLimit, columnPerTransactionLimit, columnLastAccountLimit, columnLastPerTransactionLimit}
+	limitOfferColumns       = []string{columnAccountID, columnLimitType, columnNewLimit, columnOfferActivationTime, columnOfferExpiryTime}
+	limitOfferStatusColumns = []string{columnLimitOfferID, columnStatus, columnDecisionChannel}
+	exportColumns           = []string{columnID, columnAccountID, columnLimitType, columnNewLimit, columnOfferActivationTime, columnOfferExpiryTime, columnStatus,
+		columnCreatedAt, columnUpdatedAt, columnDecidedAt, columnDecidedBy, columnDecisionChannel, columnCreatedBy, columnReviewedAt, columnReviewedBy}
+	reportColumns = []string{columnRow, columnID, columnStatus, columnError}
+
+	ErrEmptyFile = errors.New("csv file has no header row")
+)
+
+// AccountRow is a single account read from a csv file, Err is set when the row could not be parsed
+type AccountRow struct {
+	Line    int
+	Account models.Account
+	Err     error
+}
+
+// LimitOfferRow is a single limit offer read from a csv file, Err is set when the row could not be parsed
+type LimitOfferRow struct {
+	Line       int
+	LimitOffer models.LimitOffer
+	Err        error
+}
+
+// LimitOfferStatusRow is a single offer decision read from a csv file, Err is set when the row could not be parsed
+type LimitOfferStatusRow struct {
+	Line                   int
+	UpdateLimitOfferStatus models.UpdateLimitOfferStatus
+	Err                    error
+}
+
+// record is a csv row whose cells can be looked up by column name
+type record struct {
+	line    int
+	columns map[string]int
+	cells   []string
+}
+
+func (r record) get(column string) string {
+	index, ok := r.columns[column]
+	if !ok || index >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[index])
+}
+
+func (r record) getInt(column string) (*int, error) {
+	value := r.get(column)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%v must be an integer", column)
+	}
+	return &parsed, nil
+}
+
+func (r record) getTime(column string) (*time.Time, error) {
+	value := r.get(column)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%v must be a RFC3339 timestamp", column)
+	}
+	return &parsed, nil
+}
+
+// readRecords reads the header row and makes sure all the required columns are present,
+// the order of the columns does not matter and unknown columns are ignored.
+func readRecords(r io.Reader, required []string) ([]record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%v column is missing", name)
+		}
+	}
+
+	records := []record{}
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record{line: line, columns: columns, cells: cells})
+	}
+	return records, nil
+}
+
+// This function reads the accounts to be created from a csv file
+func ReadAccounts(r io.Reader) ([]AccountRow, error) {
+	records, err := readRecords(r, accountColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]AccountRow, 0, len(records))
+	for _, rec := range records {
+		row := AccountRow{Line: rec.line}
+		row.Account.CustomerID = rec.get(columnCustomerID)
+		row.Account.Locale = rec.get(columnLocale)
+		if email := rec.get(columnEmail); email != "" {
+			row.Account.Email = &email
+		}
+		row.Account.AccountLimit, row.Err = rec.getInt(columnAccountLimit)
+		if row.Err == nil {
+			row.Account.PerTransactionLimit, row.Err = rec.getInt(columnPerTransactionLimit)
+		}
+		if row.Err == nil {
+			row.Account.LastAccountLimit, row.Err = rec.getInt(columnLastAccountLimit)
+		}
+		if row.Err == nil {
+			row.Account.LastPerTransactionLimit, row.Err = rec.getInt(columnLastPerTransactionLimit)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// This function reads the limit offers to be created from a csv file
+func ReadLimitOffers(r io.Reader) ([]LimitOfferRow, error) {
+	records, err := readRecords(r, limitOfferColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]LimitOfferRow, 0, len(records))
+	for _, rec := range records {
+		row := LimitOfferRow{Line: rec.line}
+		if accountID := rec.get(columnAccountID); accountID != "" {
+			row.LimitOffer.AccountID = &accountID
+		}
+		if limitType := models.LimitType(rec.get(columnLimitType)); limitType != "" {
+			row.LimitOffer.LimitType = &limitType
+		}
+		row.LimitOffer.NewLimit, row.Err = rec.getInt(columnNewLimit)
+		if row.Err == nil {
+			row.LimitOffer.OfferActivationTime, row.Err = rec.getTime(columnOfferActivationTime)
+		}
+		if row.Err == nil {
+			row.LimitOffer.OfferExpiryTime, row.Err = rec.getTime(columnOfferExpiryTime)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// This function reads the offer decisions to be applied from a csv file
+func ReadLimitOfferStatuses(r io.Reader) ([]LimitOfferStatusRow, error) {
+	records, err := readRecords(r, limitOfferStatusColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]LimitOfferStatusRow, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, LimitOfferStatusRow{
+			Line: rec.line,
+			UpdateLimitOfferStatus: models.UpdateLimitOfferStatus{
+				LimitOfferID:    rec.get(columnLimitOfferID),
+				Status:          rec.get(columnStatus),
+				DecisionChannel: models.DecisionChannel(rec.get(columnDecisionChannel)),
+				DecidedBy:       rec.get(columnDecidedBy),
+			},
+		})
+	}
+	return rows, nil
+}
+
+// This function writes the per row results of a bulk import as csv
+func WriteReport(w io.Writer, report models.BulkImportReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportColumns); err != nil {
+		return err
+	}
+	for _, result := range report.Results {
+		err := writer.Write([]string{strconv.Itoa(result.Row), result.ID, string(result.Status), result.Error})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// LimitOfferWriter streams limit offers as csv, the header is written before the first offer
+type LimitOfferWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func NewLimitOfferWriter(w io.Writer) *LimitOfferWriter {
+	return &LimitOfferWriter{writer: csv.NewWriter(w)}
+}
+
+func (l *LimitOfferWriter) WriteHeader() error {
+	if l.headerWritten {
+		return nil
+	}
+	l.headerWritten = true
+	return l.writer.Write(exportColumns)
+}
+
+func (l *LimitOfferWriter) Write(offer models.LimitOffer) error {
+	if err := l.WriteHeader(); err != nil {
+		return err
+	}
+	return l.writer.Write([]string{
+		offer.ID,
+		stringValue(offer.AccountID),
+		stringValue((*string)(offer.LimitType)),
+		intValue(offer.NewLimit),
+		timeValue(offer.OfferActivationTime),
+		timeValue(offer.OfferExpiryTime),
+		string(offer.Status),
+		timeValue(offer.CreatedAt),
+		timeValue(offer.UpdatedAt),
+		timeValue(offer.DecidedAt),
+		stringValue(offer.DecidedBy),
+		stringValue((*string)(offer.DecisionChannel)),
+		stringValue(offer.CreatedBy),
+		timeValue(offer.ReviewedAt),
+		stringValue(offer.ReviewedBy),
+	})
+}
+
+// Flush writes any buffered offers to the underlying writer
+func (l *LimitOfferWriter) Flush() error {
+	l.writer.Flush()
+	return l.writer.Error()
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intValue(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func timeValue(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,74 @@
+package bulk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAccounts(t *testing.T) {
+	// case 1 : columns in any order, a non integer value fails only its row
+	input := "last_account_limit,account_limit,per_transaction_limit,last_per_transaction_limit\n" +
+		"1000,2000,500,500\n" +
+		"1000,abc,500,500\n" +
+		"1000,2000,,500\n"
+	rows, err := ReadAccounts(strings.NewReader(input))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rows))
+
+	assert.Nil(t, rows[0].Err)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, 2000, *rows[0].Account.AccountLimit)
+	assert.Equal(t, 1000, *rows[0].Account.LastAccountLimit)
+
+	assert.NotNil(t, rows[1].Err)
+	assert.Equal(t, 3, rows[1].Line)
+
+	// empty cells are left nil so that the validation reports them as missing
+	assert.Nil(t, rows[2].Err)
+	assert.Nil(t, rows[2].Account.PerTransactionLimit)
+
+	// case 2 : required column missing
+	_, err = ReadAccounts(strings.NewReader("account_limit,per_transaction_limit\n1,1\n"))
+	assert.NotNil(t, err)
+
+	// case 3 : empty file
+	_, err = ReadAccounts(strings.NewReader(""))
+	assert.Equal(t, ErrEmptyFile, err)
+}
+
+func TestReadLimitOffers(t *testing.T) {
+	input := "account_id,limit_type,new_limit,offer_activation_time,offer_expiry_time\n" +
+		"f83513e1-f0cb-4a49-85e4-8e9ddb1f3417,ACCOUNT_LIMIT,5000,2023-08-24T02:17:00Z,2023-08-30T02:17:00+05:30\n" +
+		"f83513e1-f0cb-4a49-85e4-8e9ddb1f3417,ACCOUNT_LIMIT,5000,24/08/2023,2023-08-30T02:17:00Z\n"
+	rows, err := ReadLimitOffers(strings.NewReader(input))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	assert.Nil(t, rows[0].Err)
+	assert.Equal(t, models.AccountLimit, *rows[0].LimitOffer.LimitType)
+	assert.Equal(t, time.Date(2023, 8, 24, 2, 17, 0, 0, time.UTC), rows[0].LimitOffer.OfferActivationTime.UTC())
+
+	assert.NotNil(t, rows[1].Err)
+}
+
+func TestWriteReportAndLimitOffers(t *testing.T) {
+	var buffer bytes.Buffer
+	err := WriteReport(&buffer, models.BulkImportReport{Results: []models.BulkRowResult{
+		{Row: 2, ID: "id-1", Status: models.RowSucceeded},
+		{Row: 3, Status: models.RowFailed, Error: "new_limit field is missing"},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, "row,id,status,error\n2,id-1,SUCCEEDED,\n3,,FAILED,new_limit field is missing\n", buffer.String())
+
+	// the header is written even when nothing is exported
+	buffer.Reset()
+	writer := NewLimitOfferWriter(&buffer)
+	assert.Nil(t, writer.WriteHeader())
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, "id,account_id,limit_type,new_limit,offer_activation_time,offer_expiry_time,status,created_at,updated_at,decided_at,decided_by,decision_channel,created_by,reviewed_at,reviewed_by\n", buffer.String())
+}
@@ -1,20 +1,36 @@
 package config
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"os"
+	"regexp"
+	"sync/atomic"
 
-	"github.com/pelletier/go-toml"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
 )
 
 var (
-	globalConfig GlobalConfig
+	// the configuration in use, swapped as a whole by SetConfig and Reload
+	globalConfig atomic.Pointer[GlobalConfig]
 )
 
 // Global Configuration
 type GlobalConfig struct {
-	Database Database `toml:"database"`
-	Server   Server   `toml:"server"`
+	Database      Database      `toml:"database"`
+	Server        Server        `toml:"server"`
+	LimitOffer    LimitOffer    `toml:"limit_offer"`
+	Auth          Auth          `toml:"auth"`
+	Outbox        Outbox        `toml:"outbox"`
+	Webhooks      Webhooks      `toml:"webhooks"`
+	Stream        Stream        `toml:"stream"`
+	Notifications Notifications `toml:"notifications"`
+	Tracing       Tracing       `toml:"tracing"`
+	Logging       Logging       `toml:"logging"`
+	RateLimit     RateLimit     `toml:"rate_limit"`
 }
 
 // DB configuration
@@ -23,7 +39,30 @@ type Database struct {
 	Port     int    `toml:"port"`
 	DBname   string `toml:"dbname"`
 	User     string `toml:"user"`
-	Password string `toml:"password"`
+	Password string `toml:"password" secret:"true"`
+	// the password is read from the file when set, e.g. a mounted secret
+	PasswordFile string `toml:"password_file"`
+	// disable, allow, prefer, require, verify-ca or verify-full, the server certificate is verified against
+	// SSLRootCert, a pem file, by the last two
+	SSLMode     string `toml:"ssl_mode"`
+	SSLRootCert string `toml:"ssl_root_cert"`
+	// name of the connections in pg_stat_activity
+	ApplicationName string `toml:"application_name"`
+	// size of the connection pool, 0 leaves the open connections unlimited
+	MaxOpenConns int `toml:"max_open_conns"`
+	MaxIdleConns int `toml:"max_idle_conns"`
+	// seconds a connection is used, and kept idle, before it is closed, 0 keeps it forever
+	ConnMaxLifetime int `toml:"conn_max_lifetime"`
+	ConnMaxIdleTime int `toml:"conn_max_idle_time"`
+	// milliseconds a statement may run before the database cancels it, 0 lets it run
+	StatementTimeout int `toml:"statement_timeout"`
+	// seconds an attempt to connect waits for the database
+	ConnectTimeout int `toml:"connect_timeout"`
+	// the connection is attempted ConnectAttempts times at startup, waiting ConnectRetryBackoff seconds after the
+	// first failure, doubled after every one up to MaxConnectRetryBackoff
+	ConnectAttempts        int `toml:"connect_attempts"`
+	ConnectRetryBackoff    int `toml:"connect_retry_backoff"`
+	MaxConnectRetryBackoff int `toml:"max_connect_retry_backoff"`
 }
 
 // server configuration
@@ -31,34 +70,666 @@ type Server struct {
 	Address      string `toml:"address"`
 	ReadTimeOut  int    `toml:"read_time_out"`
 	WriteTimeOut int    `toml:"write_time_out"`
+	// address of the grpc api, it is not served when empty
+	GRPCAddress string `toml:"grpc_address"`
+	// address of the prometheus metrics, they are served on the address of the http api when empty
+	MetricsAddress string `toml:"metrics_address"`
+	// seconds the readiness probe waits for the database
+	ReadinessTimeout int `toml:"readiness_timeout"`
+	// seconds the readiness probe fails before the server is shut down, so that the traffic is drained first
+	DrainDelay int `toml:"drain_delay"`
+	// seconds between the checks of the config files, which are reloaded once they change as on a SIGHUP,
+	// 0 reloads them on a SIGHUP only
+	ConfigReloadInterval int `toml:"config_reload_interval"`
+	// addresses or cidr ranges of the proxies whose X-Forwarded-For header gives the ip of the client, e.g. of the
+	// load balancer, none are trusted when empty and the ip of the client is the one of the connection
+	TrustedProxies []string `toml:"trusted_proxies"`
+	TLS            TLS      `toml:"tls"`
+}
+
+// TLS of the http api
+type TLS struct {
+	// the http api is served over https when enabled
+	Enabled  bool   `toml:"enabled"`
+	CertFile string `toml:"cert_file"`
+	KeyFile  string `toml:"key_file"`
+	// "1.2" or "1.3"
+	MinVersion string `toml:"min_version"`
+	// names of the TLS 1.2 cipher suites, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, the defaults of go when empty
+	CipherSuites []string `toml:"cipher_suites"`
+	// "none", "optional" verifies the certificate of the clients sending one, "require" rejects the others,
+	// the certificates are verified against the pem bundle of ClientCAFile
+	ClientAuth   string `toml:"client_auth"`
+	ClientCAFile string `toml:"client_ca_file"`
+	// seconds between the checks of the files, which are read again once they change, 0 disables the reload
+	ReloadInterval int `toml:"reload_interval"`
+}
+
+// limit offer configuration
+type LimitOffer struct {
+	// what happens when an offer is created while a PENDING offer exists for the same account and limit type
+	DuplicatePolicy string `toml:"duplicate_policy" reload:"true"`
+	// offers with a new limit above the amount or increasing the current limit by more than the percentage
+	// await the approval of a second back-office user, 0 disables the respective threshold
+	ApprovalThresholdAmount     int     `toml:"approval_threshold_amount" reload:"true"`
+	ApprovalThresholdPercentage float64 `toml:"approval_threshold_percentage" reload:"true"`
+	// seconds between the sweeps marking the PENDING offers past their expiry time EXPIRED, 0 disables the sweeps
+	ExpirySweepInterval int `toml:"expiry_sweep_interval"`
+}
+
+// configuration of the relay publishing the domain events of the outbox table
+type Outbox struct {
+	Enabled bool `toml:"enabled"`
+	// "log" appends the events to File (the application log when empty), "webhook" posts them to WebhookURL
+	Publisher      string `toml:"publisher"`
+	File           string `toml:"file"`
+	WebhookURL     string `toml:"webhook_url"`
+	WebhookTimeout int    `toml:"webhook_timeout"`
+	// milliseconds between the polls of the outbox table when it was found empty
+	PollInterval int `toml:"poll_interval"`
+	BatchSize    int `toml:"batch_size"`
+	// seconds to wait before retrying a failed event, doubled with every attempt up to MaxRetryBackoff
+	RetryBackoff    int `toml:"retry_backoff"`
+	MaxRetryBackoff int `toml:"max_retry_backoff"`
+	// seconds a relay keeps the events it publishes from the other relays, it has to outlast the publish of a batch
+	LeaseDuration int `toml:"lease_duration"`
+	// seconds a published event is kept before it is purged, the relay purges them every PurgeInterval seconds
+	Retention     int `toml:"retention"`
+	PurgeInterval int `toml:"purge_interval"`
+}
+
+// authentication configuration of the /v1 routes
+type Auth struct {
+	// when disabled every request is served as an anonymous principal holding all the scopes
+	Enabled bool `toml:"enabled"`
+	// api keys are looked up in the api_key table in addition to the ones listed below
+	APIKeysFromDB bool     `toml:"api_keys_from_db"`
+	APIKeys       []APIKey `toml:"api_keys"`
+	JWT           JWT      `toml:"jwt"`
+	// the clients whose certificate is verified by the tls of the server, see Server.TLS
+	ClientCertificates []ClientCertificate `toml:"client_certificates"`
+}
+
+// client identified by the common name of the subject of its certificate
+type ClientCertificate struct {
+	CommonName string   `toml:"common_name"`
+	Scopes     []string `toml:"scopes"`
+	Roles      []string `toml:"roles"`
+}
+
+// api key whose sha256 hex digest is stored instead of the key itself
+type APIKey struct {
+	ClientID   string   `toml:"client_id"`
+	CustomerID string   `toml:"customer_id"`
+	KeySHA256  string   `toml:"key_sha256" secret:"true"`
+	Scopes     []string `toml:"scopes"`
+	Roles      []string `toml:"roles"`
+}
+
+// keys used to verify the bearer tokens, HS256 and RS256 can be enabled together
+type JWT struct {
+	HS256Secret        string `toml:"hs256_secret" secret:"true"`
+	HS256SecretFile    string `toml:"hs256_secret_file"`
+	RS256PublicKeyFile string `toml:"rs256_public_key_file"`
+	JWKSFile           string `toml:"jwks_file"`
+	Issuer             string `toml:"issuer"`
+	Audience           string `toml:"audience"`
+	// allowed clock difference in seconds while checking exp and nbf
+	Leeway int `toml:"leeway"`
 }
 
 // Setter method for GlobalConfig
 func SetConfig(cfg GlobalConfig) {
-	globalConfig = cfg
+	globalConfig.Store(&cfg)
 }
 
-// Getter method for GlobalConfig
+// Getter method for GlobalConfig, the configuration may be reloaded meanwhile so a setting read twice
+// while serving a request should be read from the same returned value
 func GetConfig() GlobalConfig {
-	return globalConfig
+	if cfg := globalConfig.Load(); cfg != nil {
+		return *cfg
+	}
+	return GlobalConfig{}
 }
 
-// Loading the values from default.toml and assigning them as part of GlobalConfig struct
-func InitGlobalConfig() error {
-	config, err := toml.LoadFile("./../config/defaults.toml")
-	fmt.Println("Err : ", err)
+// InitGlobalConfig loads the files, each overriding the keys set by the ones before it, applies the overrides
+// of the environment and validates the result
+func InitGlobalConfig(files []string) error {
+	appConfig, err := Load(files, os.LookupEnv)
 	if err != nil {
-		log.Printf("Error while loading defaults.toml file : %v ", err)
 		return err
 	}
+	SetConfig(appConfig)
+	return nil
+}
 
-	var appConfig GlobalConfig
-	err = config.Unmarshal(&appConfig)
-	if err != nil {
-		log.Printf("Error while unmarshalling config : %v", err)
+// validate checks every section of the configuration and applies the defaults of the unset values
+func validate(appConfig *GlobalConfig) error {
+	if err := validateDatabase(&appConfig.Database); err != nil {
+		log.Printf("Invalid database config : %v", err)
 		return err
 	}
 
-	SetConfig(appConfig)
+	if err := validateServer(&appConfig.Server); err != nil {
+		log.Printf("Invalid server config : %v", err)
+		return err
+	}
+
+	switch appConfig.LimitOffer.DuplicatePolicy {
+	case "":
+		appConfig.LimitOffer.DuplicatePolicy = constants.SupersedePolicy
+	case constants.SupersedePolicy, constants.RejectDuplicatePolicy, constants.AllowMultiplePolicy:
+	default:
+		log.Printf("Invalid limit_offer.duplicate_policy : %v", appConfig.LimitOffer.DuplicatePolicy)
+		return fmt.Errorf("invalid limit_offer.duplicate_policy %q", appConfig.LimitOffer.DuplicatePolicy)
+	}
+
+	if appConfig.LimitOffer.ApprovalThresholdAmount < 0 || appConfig.LimitOffer.ApprovalThresholdPercentage < 0 {
+		log.Printf("Invalid limit_offer approval thresholds : %v, %v", appConfig.LimitOffer.ApprovalThresholdAmount, appConfig.LimitOffer.ApprovalThresholdPercentage)
+		return errors.New("limit_offer approval thresholds can not be negative")
+	}
+
+	if err := validateOutbox(&appConfig.Outbox); err != nil {
+		log.Printf("Invalid outbox config : %v", err)
+		return err
+	}
+
+	if err := validateWebhooks(&appConfig.Webhooks, appConfig.Outbox); err != nil {
+		log.Printf("Invalid webhooks config : %v", err)
+		return err
+	}
+
+	validateStream(&appConfig.Stream)
+
+	if err := validateNotifications(&appConfig.Notifications, appConfig.Outbox); err != nil {
+		log.Printf("Invalid notifications config : %v", err)
+		return err
+	}
+
+	if err := validateTracing(&appConfig.Tracing); err != nil {
+		log.Printf("Invalid tracing config : %v", err)
+		return err
+	}
+
+	if err := validateLogging(&appConfig.Logging); err != nil {
+		log.Printf("Invalid logging config : %v", err)
+		return err
+	}
+
+	if err := validateRateLimit(&appConfig.RateLimit); err != nil {
+		log.Printf("Invalid rate_limit config : %v", err)
+		return err
+	}
+	return nil
+}
+
+// validateDatabase checks that the database to connect to is set and applies the defaults of the unset values
+func validateDatabase(database *Database) error {
+	for _, required := range []struct{ key, value string }{{"host", database.Host}, {"dbname", database.DBname}, {"user", database.User}} {
+		if required.value == "" {
+			return fmt.Errorf("database.%v is required", required.key)
+		}
+	}
+	if database.Port <= 0 || database.Port > 65535 {
+		return fmt.Errorf("invalid database.port %v", database.Port)
+	}
+	switch database.SSLMode {
+	case "":
+		database.SSLMode = "prefer"
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		return fmt.Errorf("invalid database.ssl_mode %q", database.SSLMode)
+	}
+	if database.ApplicationName == "" {
+		database.ApplicationName = "credit-card-offer-limit"
+	}
+	for _, setting := range []struct {
+		key   string
+		value int
+	}{
+		{"max_open_conns", database.MaxOpenConns}, {"max_idle_conns", database.MaxIdleConns},
+		{"conn_max_lifetime", database.ConnMaxLifetime}, {"conn_max_idle_time", database.ConnMaxIdleTime},
+		{"statement_timeout", database.StatementTimeout}, {"connect_timeout", database.ConnectTimeout},
+		{"connect_attempts", database.ConnectAttempts}, {"connect_retry_backoff", database.ConnectRetryBackoff},
+		{"max_connect_retry_backoff", database.MaxConnectRetryBackoff},
+	} {
+		if setting.value < 0 {
+			return fmt.Errorf("database.%v can not be negative", setting.key)
+		}
+	}
+	if database.ConnectTimeout == 0 {
+		database.ConnectTimeout = 5
+	}
+	if database.ConnectAttempts == 0 {
+		database.ConnectAttempts = 1
+	}
+	if database.ConnectRetryBackoff == 0 {
+		database.ConnectRetryBackoff = 1
+	}
+	if database.MaxConnectRetryBackoff < database.ConnectRetryBackoff {
+		database.MaxConnectRetryBackoff = database.ConnectRetryBackoff
+	}
+	return nil
+}
+
+// validateServer checks the address and the probe settings of the server and applies the defaults of the unset values
+func validateServer(server *Server) error {
+	if server.Address == "" {
+		return errors.New("server.address is required")
+	}
+	if server.ReadinessTimeout < 0 || server.DrainDelay < 0 {
+		return errors.New("server readiness_timeout and drain_delay can not be negative")
+	}
+	if server.ConfigReloadInterval < 0 {
+		return errors.New("server.config_reload_interval can not be negative")
+	}
+	for _, proxy := range server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid server.trusted_proxies %q, an ip address or a cidr range is expected", proxy)
+		}
+	}
+	if server.ReadinessTimeout == 0 {
+		server.ReadinessTimeout = 2
+	}
+	return validateTLS(&server.TLS)
+}
+
+// validateTLS checks the files, the versions and the cipher suites of the tls and applies the defaults of the unset values
+func validateTLS(cfg *TLS) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return errors.New("server.tls cert_file and key_file are required")
+	}
+	switch cfg.MinVersion {
+	case "":
+		cfg.MinVersion = "1.2"
+	case "1.2", "1.3":
+	default:
+		return fmt.Errorf("invalid server.tls.min_version %q", cfg.MinVersion)
+	}
+	for _, name := range cfg.CipherSuites {
+		known := false
+		for _, suite := range tls.CipherSuites() {
+			known = known || suite.Name == name
+		}
+		if !known {
+			return fmt.Errorf("invalid server.tls cipher suite %q", name)
+		}
+	}
+	switch cfg.ClientAuth {
+	case "":
+		cfg.ClientAuth = constants.NoClientAuth
+	case constants.NoClientAuth:
+	case constants.OptionalClientAuth, constants.RequireClientAuth:
+		if cfg.ClientCAFile == "" {
+			return fmt.Errorf("server.tls.client_ca_file is required by the %q client_auth", cfg.ClientAuth)
+		}
+	default:
+		return fmt.Errorf("invalid server.tls.client_auth %q", cfg.ClientAuth)
+	}
+	if cfg.ReloadInterval < 0 {
+		return errors.New("server.tls.reload_interval can not be negative")
+	}
+	return nil
+}
+
+// configuration of the delivery of the outbox events to the webhook subscriptions
+type Webhooks struct {
+	// the events reach the subscriptions through the outbox relay, so the outbox has to be enabled as well
+	Enabled bool `toml:"enabled"`
+	// seconds a subscription has to respond
+	Timeout int `toml:"timeout"`
+	// milliseconds between the polls of the due deliveries when there was none
+	PollInterval int `toml:"poll_interval"`
+	BatchSize    int `toml:"batch_size"`
+	// seconds to wait before retrying a failed delivery, doubled with every attempt up to MaxRetryBackoff
+	RetryBackoff    int `toml:"retry_backoff"`
+	MaxRetryBackoff int `toml:"max_retry_backoff"`
+	// a delivery failing MaxAttempts times is dead-lettered
+	MaxAttempts int `toml:"max_attempts"`
+	// seconds a dispatcher keeps the deliveries it posts from the other dispatchers, by default long enough
+	// for a batch whose every delivery times out
+	LeaseDuration int `toml:"lease_duration"`
+}
+
+// configuration of the server-sent events stream of the limit offer events of an account
+type Stream struct {
+	Enabled bool `toml:"enabled"`
+	// seconds the events are retained to resume a stream from its Last-Event-ID, up to RetentionSize events
+	Retention     int `toml:"retention"`
+	RetentionSize int `toml:"retention_size"`
+	// seconds between the heartbeats keeping idle streams open
+	HeartbeatInterval int `toml:"heartbeat_interval"`
+	// events buffered for a stream, a stream falling further behind is closed and has to resume
+	SubscriberBuffer int `toml:"subscriber_buffer"`
+}
+
+// configuration of the notifications sent to the customers about their offers
+type Notifications struct {
+	// the offer created and limit changed notifications come through the outbox relay, so the outbox has to be enabled as well
+	Enabled bool `toml:"enabled"`
+	// "log" appends the messages to File (the application log when empty), "smtp" sends them as emails
+	Notifier     string `toml:"notifier"`
+	File         string `toml:"file"`
+	SMTPHost     string `toml:"smtp_host"`
+	SMTPPort     int    `toml:"smtp_port"`
+	SMTPUsername string `toml:"smtp_username"`
+	SMTPPassword string `toml:"smtp_password" secret:"true"`
+	// the smtp password is read from the file when set, e.g. a mounted secret
+	SMTPPasswordFile string `toml:"smtp_password_file"`
+	SMTPFrom         string `toml:"smtp_from"`
+	// seconds a message has to be sent in, from the dial to the quit
+	SMTPTimeout int `toml:"smtp_timeout"`
+	// locale of the accounts created without one and of the accounts whose locale has no templates
+	DefaultLocale string `toml:"default_locale"`
+	// a reminder is sent when a PENDING offer gets within each of these numbers of days of its expiry
+	ExpiryReminderDays []int `toml:"expiry_reminder_days"`
+	// seconds between the scans for the offers due for a reminder
+	ReminderInterval int `toml:"reminder_interval"`
+	// milliseconds between the polls of the queued notifications when there was none
+	PollInterval int `toml:"poll_interval"`
+	BatchSize    int `toml:"batch_size"`
+	// seconds to wait before retrying a failed notification, doubled with every attempt up to MaxRetryBackoff
+	RetryBackoff    int `toml:"retry_backoff"`
+	MaxRetryBackoff int `toml:"max_retry_backoff"`
+	// a notification failing MaxAttempts times is given up
+	MaxAttempts int `toml:"max_attempts"`
+	// seconds a dispatcher keeps the notifications it sends from the other dispatchers, by default long enough
+	// for a batch whose every message times out
+	LeaseDuration int `toml:"lease_duration"`
+}
+
+// validateOutbox checks the publisher of the outbox and applies the defaults of the unset values
+func validateOutbox(outbox *Outbox) error {
+	switch outbox.Publisher {
+	case "":
+		outbox.Publisher = constants.LogPublisher
+	case constants.LogPublisher:
+	case constants.WebhookPublisher:
+		if outbox.WebhookURL == "" {
+			return errors.New("outbox.webhook_url is required by the webhook publisher")
+		}
+	default:
+		return fmt.Errorf("invalid outbox.publisher %q", outbox.Publisher)
+	}
+
+	defaults := []struct {
+		value  *int
+		orElse int
+	}{
+		{&outbox.WebhookTimeout, 10},
+		{&outbox.PollInterval, 1000},
+		{&outbox.BatchSize, 100},
+		{&outbox.RetryBackoff, 1},
+		{&outbox.MaxRetryBackoff, 300},
+		{&outbox.LeaseDuration, 60},
+		{&outbox.Retention, 604800},
+		{&outbox.PurgeInterval, 3600},
+	}
+	for _, d := range defaults {
+		if *d.value <= 0 {
+			*d.value = d.orElse
+		}
+	}
+	return nil
+}
+
+// validateWebhooks checks the webhooks can be fed by the outbox and applies the defaults of the unset values
+func validateWebhooks(webhooks *Webhooks, outbox Outbox) error {
+	if webhooks.Enabled && !outbox.Enabled {
+		return errors.New("webhooks.enabled requires outbox.enabled")
+	}
+
+	defaults := []struct {
+		value  *int
+		orElse int
+	}{
+		{&webhooks.Timeout, 10},
+		{&webhooks.PollInterval, 1000},
+		{&webhooks.BatchSize, 50},
+		{&webhooks.RetryBackoff, 5},
+		{&webhooks.MaxRetryBackoff, 3600},
+		{&webhooks.MaxAttempts, 10},
+	}
+	for _, d := range defaults {
+		if *d.value <= 0 {
+			*d.value = d.orElse
+		}
+	}
+	if webhooks.LeaseDuration <= 0 {
+		webhooks.LeaseDuration = webhooks.BatchSize*webhooks.Timeout + 60
+	}
+	return nil
+}
+
+// validateStream applies the defaults of the unset values of the stream
+func validateStream(stream *Stream) {
+	defaults := []struct {
+		value  *int
+		orElse int
+	}{
+		{&stream.Retention, 300},
+		{&stream.RetentionSize, 10000},
+		{&stream.HeartbeatInterval, 15},
+		{&stream.SubscriberBuffer, 64},
+	}
+	for _, d := range defaults {
+		if *d.value <= 0 {
+			*d.value = d.orElse
+		}
+	}
+}
+
+// validateNotifications checks the notifier and the reminders and applies the defaults of the unset values
+func validateNotifications(notifications *Notifications, outbox Outbox) error {
+	if notifications.Enabled && !outbox.Enabled {
+		return errors.New("notifications.enabled requires outbox.enabled")
+	}
+	switch notifications.Notifier {
+	case "":
+		notifications.Notifier = constants.LogPublisher
+	case constants.LogPublisher:
+	case constants.SMTPNotifier:
+		if notifications.SMTPHost == "" || notifications.SMTPFrom == "" {
+			return errors.New("notifications.smtp_host and notifications.smtp_from are required by the smtp notifier")
+		}
+	default:
+		return fmt.Errorf("invalid notifications.notifier %q", notifications.Notifier)
+	}
+	for _, days := range notifications.ExpiryReminderDays {
+		if days <= 0 {
+			return fmt.Errorf("invalid notifications.expiry_reminder_days %v, the days should be positive", notifications.ExpiryReminderDays)
+		}
+	}
+	if notifications.DefaultLocale == "" {
+		notifications.DefaultLocale = "en"
+	}
+
+	defaults := []struct {
+		value  *int
+		orElse int
+	}{
+		{&notifications.SMTPPort, 587},
+		{&notifications.SMTPTimeout, 30},
+		{&notifications.ReminderInterval, 300},
+		{&notifications.PollInterval, 1000},
+		{&notifications.BatchSize, 50},
+		{&notifications.RetryBackoff, 30},
+		{&notifications.MaxRetryBackoff, 3600},
+		{&notifications.MaxAttempts, 5},
+	}
+	for _, d := range defaults {
+		if *d.value <= 0 {
+			*d.value = d.orElse
+		}
+	}
+	if notifications.LeaseDuration <= 0 {
+		notifications.LeaseDuration = notifications.BatchSize*notifications.SMTPTimeout + 60
+	}
+	return nil
+}
+
+// configuration of the OpenTelemetry spans of the http requests, the grpc calls, the service methods and the sql statements
+type Tracing struct {
+	// "none" disables the spans, "otlp" exports them to Endpoint over grpc and "stdout" writes them as json
+	// to File (the standard output when empty)
+	Exporter string `toml:"exporter"`
+	Endpoint string `toml:"endpoint"`
+	// the spans are exported over plaintext grpc instead of tls
+	Insecure    bool   `toml:"insecure"`
+	File        string `toml:"file"`
+	ServiceName string `toml:"service_name"`
+	// share of the traces started by the application which are sampled, the traces continued from
+	// a traceparent header follow the sampling decision of the caller
+	SampleRatio float64 `toml:"sample_ratio"`
+}
+
+// validateTracing checks the exporter and the sample ratio of the tracing and applies the defaults of the unset values
+func validateTracing(tracing *Tracing) error {
+	switch tracing.Exporter {
+	case "":
+		tracing.Exporter = constants.NoExporter
+	case constants.NoExporter, constants.StdoutExporter:
+	case constants.OTLPExporter:
+		if tracing.Endpoint == "" {
+			return errors.New("tracing.endpoint is required by the otlp exporter")
+		}
+	default:
+		return fmt.Errorf("invalid tracing.exporter %q", tracing.Exporter)
+	}
+	if tracing.SampleRatio < 0 || tracing.SampleRatio > 1 {
+		return fmt.Errorf("invalid tracing.sample_ratio %v, the ratio should be between 0 and 1", tracing.SampleRatio)
+	}
+	if tracing.ServiceName == "" {
+		tracing.ServiceName = "credit-card-offer-limit"
+	}
+	return nil
+}
+
+// configuration of the application log
+type Logging struct {
+	// debug, info, warn or error
+	Level string `toml:"level" reload:"true"`
+	// "json" writes an object per entry, "console" a human readable line
+	Format string `toml:"format"`
+	// the entries are appended to the file, to the standard error when empty
+	File string `toml:"file"`
+	// the first SamplingInitial entries with the same level and message within a second are logged and then every
+	// SamplingThereafter-th of them (none when 0), a SamplingInitial of 0 disables the sampling
+	SamplingInitial    int `toml:"sampling_initial"`
+	SamplingThereafter int `toml:"sampling_thereafter"`
+	// an entry is logged for every http request once it is served
+	AccessLog bool `toml:"access_log"`
+	// the json bodies of the request and of the response are added to the access log, redacted
+	LogBodies bool `toml:"log_bodies" reload:"true"`
+	// the personal data removed from the entries before they are written, the default rules apply when none is set
+	Redaction []RedactionRule `toml:"redaction"`
+}
+
+// RedactionRule redacts the log field and the json key of the logged bodies named Field, or the text of the
+// messages and of the string fields matching Pattern
+type RedactionRule struct {
+	Field   string `toml:"field"`
+	Pattern string `toml:"pattern"`
+	// drop, mask or truncate
+	Action string `toml:"action"`
+	// the characters left by mask, at the end, and by truncate, at the start
+	Keep int `toml:"keep"`
+}
+
+// defaultRedaction masks the customer ids, drops the names and the emails and truncates the account ids
+var defaultRedaction = []RedactionRule{
+	{Field: "customer_id", Action: constants.MaskRedaction, Keep: 4},
+	{Field: "account_id", Action: constants.TruncateRedaction, Keep: 8},
+	{Field: "name", Action: constants.DropRedaction},
+	{Field: "email", Action: constants.DropRedaction},
+	{Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Action: constants.DropRedaction},
+}
+
+// validateLogging checks the level and the format of the log and applies the defaults of the unset values
+func validateLogging(logging *Logging) error {
+	switch logging.Level {
+	case "":
+		logging.Level = "info"
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("invalid logging.level %q", logging.Level)
+	}
+	switch logging.Format {
+	case "":
+		logging.Format = constants.JSONLogFormat
+	case constants.JSONLogFormat, constants.ConsoleLogFormat:
+	default:
+		return fmt.Errorf("invalid logging.format %q", logging.Format)
+	}
+	if logging.SamplingInitial < 0 || logging.SamplingThereafter < 0 {
+		return errors.New("logging sampling can not be negative")
+	}
+	if len(logging.Redaction) == 0 {
+		logging.Redaction = defaultRedaction
+	}
+	for _, rule := range logging.Redaction {
+		if (rule.Field == "") == (rule.Pattern == "") {
+			return errors.New("a logging.redaction rule needs either a field or a pattern")
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("invalid logging.redaction pattern %q : %w", rule.Pattern, err)
+			}
+		}
+		switch rule.Action {
+		case constants.DropRedaction, constants.MaskRedaction, constants.TruncateRedaction:
+		default:
+			return fmt.Errorf("invalid logging.redaction action %q", rule.Action)
+		}
+		if rule.Keep < 0 {
+			return errors.New("logging.redaction keep can not be negative")
+		}
+	}
+	return nil
+}
+
+// configuration of the token buckets limiting the rate of the /v1 requests
+type RateLimit struct {
+	// the requests beyond the limits are rejected with 429 when enabled
+	Enabled bool `toml:"enabled" reload:"true"`
+	// the limits of the routes, a request is rejected once it exceeds any of the limits of its route, or of "*"
+	// when its route has none
+	Limits []RouteLimit `toml:"limits" reload:"true"`
+}
+
+// RouteLimit allows every client, ip or account Rate requests per second to the route, with bursts of up to Burst requests
+type RouteLimit struct {
+	// name of the route following /v1/, e.g. create_limit_offer, "*" for the routes without limits of their own
+	Route string `toml:"route"`
+	// "client" limits each authenticated principal, "ip" each remote address and "account" each account_id
+	// of the path or of the json body, the requests without an account are not limited by it
+	Key   string  `toml:"key"`
+	Rate  float64 `toml:"rate"`
+	Burst int     `toml:"burst"`
+}
+
+// validateRateLimit checks the limits and applies the defaults of the unset bursts
+func validateRateLimit(rateLimit *RateLimit) error {
+	for i := range rateLimit.Limits {
+		limit := &rateLimit.Limits[i]
+		if limit.Route == "" {
+			return errors.New("rate_limit.limits route is required")
+		}
+		switch limit.Key {
+		case constants.ClientRateLimitKey, constants.IPRateLimitKey, constants.AccountRateLimitKey:
+		default:
+			return fmt.Errorf("invalid rate_limit.limits key %q of route %v", limit.Key, limit.Route)
+		}
+		if limit.Rate <= 0 || limit.Burst < 0 {
+			return fmt.Errorf("rate_limit.limits of route %v should have a positive rate and burst", limit.Route)
+		}
+		// a bucket holds at least the token of one request
+		if limit.Burst == 0 {
+			limit.Burst = 1
+		}
+	}
 	return nil
 }
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// EnvPrefix starts the names of the environment variables overriding the keys, e.g. CCLO_DATABASE_PASSWORD
+// overrides the password of the [database] section
+const EnvPrefix = "CCLO"
+
+// fileSuffix ends the names of the environment variables naming the file a key is read from, e.g.
+// CCLO_DATABASE_PASSWORD_FILE
+const fileSuffix = "_FILE"
+
+// masked replaces the secrets printed by Masked
+const masked = "********"
+
+// DefaultFiles are the locations of defaults.toml tried when no file is given, from the root of the repository
+// and from cmd/
+var DefaultFiles = []string{"config/defaults.toml", "./../config/defaults.toml"}
+
+// Files returns the files to load: the given ones, or those of the CCLO_CONFIG variable, comma separated, or the
+// first of DefaultFiles which exists, followed by the <environment>.toml file next to the first of them when an
+// environment is given
+func Files(files []string, environment string, lookupEnv func(string) (string, bool)) ([]string, error) {
+	if len(files) == 0 {
+		if value, ok := lookupEnv(EnvPrefix + "_CONFIG"); ok && value != "" {
+			files = strings.Split(value, ",")
+		}
+	}
+	if len(files) == 0 {
+		for _, file := range DefaultFiles {
+			if _, err := os.Stat(file); err == nil {
+				files = []string{file}
+				break
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config file given and none of %v exists", DefaultFiles)
+	}
+	if environment != "" {
+		files = append(files, filepath.Join(filepath.Dir(files[0]), environment+".toml"))
+	}
+	return files, nil
+}
+
+// Load reads the files, each overriding the keys set by the ones before it, then applies the overrides of the
+// environment variables returned by lookupEnv, reads the secret files and validates the result
+func Load(files []string, lookupEnv func(string) (string, bool)) (GlobalConfig, error) {
+	var appConfig GlobalConfig
+	if len(files) == 0 {
+		return appConfig, errors.New("no config file to load")
+	}
+
+	var merged *toml.Tree
+	for _, file := range files {
+		tree, err := toml.LoadFile(file)
+		if err != nil {
+			log.Printf("Error while loading %v file : %v ", file, err)
+			return appConfig, fmt.Errorf("unable to load %v : %w", file, err)
+		}
+		// a misspelt key would otherwise be ignored and its default silently used
+		if err := checkKeys(tree, reflect.TypeOf(appConfig), ""); err != nil {
+			return appConfig, fmt.Errorf("%v : %w", file, err)
+		}
+		if merged == nil {
+			merged = tree
+			continue
+		}
+		mergeTree(merged, tree)
+	}
+
+	if err := merged.Unmarshal(&appConfig); err != nil {
+		log.Printf("Error while unmarshalling config : %v", err)
+		return appConfig, err
+	}
+	if err := applyEnvironment(reflect.ValueOf(&appConfig).Elem(), EnvPrefix, lookupEnv); err != nil {
+		return appConfig, err
+	}
+	if err := readSecretFiles(&appConfig); err != nil {
+		return appConfig, err
+	}
+	if err := validate(&appConfig); err != nil {
+		return appConfig, err
+	}
+	return appConfig, nil
+}
+
+// mergeTree sets the keys of overlay in base, the tables are merged key by key while the arrays,
+// including the arrays of tables, are replaced as a whole
+func mergeTree(base, overlay *toml.Tree) {
+	for _, key := range overlay.Keys() {
+		value := overlay.GetPath([]string{key})
+		if overlayTable, ok := value.(*toml.Tree); ok {
+			if baseTable, ok := base.GetPath([]string{key}).(*toml.Tree); ok {
+				mergeTree(baseTable, overlayTable)
+				continue
+			}
+		}
+		base.SetPath([]string{key}, value)
+	}
+}
+
+// tomlFields returns the fields of the struct by their toml key
+func tomlFields(structType reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if key := strings.Split(field.Tag.Get("toml"), ",")[0]; key != "" && key != "-" {
+			fields[key] = field
+		}
+	}
+	return fields
+}
+
+// checkKeys returns an error naming the first key of the tree which is not a field of the struct
+func checkKeys(tree *toml.Tree, structType reflect.Type, prefix string) error {
+	fields := tomlFields(structType)
+	for _, key := range tree.Keys() {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown key %v%v", prefix, key)
+		}
+		switch value := tree.GetPath([]string{key}).(type) {
+		case *toml.Tree:
+			if field.Type.Kind() != reflect.Struct {
+				return fmt.Errorf("%v%v is not a table", prefix, key)
+			}
+			if err := checkKeys(value, field.Type, prefix+key+"."); err != nil {
+				return err
+			}
+		case []*toml.Tree:
+			if field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() != reflect.Struct {
+				return fmt.Errorf("%v%v is not an array of tables", prefix, key)
+			}
+			for _, table := range value {
+				if err := checkKeys(table, field.Type.Elem(), prefix+key+"."); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// applyEnvironment sets every field of the struct whose variable, the prefix followed by the upper case toml key,
+// is set, or whose _FILE variable names a file, to its value. The arrays of tables can only be set in the files.
+func applyEnvironment(value reflect.Value, prefix string, lookupEnv func(string) (string, bool)) error {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		key := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(key)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnvironment(value.Field(i), name, lookupEnv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		variable, ok := lookupEnv(name)
+		if file, fromFile := lookupEnv(name + fileSuffix); fromFile && file != "" {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("unable to read %v%v : %w", name, fileSuffix, err)
+			}
+			variable, ok = strings.TrimSpace(string(content)), true
+		}
+		if !ok {
+			continue
+		}
+		if err := setField(value.Field(i), variable); err != nil {
+			return fmt.Errorf("invalid %v %q : %w", name, variable, err)
+		}
+	}
+	return nil
+}
+
+// setField parses the text into the field, the lists are comma separated
+func setField(field reflect.Value, text string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(parsed))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Struct {
+			return errors.New("an array of tables can only be set in a config file")
+		}
+		items := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			element := reflect.New(field.Type().Elem()).Elem()
+			if err := setField(element, item); err != nil {
+				return err
+			}
+			items = reflect.Append(items, element)
+		}
+		field.Set(items)
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+	return nil
+}
+
+// readSecretFiles reads the secrets whose file is set, the files of the jwt keys are read by the authenticator
+func readSecretFiles(appConfig *GlobalConfig) error {
+	for _, secret := range []struct {
+		key    string
+		file   string
+		target *string
+	}{
+		{"database.password_file", appConfig.Database.PasswordFile, &appConfig.Database.Password},
+		{"notifications.smtp_password_file", appConfig.Notifications.SMTPPasswordFile, &appConfig.Notifications.SMTPPassword},
+	} {
+		if secret.file == "" {
+			continue
+		}
+		content, err := os.ReadFile(secret.file)
+		if err != nil {
+			return fmt.Errorf("unable to read %v : %w", secret.key, err)
+		}
+		*secret.target = strings.TrimSpace(string(content))
+	}
+	return nil
+}
+
+// Masked returns a copy of the configuration whose secrets, the fields tagged secret, are masked
+func Masked(cfg GlobalConfig) GlobalConfig {
+	maskSecrets(reflect.ValueOf(&cfg).Elem())
+	return cfg
+}
+
+func maskSecrets(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field, structField := value.Field(i), value.Type().Field(i)
+		switch {
+		case structField.Tag.Get("secret") == "true" && field.Kind() == reflect.String && field.String() != "":
+			field.SetString(masked)
+		case field.Kind() == reflect.Struct:
+			maskSecrets(field)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Struct:
+			// the elements are copied so that the configuration in use keeps its secrets
+			elements := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			reflect.Copy(elements, field)
+			for j := 0; j < elements.Len(); j++ {
+				maskSecrets(elements.Index(j))
+			}
+			field.Set(elements)
+		}
+	}
+}
+
+// Print writes the configuration in the toml format with its secrets masked
+func Print(cfg GlobalConfig) ([]byte, error) {
+	return toml.Marshal(Masked(cfg))
+}
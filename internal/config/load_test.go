@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const defaultsFile = "../../config/defaults.toml"
+
+// environment returns the lookup of the variables
+func environment(variables map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	production := writeFile(t, dir, "production.toml", `
+[database]
+host = "db.internal"
+
+[server]
+drain_delay = 15
+
+[[auth.api_keys]]
+client_id = "back-office"
+key_sha256 = "3f1c"
+scopes = ["accounts:read"]
+`)
+	passwordFile := writeFile(t, dir, "password", "s3cret\n")
+
+	cfg, err := Load([]string{defaultsFile, production}, environment(map[string]string{
+		"CCLO_DATABASE_USER":                      "app",
+		"CCLO_DATABASE_PASSWORD_FILE":             passwordFile,
+		"CCLO_SERVER_READINESS_TIMEOUT":           "4",
+		"CCLO_OUTBOX_ENABLED":                     "true",
+		"CCLO_NOTIFICATIONS_EXPIRY_REMINDER_DAYS": "7, 1",
+	}))
+	assert.NoError(t, err)
+	// the keys of the override are set, the others keep the value of defaults.toml
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, 15, cfg.Server.DrainDelay)
+	assert.Equal(t, "0.0.0.0:8080", cfg.Server.Address)
+	assert.Equal(t, "back-office", cfg.Auth.APIKeys[0].ClientID)
+	// the environment overrides the files
+	assert.Equal(t, "app", cfg.Database.User)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, 4, cfg.Server.ReadinessTimeout)
+	assert.True(t, cfg.Outbox.Enabled)
+	assert.Equal(t, []int{7, 1}, cfg.Notifications.ExpiryReminderDays)
+
+	// the printed config has its secrets masked, the loaded one keeps them
+	printed, err := Print(cfg)
+	assert.NoError(t, err)
+	assert.Contains(t, string(printed), `password = "********"`)
+	assert.Contains(t, string(printed), `key_sha256 = "********"`)
+	assert.NotContains(t, string(printed), "s3cret")
+	assert.Equal(t, "3f1c", cfg.Auth.APIKeys[0].KeySHA256)
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	user := map[string]string{"CCLO_DATABASE_USER": "app"}
+
+	_, err := Load([]string{defaultsFile}, environment(nil))
+	assert.EqualError(t, err, "database.user is required")
+
+	misspelt := writeFile(t, dir, "misspelt.toml", "[server]\ndrain_dealy = 15\n")
+	_, err = Load([]string{defaultsFile, misspelt}, environment(user))
+	assert.EqualError(t, err, misspelt+" : unknown key server.drain_dealy")
+
+	_, err = Load([]string{defaultsFile}, environment(map[string]string{"CCLO_DATABASE_USER": "app", "CCLO_DATABASE_PORT": "postgres"}))
+	assert.ErrorContains(t, err, `invalid CCLO_DATABASE_PORT "postgres"`)
+
+	_, err = Load([]string{defaultsFile, filepath.Join(dir, "missing.toml")}, environment(user))
+	assert.Error(t, err)
+}
+
+func TestFiles(t *testing.T) {
+	files, err := Files([]string{"/etc/cclo/defaults.toml", "/etc/cclo/local.toml"}, "production", environment(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/etc/cclo/defaults.toml", "/etc/cclo/local.toml", "/etc/cclo/production.toml"}, files)
+
+	files, err = Files(nil, "", environment(map[string]string{"CCLO_CONFIG": "a.toml,b.toml"}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.toml", "b.toml"}, files)
+}
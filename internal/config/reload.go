@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// reloadMu serializes the reloads so that none of them swaps a configuration built from a stale one
+var reloadMu sync.Mutex
+
+// Reload loads the files as Load does and swaps the configuration in use for a copy whose reloadable settings,
+// the fields tagged reload, have their loaded value. It returns the keys of the reloadable settings which changed
+// and of the other settings which changed, those need a restart and keep their value. The configuration in use is
+// kept when the files are invalid.
+func Reload(files []string, lookupEnv func(string) (string, bool)) (applied []string, rejected []string, err error) {
+	loaded, err := Load(files, lookupEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	updated := GetConfig()
+	applied, rejected = reloadFields(reflect.ValueOf(&updated).Elem(), reflect.ValueOf(loaded), "")
+	SetConfig(updated)
+	return applied, rejected, nil
+}
+
+// reloadFields sets the reloadable fields of current which differ from loaded to their loaded value and returns
+// their keys along with the keys of the other fields which differ
+func reloadFields(current, loaded reflect.Value, prefix string) (applied []string, rejected []string) {
+	for i := 0; i < current.NumField(); i++ {
+		field := current.Type().Field(i)
+		key := prefix + strings.Split(field.Tag.Get("toml"), ",")[0]
+		if field.Type.Kind() == reflect.Struct {
+			fieldApplied, fieldRejected := reloadFields(current.Field(i), loaded.Field(i), key+".")
+			applied, rejected = append(applied, fieldApplied...), append(rejected, fieldRejected...)
+			continue
+		}
+		if reflect.DeepEqual(current.Field(i).Interface(), loaded.Field(i).Interface()) {
+			continue
+		}
+		if field.Tag.Get("reload") != "true" {
+			rejected = append(rejected, key)
+			continue
+		}
+		current.Field(i).Set(loaded.Field(i))
+		applied = append(applied, key)
+	}
+	return applied, rejected
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	user := environment(map[string]string{"CCLO_DATABASE_USER": "app"})
+	override := writeFile(t, dir, "override.toml", "[limit_offer]\napproval_threshold_amount = 1000\n")
+	files := []string{defaultsFile, override}
+	cfg, err := Load(files, user)
+	assert.NoError(t, err)
+	SetConfig(cfg)
+
+	// the reloadable settings are swapped while the structural ones keep their value
+	writeFile(t, dir, "override.toml", `
+[server]
+address = "0.0.0.0:9000"
+
+[limit_offer]
+approval_threshold_amount = 5000
+duplicate_policy = "reject_duplicate"
+
+[logging]
+level = "debug"
+`)
+	applied, rejected, err := Reload(files, user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"limit_offer.duplicate_policy", "limit_offer.approval_threshold_amount", "logging.level"}, applied)
+	assert.Equal(t, []string{"server.address"}, rejected)
+	assert.Equal(t, 5000, GetConfig().LimitOffer.ApprovalThresholdAmount)
+	assert.Equal(t, "reject_duplicate", GetConfig().LimitOffer.DuplicatePolicy)
+	assert.Equal(t, "debug", GetConfig().Logging.Level)
+	assert.Equal(t, "0.0.0.0:8080", GetConfig().Server.Address)
+
+	// an invalid file keeps the configuration in use
+	writeFile(t, dir, "override.toml", "[limit_offer]\napproval_threshold_amount = -1\n")
+	_, _, err = Reload(files, user)
+	assert.Error(t, err)
+	assert.Equal(t, 5000, GetConfig().LimitOffer.ApprovalThresholdAmount)
+
+	// nothing changed
+	writeFile(t, dir, "override.toml", "[limit_offer]\napproval_threshold_amount = 5000\nduplicate_policy = \"reject_duplicate\"\n\n[logging]\nlevel = \"debug\"\n")
+	applied, rejected, err = Reload(files, user)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Empty(t, rejected)
+}
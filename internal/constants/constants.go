@@ -7,6 +7,30 @@ const (
 	CreateLimitOffer       = "create_limit_offer"
 	ListActiveLimitOffers  = "list_active_limit_offers"
 	UpdateLimitOfferStatus = "update_limit_offer_status"
+	ImportAccounts         = "import_accounts"
+	ImportLimitOffers      = "import_limit_offers"
+	ImportLimitOfferStatus = "import_limit_offer_statuses"
+	ExportLimitOffers      = "export_limit_offers"
+	GetLimitOffer          = "get_limit_offer"
+	ListLimitOffers        = "list_limit_offers"
+	CancelLimitOffer       = "cancel_limit_offer"
+	RevertAccountLimit     = "revert_account_limit"
+	ApproveLimitOffer      = "approve_limit_offer"
+	DeclineLimitOffer      = "decline_limit_offer"
+	ListAuditEvents        = "audit_events"
+	CreateWebhookSub       = "create_webhook_subscription"
+	ListWebhookSubs        = "list_webhook_subscriptions"
+	ListWebhookDeliveries  = "list_webhook_deliveries"
+	RedeliverWebhook       = "redeliver_webhook_delivery"
+	StreamLimitOfferEvents = "stream_limit_offer_events"
+	OpenAPIDocument        = "openapi.json"
+	APIDocs                = "docs"
+	Metrics                = "metrics"
+	Healthz                = "healthz"
+	Readyz                 = "readyz"
+	SubscriptionID         = "subscription_id"
+	DeliveryID             = "delivery_id"
+	LimitOfferID           = "limit_offer_id"
 	AccountID              = "account_id"
 	Colon                  = ":"
 	EmptyString            = ""
@@ -20,10 +44,137 @@ const (
 	InvalidBodyCreateAccount          = "invalid create account request body"
 	InvalidBodyCreateLimitOffer       = "invalid create limit offer request body"
 	InvalidBodyUpdateLimitOfferStatus = "invalid update limit offer status request body"
+	InvalidCSVBody                    = "invalid csv request body"
+	InvalidExportLimitOffersQuery     = "invalid export limit offers query params"
+	InvalidListLimitOffersQuery       = "invalid list limit offers query params"
+	InvalidCursor                     = "invalid value for cursor"
+	InvalidBodyRevertAccountLimit     = "invalid revert account limit request body"
+	InvalidBodyWebhookSubscription    = "invalid webhook subscription request body"
+	InvalidSubscriptionID             = "invalid value for subscription id"
+	InvalidDeliveryID                 = "invalid value for delivery id"
+	InvalidListWebhookDeliveriesQuery = "invalid list webhook deliveries query params"
+
+	// sorting and pagination of limit offer listing
+	SortByCreatedAt           = "created_at"
+	SortByOfferActivationTime = "offer_activation_time"
+	SortByOfferExpiryTime     = "offer_expiry_time"
+	SortByNewLimit            = "new_limit"
+	SortOrderAsc              = "asc"
+	SortOrderDesc             = "desc"
+	DefaultPageSize           = 20
+	MaxPageSize               = 100
+
+	// policies for creating an offer while a PENDING offer exists for the same account and limit type
+	SupersedePolicy       = "supersede"
+	RejectDuplicatePolicy = "reject_duplicate"
+	AllowMultiplePolicy   = "allow_multiple"
+
+	// authentication
+	Principal            = "principal"
+	APIKeyHeader         = "X-API-Key"
+	BearerPrefix         = "Bearer "
+	WWWAuthenticate      = "WWW-Authenticate"
+	Unauthorized         = "missing or invalid credentials"
+	Forbidden            = "insufficient scope for the request"
+	AnonymousClientID    = "anonymous"
+	ScopeAccountsRead    = "accounts:read"
+	ScopeAccountsWrite   = "accounts:write"
+	ScopeOffersRead      = "offers:read"
+	ScopeOffersWrite     = "offers:write"
+	ScopeOffersDecide    = "offers:decide"
+	ScopeOffersApprove   = "offers:approve"
+	ScopeAuditRead       = "audit:read"
+	ScopeWebhooksManage  = "webhooks:manage"
+	AuthMethodAPIKey     = "api_key"
+	AuthMethodJWT        = "jwt"
+	AuthMethodAnonymous  = "anonymous"
+	AuthMethodSystem     = "system"
+	AuthMethodClientCert = "client_certificate"
+	SystemClientID       = "system"
+
+	// rate limiting
+	ClientRateLimitKey  = "client"
+	IPRateLimitKey      = "ip"
+	AccountRateLimitKey = "account"
+	AllRoutes           = "*"
+	RetryAfter          = "Retry-After"
+	TooManyRequests     = "too many requests, retry later"
+
+	// audit log
+	AuditEntityAccount          = "account"
+	AuditEntityLimitOffer       = "limit_offer"
+	AuditAccountCreated         = "account.created"
+	AuditAccountLimitUpdated    = "account.limit_updated"
+	AuditAccountLimitReverted   = "account.limit_reverted"
+	AuditLimitOfferCreated      = "limit_offer.created"
+	AuditLimitOfferSuperseded   = "limit_offer.superseded"
+	AuditLimitOfferStatusUpdate = "limit_offer.status_updated"
+	AuditLimitOfferCancelled    = "limit_offer.cancelled"
+	AuditLimitOfferReviewed     = "limit_offer.reviewed"
+	AuditLimitOfferExpired      = "limit_offer.expired"
+	AuditEntityWebhookSub       = "webhook_subscription"
+	AuditEntityWebhookDelivery  = "webhook_delivery"
+	AuditWebhookSubCreated      = "webhook_subscription.created"
+	AuditWebhookRedelivered     = "webhook_delivery.redelivered"
+	InvalidListAuditEventsQuery = "invalid list audit events query params"
+
+	// domain events published through the outbox
+	EventLimitOfferCreated    = "limit_offer.created"
+	EventLimitOfferAccepted   = "limit_offer.accepted"
+	EventLimitOfferRejected   = "limit_offer.rejected"
+	EventLimitOfferExpired    = "limit_offer.expired"
+	EventLimitOfferSuperseded = "limit_offer.superseded"
+	EventLimitOfferCancelled  = "limit_offer.cancelled"
+	EventLimitOfferApproved   = "limit_offer.approved"
+	EventLimitOfferDeclined   = "limit_offer.declined"
+	LogPublisher              = "log"
+	WebhookPublisher          = "webhook"
+	EventIDHeader             = "X-Event-ID"
+	EventTypeHeader           = "X-Event-Type"
+	DeliveryIDHeader          = "X-Delivery-ID"
+	SignatureHeader           = "X-Signature"
+	WebhookSecretPrefix       = "whsec_"
+	OutboxNotifyChannel       = "outbox_events"
+	SMTPNotifier              = "smtp"
+	LastEventIDHeader         = "Last-Event-ID"
+	TextEventStream           = "text/event-stream"
+	EventResync               = "resync"
+
+	// exporters of the spans
+	NoExporter     = "none"
+	OTLPExporter   = "otlp"
+	StdoutExporter = "stdout"
+
+	// formats of the application log and the fields of its entries
+	JSONLogFormat    = "json"
+	ConsoleLogFormat = "console"
+	LogAccountID     = "log_account_id"
+	LogRoute         = "log_route"
+
+	// verification of the client certificates by the tls of the server
+	NoClientAuth       = "none"
+	OptionalClientAuth = "optional"
+	RequireClientAuth  = "require"
+
+	// actions of the redaction rules of the log
+	DropRedaction     = "drop"
+	MaskRedaction     = "mask"
+	TruncateRedaction = "truncate"
+
+	// roles, checked by the service layer for every operation
+	RoleCustomer     = "CUSTOMER"
+	RoleRiskOfficer  = "RISK_OFFICER"
+	RoleAdmin        = "ADMIN"
+	RoleNotPermitted = "principal is not permitted to %v"
+	AccountNotOwned  = "account does not belong to the customer"
 
 	//http
 	Accept          = "Accept"
 	ContentType     = "Content-Type"
 	Authorization   = "Authorization"
 	ApplicationJSON = "application/json"
+	TextCSV         = "text/csv"
+
+	ContentDisposition = "Content-Disposition"
+	CSVFormField       = "file"
 )
@@ -4,64 +4,66 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
 	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
 	"github.com/ankit/project/credit-card-offer-limit/internal/models"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-func (p postgres) CreateAccount(ctx *gin.Context, accountInfo models.Account) *limitoffererror.CreditCardError {
-	txid := ctx.Request.Header.Get(constants.TransactionID)
+// columns of account in the order expected by scanAccount
+const accountColumns = `account_id, customer_id, account_limit, per_transaction_limit, last_account_limit,
+	last_per_transaction_limit, account_limit_update_time, per_transaction_limit_update_time, email, locale`
+
+func scanAccount(row rowScanner) (models.Account, error) {
+	var account models.Account
+	err := row.Scan(
+		&account.AccountID,
+		&account.CustomerID,
+		&account.AccountLimit,
+		&account.PerTransactionLimit,
+		&account.LastAccountLimit,
+		&account.LastPerTransactionLimit,
+		&account.AccountLimitUpdateTime,
+		&account.PerTransactionLimitUpdateTime,
+		&account.Email,
+		&account.Locale,
+	)
+	return account, err
+}
 
+func (p postgres) CreateAccount(ctx *gin.Context, accountInfo models.Account) *limitoffererror.CreditCardError {
 	query := `
 			INSERT INTO account(account_id, customer_id, account_limit, per_transaction_limit, last_account_limit, 
-			last_per_transaction_limit, account_limit_update_time, per_transaction_limit_update_time) 
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8)`
-
-	_, err := p.db.Exec(query, accountInfo.AccountID, accountInfo.CustomerID, accountInfo.AccountLimit,
-		accountInfo.PerTransactionLimit, accountInfo.LastAccountLimit, accountInfo.LastPerTransactionLimit,
-		accountInfo.AccountLimitUpdateTime, accountInfo.PerTransactionLimitUpdateTime)
+			last_per_transaction_limit, account_limit_update_time, per_transaction_limit_update_time, email, locale) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING ` + accountColumns
 
-	if err != nil {
-		utils.Logger.Error(fmt.Sprintf("error while running insert query, txid : %v", txid))
-		if strings.Contains(err.Error(), "duplicate key value") {
-			return &limitoffererror.CreditCardError{
-				Trace:   ctx.Request.Header.Get(constants.TransactionID),
-				Code:    http.StatusBadRequest,
-				Message: "account already added",
-			}
-		}
-		return &limitoffererror.CreditCardError{
-			Trace:   txid,
-			Code:    http.StatusInternalServerError,
-			Message: "unable to add account info",
+	err := p.runInTx(ctx, "unable to add account info", func(tx *sql.Tx) error {
+		createdAccount, err := scanAccount(tx.QueryRowContext(ctx.Request.Context(), query, accountInfo.AccountID, accountInfo.CustomerID, accountInfo.AccountLimit,
+			accountInfo.PerTransactionLimit, accountInfo.LastAccountLimit, accountInfo.LastPerTransactionLimit,
+			accountInfo.AccountLimitUpdateTime, accountInfo.PerTransactionLimitUpdateTime, accountInfo.Email, accountInfo.Locale))
+		if err != nil {
+			return failure("unable to add account info", err)
 		}
+		return appendAuditEvent(ctx, tx, constants.AuditAccountCreated, constants.AuditEntityAccount, createdAccount.AccountID, nil, createdAccount)
+	})
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while running insert query", zap.Error(err))
+		return err
 	}
-	utils.Logger.Info(fmt.Sprintf("successfully added the account entry in db, txid : %v", txid))
+	utils.RequestLogger(ctx).Info("successfully added the account entry in db")
 	return nil
 }
 
 func (p postgres) GetAccount(ctx *gin.Context, accountID string) (models.Account, *limitoffererror.CreditCardError) {
 	txid := ctx.Request.Header.Get(constants.TransactionID)
 
-	scannedAccount := models.Account{}
-
-	query := `SELECT * FROM account WHERE account_id=$1`
-	row := p.db.QueryRow(query, accountID)
-
-	err := row.Scan(
-		&scannedAccount.AccountID,
-		&scannedAccount.CustomerID,
-		&scannedAccount.AccountLimit,
-		&scannedAccount.PerTransactionLimit,
-		&scannedAccount.LastAccountLimit,
-		&scannedAccount.LastPerTransactionLimit,
-		&scannedAccount.AccountLimitUpdateTime,
-		&scannedAccount.PerTransactionLimitUpdateTime,
-	)
+	query := `SELECT ` + accountColumns + ` FROM account WHERE account_id=$1`
+	scannedAccount, err := scanAccount(p.db.QueryRowContext(ctx.Request.Context(), query, accountID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Handle case where no rows were found
@@ -72,14 +74,82 @@ func (p postgres) GetAccount(ctx *gin.Context, accountID string) (models.Account
 			}
 		}
 
-		utils.Logger.Error(fmt.Sprintf("error while scanning account from db, txid : %v, error: %v", txid, err))
-		return scannedAccount, &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "unable to get the account",
+		utils.RequestLogger(ctx).Error("error while scanning account from db", zap.Error(err))
+		return scannedAccount, translateError(txid, err, "unable to get the account")
+	}
+
+	utils.RequestLogger(ctx).Info("successfully fetched account from db")
+	return scannedAccount, nil
+}
+
+// RevertAccountLimit swaps the current and the last value of the limit, so that a revert can itself be reverted.
+// The customer is not notified: a revert is a back-office correction recorded in the audit log only, while the
+// notifications are queued from the outbox events of the offers and render the offer which changed the limit.
+func (p postgres) RevertAccountLimit(ctx *gin.Context, revertAccountLimit models.RevertAccountLimit) (models.Account, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	var accountInfo models.Account
+	err := p.runInTx(ctx, "error while reverting account limit", func(tx *sql.Tx) error {
+		query := `SELECT ` + accountColumns + ` FROM account WHERE account_id = $1 FOR UPDATE`
+		before, err := scanAccount(tx.QueryRowContext(ctx.Request.Context(), query, revertAccountLimit.AccountID))
+		accountInfo = before
+		if err == sql.ErrNoRows {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "account not found",
+				Trace:   txid,
+			}
+		}
+		if err != nil {
+			return failure("error while fetching account", err)
+		}
+
+		noPreviousLimit := &limitoffererror.CreditCardError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "account has no previous limit to revert to",
 			Trace:   txid,
 		}
+		now := time.Now().UTC()
+		if revertAccountLimit.LimitType == models.AccountLimit {
+			if accountInfo.LastAccountLimit == nil {
+				return noPreviousLimit
+			}
+			accountInfo.AccountLimit, accountInfo.LastAccountLimit = accountInfo.LastAccountLimit, accountInfo.AccountLimit
+			accountInfo.AccountLimitUpdateTime = now
+		} else {
+			if accountInfo.LastPerTransactionLimit == nil {
+				return noPreviousLimit
+			}
+			accountInfo.PerTransactionLimit, accountInfo.LastPerTransactionLimit = accountInfo.LastPerTransactionLimit, accountInfo.PerTransactionLimit
+			accountInfo.PerTransactionLimitUpdateTime = now
+		}
+
+		if accountInfo.AccountLimit != nil && accountInfo.PerTransactionLimit != nil && *accountInfo.PerTransactionLimit > *accountInfo.AccountLimit {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: "per transaction limit can not be greater than account limit after the revert",
+				Trace:   txid,
+			}
+		}
+
+		accountInfo, err = scanAccount(tx.QueryRowContext(ctx.Request.Context(), `
+			UPDATE account
+			SET account_limit = $1, last_account_limit = $2, account_limit_update_time = $3,
+				per_transaction_limit = $4, last_per_transaction_limit = $5, per_transaction_limit_update_time = $6
+			WHERE account_id = $7
+			RETURNING `+accountColumns,
+			accountInfo.AccountLimit, accountInfo.LastAccountLimit, accountInfo.AccountLimitUpdateTime,
+			accountInfo.PerTransactionLimit, accountInfo.LastPerTransactionLimit, accountInfo.PerTransactionLimitUpdateTime,
+			accountInfo.AccountID))
+		if err != nil {
+			return failure("unable to update the account limit info in db", err)
+		}
+		return appendAuditEvent(ctx, tx, constants.AuditAccountLimitReverted, constants.AuditEntityAccount, accountInfo.AccountID, before, accountInfo)
+	})
+	if err != nil {
+		return models.Account{}, err
 	}
 
-	utils.Logger.Info(fmt.Sprintf("successfully fetched account from db, txid : %v", txid))
-	return scannedAccount, nil
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("successfully reverted the %v of account", revertAccountLimit.LimitType))
+	return accountInfo, nil
 }
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetAPIKeyPrincipal returns the principal owning the api key with the given sha256 hex digest, revoked keys are not found
+func (p postgres) GetAPIKeyPrincipal(ctx *gin.Context, keySHA256 string) (models.Principal, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	var principal models.Principal
+	var customerID sql.NullString
+	var scopes, roles string
+	query := `SELECT client_id, customer_id, scopes, roles FROM api_key WHERE key_sha256 = $1 AND revoked_at IS NULL`
+	err := p.db.QueryRowContext(ctx.Request.Context(), query, keySHA256).Scan(&principal.ClientID, &customerID, &scopes, &roles)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return principal, &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "api key not found",
+				Trace:   txid,
+			}
+		}
+
+		utils.RequestLogger(ctx).Error("error while fetching api key from db", zap.Error(err))
+		return principal, translateError(txid, err, "unable to get the api key")
+	}
+
+	principal.CustomerID = customerID.String
+	principal.Subject = principal.ClientID
+	principal.Scopes = strings.Fields(scopes)
+	principal.Roles = strings.Fields(roles)
+	principal.AuthMethod = constants.AuthMethodAPIKey
+	return principal, nil
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/audit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// key of the transaction level advisory lock which serializes the appends to the audit log,
+// so that every event is chained to the one committed before it
+const auditLogLockKey = 7305128
+
+// columns of audit_log in the order expected by scanAuditEvent
+const auditEventColumns = `seq, transaction_id, client_id, subject, action, entity_type, entity_id, before, after,
+	created_at, prev_hash, hash`
+
+func scanAuditEvent(row rowScanner) (models.AuditEvent, error) {
+	var event models.AuditEvent
+	var before, after []byte
+	err := row.Scan(&event.Seq, &event.TransactionID, &event.ClientID, &event.Subject, &event.Action, &event.EntityType,
+		&event.EntityID, &before, &after, &event.CreatedAt, &event.PrevHash, &event.Hash)
+	event.Before = before
+	event.After = after
+	return event, err
+}
+
+// appendAuditEvent records the change of the entity within the transaction making the change, before is nil
+// for a created entity. The principal and the transaction id of the request are recorded along with the change.
+func appendAuditEvent(ctx *gin.Context, tx *sql.Tx, action string, entityType string, entityID string, before interface{}, after interface{}) error {
+	principal, _ := utils.GetPrincipal(ctx)
+	event := models.AuditEvent{
+		TransactionID: ctx.Request.Header.Get(constants.TransactionID),
+		ClientID:      principal.ClientID,
+		Subject:       principal.Subject,
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      entityID,
+		CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	var err error
+	if before != nil {
+		if event.Before, err = json.Marshal(before); err != nil {
+			return failure("unable to record the audit event", err)
+		}
+	}
+	if after != nil {
+		if event.After, err = json.Marshal(after); err != nil {
+			return failure("unable to record the audit event", err)
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx.Request.Context(), `SELECT pg_advisory_xact_lock($1)`, auditLogLockKey); err != nil {
+		return failure("unable to lock the audit log", err)
+	}
+	err = tx.QueryRowContext(ctx.Request.Context(), `SELECT hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&event.PrevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return failure("unable to read the last audit event", err)
+	}
+	if err = tx.QueryRowContext(ctx.Request.Context(), `SELECT nextval(pg_get_serial_sequence('audit_log', 'seq'))`).Scan(&event.Seq); err != nil {
+		return failure("unable to record the audit event", err)
+	}
+	event.Hash = audit.Hash(event)
+
+	_, err = tx.ExecContext(ctx.Request.Context(), `
+		INSERT INTO audit_log(`+auditEventColumns+`)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		event.Seq, event.TransactionID, event.ClientID, event.Subject, event.Action, event.EntityType, event.EntityID,
+		nullableJSON(event.Before), nullableJSON(event.After), event.CreatedAt, event.PrevHash, event.Hash)
+	if err != nil {
+		return failure("unable to record the audit event", err)
+	}
+	return nil
+}
+
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+// ListAuditEvents returns a page of the audit events matching the filter in the order they were recorded
+func (p postgres) ListAuditEvents(ctx *gin.Context, filter models.AuditEventFilter) (models.AuditEventPage, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	page := models.AuditEventPage{AuditEvents: []models.AuditEvent{}}
+
+	conditions := []string{}
+	args := []interface{}{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.EntityType != constants.EmptyString {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != constants.EmptyString {
+		add("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Action != constants.EmptyString {
+		add("action = $%d", filter.Action)
+	}
+	if filter.ClientID != constants.EmptyString {
+		add("client_id = $%d", filter.ClientID)
+	}
+	if filter.TransactionID != constants.EmptyString {
+		add("transaction_id = $%d", filter.TransactionID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at <= $%d", *filter.To)
+	}
+	if filter.Cursor != constants.EmptyString {
+		afterSeq, err := decodeAuditCursor(filter.Cursor)
+		if err != nil {
+			return page, &limitoffererror.CreditCardError{
+				Code:    http.StatusBadRequest,
+				Message: constants.InvalidCursor,
+				Trace:   txid,
+			}
+		}
+		add("seq > $%d", afterSeq)
+	}
+
+	args = append(args, filter.PageSize+1)
+	query := `SELECT ` + auditEventColumns + ` FROM audit_log` + whereClause(conditions) +
+		fmt.Sprintf(` ORDER BY seq LIMIT $%d`, len(args))
+	rows, err := p.db.QueryContext(ctx.Request.Context(), query, args...)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while listing audit events", zap.Error(err))
+		return page, translateError(txid, err, "unable to list the audit events")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return page, translateError(txid, err, "error scanning audit event rows")
+		}
+		page.AuditEvents = append(page.AuditEvents, event)
+	}
+	if err := rows.Err(); err != nil {
+		return page, translateError(txid, err, "error scanning audit event rows")
+	}
+
+	if len(page.AuditEvents) > filter.PageSize {
+		page.AuditEvents = page.AuditEvents[:filter.PageSize]
+		page.NextCursor = encodeAuditCursor(page.AuditEvents[filter.PageSize-1].Seq)
+	}
+	return page, nil
+}
+
+// StreamAuditEvents calls fn with every audit event in the order they were recorded
+func (p postgres) StreamAuditEvents(ctx *gin.Context, fn func(models.AuditEvent) error) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	rows, err := p.db.QueryContext(ctx.Request.Context(), `SELECT `+auditEventColumns+` FROM audit_log ORDER BY seq`)
+	if err != nil {
+		return translateError(txid, err, "unable to read the audit log")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return translateError(txid, err, "error scanning audit event rows")
+		}
+		if err := fn(event); err != nil {
+			return translateError(txid, err, "unable to process the audit event")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return translateError(txid, err, "error scanning audit event rows")
+	}
+	return nil
+}
+
+func encodeAuditCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+func decodeAuditCursor(cursor string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(decoded), 10, 64)
+}
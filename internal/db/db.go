@@ -1,20 +1,29 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/ankit/project/credit-card-offer-limit/internal/config"
 	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
 	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
 )
 
 var (
-	conn *sql.DB
-	once sync.Once
+	conn    *sql.DB
+	connErr error
+	once    sync.Once
 )
 
 type postgres struct{ db *sql.DB }
@@ -22,37 +31,117 @@ type postgres struct{ db *sql.DB }
 type CreditCardLimitOfferService interface {
 	CreateAccount(*gin.Context, models.Account) *limitoffererror.CreditCardError
 	GetAccount(*gin.Context, string) (models.Account, *limitoffererror.CreditCardError)
-	CreateLimitOffer(*gin.Context, models.LimitOffer, bool) *limitoffererror.CreditCardError
+	RevertAccountLimit(*gin.Context, models.RevertAccountLimit) (models.Account, *limitoffererror.CreditCardError)
+	CreateLimitOffer(*gin.Context, models.LimitOffer, string) *limitoffererror.CreditCardError
 	ListActiveLimitOffers(*gin.Context, models.ActiveLimitOffer) ([]models.LimitOffer, *limitoffererror.CreditCardError)
 	UpdateLimitOfferStatus(*gin.Context, models.UpdateLimitOfferStatus) *limitoffererror.CreditCardError
-	IsLimitOfferExists(*gin.Context, models.LimitOffer) (bool, string, *limitoffererror.CreditCardError)
+	CancelLimitOffer(*gin.Context, string, string) *limitoffererror.CreditCardError
+	ReviewLimitOffer(*gin.Context, models.ReviewLimitOffer, string) *limitoffererror.CreditCardError
 	GetLimitOffer(*gin.Context, string) (models.LimitOffer, *limitoffererror.CreditCardError)
+	ListLimitOffers(*gin.Context, models.ListLimitOffers) (models.LimitOfferPage, *limitoffererror.CreditCardError)
+	ExportLimitOffers(*gin.Context, models.LimitOfferFilter, func(models.LimitOffer) error) *limitoffererror.CreditCardError
+	GetAPIKeyPrincipal(*gin.Context, string) (models.Principal, *limitoffererror.CreditCardError)
+	ListAuditEvents(*gin.Context, models.AuditEventFilter) (models.AuditEventPage, *limitoffererror.CreditCardError)
+	StreamAuditEvents(*gin.Context, func(models.AuditEvent) error) *limitoffererror.CreditCardError
+	ExpireLimitOffers(*gin.Context) ([]models.LimitOffer, *limitoffererror.CreditCardError)
+	RelayOutboxEvents(*gin.Context, int, time.Duration, func(models.OutboxEvent) error, func(int) time.Duration) (int, *limitoffererror.CreditCardError)
+	PurgeOutboxEvents(*gin.Context, time.Duration, int) (int, *limitoffererror.CreditCardError)
+	CreateWebhookSubscription(*gin.Context, models.WebhookSubscription) (models.WebhookSubscription, *limitoffererror.CreditCardError)
+	ListWebhookSubscriptions(*gin.Context) ([]models.WebhookSubscription, *limitoffererror.CreditCardError)
+	EnqueueWebhookDeliveries(*gin.Context, models.OutboxEvent, []byte) *limitoffererror.CreditCardError
+	DispatchWebhookDeliveries(*gin.Context, int, int, time.Duration, func(models.WebhookDelivery, models.WebhookSubscription) models.WebhookDeliveryAttempt, func(int) time.Duration) (int, *limitoffererror.CreditCardError)
+	ListWebhookDeliveries(*gin.Context, models.WebhookDeliveryFilter) ([]models.WebhookDelivery, *limitoffererror.CreditCardError)
+	RedeliverWebhookDelivery(*gin.Context, string) (models.WebhookDelivery, *limitoffererror.CreditCardError)
+	EnqueueNotification(*gin.Context, models.NotificationKind, string, string) *limitoffererror.CreditCardError
+	EnqueueExpiryReminders(*gin.Context, []int) (int, *limitoffererror.CreditCardError)
+	DispatchNotifications(*gin.Context, int, int, time.Duration, func(models.Notification) error, func(int) time.Duration) (int, *limitoffererror.CreditCardError)
 }
 
 func New() (postgres, error) {
 	// Initialize the connection only once
 	once.Do(func() {
-		cfg := config.GetConfig()
-		connString := fmt.Sprintf(
-			"host=%s dbname=%s password=%s user=%s port=%d",
-			cfg.Database.Host, cfg.Database.DBname, cfg.Database.Password,
-			cfg.Database.User, cfg.Database.Port,
-		)
-
-		var err error
-		conn, err = sql.Open("pgx", connString)
-		if err != nil {
-			log.Fatalf("Unable to connect: %v\n", err)
-		}
+		conn, connErr = connect(config.GetConfig().Database)
+	})
+	if connErr != nil {
+		return postgres{}, connErr
+	}
+	return postgres{db: conn}, nil
+}
 
-		log.Println("Connected to database")
+// connect opens the connection pool and waits for the database, a database which is briefly unavailable,
+// e.g. starting along with the application, is retried with a backoff
+func connect(cfg config.Database) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(connString(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid database config : %w", err)
+	}
+	connConfig.Tracer = queryTracer{}
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
 
-		err = conn.Ping()
-		if err != nil {
-			log.Fatal("Cannot Ping the database")
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ConnectTimeout)*time.Second)
+		err = db.PingContext(ctx)
+		cancel()
+		if err == nil {
+			break
 		}
-		log.Println("pinged database")
-	})
+		if attempt >= cfg.ConnectAttempts {
+			db.Close()
+			return nil, fmt.Errorf("unable to reach the database after %v attempts : %w", attempt, err)
+		}
+		delay := connectRetryDelay(cfg, attempt)
+		utils.Logger.Warn(fmt.Sprintf("unable to reach the database, retrying in %v", delay), zap.Int("attempt", attempt), zap.Error(err))
+		time.Sleep(delay)
+	}
 
-	return postgres{db: conn}, nil
+	metrics.RegisterDB(db, cfg.DBname)
+	utils.Logger.Info("Connected to database")
+	return db, nil
+}
+
+// connectRetryDelay returns the wait after the failed attempt, doubled after every failure up to the maximum
+func connectRetryDelay(cfg config.Database, attempt int) time.Duration {
+	delay := time.Duration(cfg.ConnectRetryBackoff) * time.Second
+	max := time.Duration(cfg.MaxConnectRetryBackoff) * time.Second
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// connString returns the keyword/value connection string of the database, the values are quoted so that
+// a password may hold spaces and quotes. The keywords pgx does not know, e.g. statement_timeout, are sent
+// to the database as run-time parameters of every connection.
+func connString(cfg config.Database) string {
+	settings := []struct {
+		key   string
+		value string
+	}{
+		{"host", cfg.Host},
+		{"port", strconv.Itoa(cfg.Port)},
+		{"dbname", cfg.DBname},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"application_name", cfg.ApplicationName},
+		{"connect_timeout", strconv.Itoa(cfg.ConnectTimeout)},
+		{"statement_timeout", strconv.Itoa(cfg.StatementTimeout)},
+	}
+	var parts []string
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(setting.value)
+		parts = append(parts, fmt.Sprintf("%v='%v'", setting.key, value))
+	}
+	return strings.Join(parts, " ")
 }
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnString(t *testing.T) {
+	cfg := config.Database{
+		Host: "db.internal", Port: 5433, DBname: "offers", User: "app", Password: `it's a \\ secret`,
+		SSLMode: "disable", ApplicationName: "credit-card-offer-limit", ConnectTimeout: 5, StatementTimeout: 30000,
+	}
+	connConfig, err := pgx.ParseConfig(connString(cfg))
+	assert.NoError(t, err)
+	assert.Equal(t, "db.internal", connConfig.Host)
+	assert.Equal(t, uint16(5433), connConfig.Port)
+	assert.Equal(t, `it's a \\ secret`, connConfig.Password)
+	assert.Nil(t, connConfig.TLSConfig)
+	assert.Equal(t, 5*time.Second, connConfig.ConnectTimeout)
+	// the settings unknown to pgx are sent to the database
+	assert.Equal(t, "30000", connConfig.RuntimeParams["statement_timeout"])
+	assert.Equal(t, "credit-card-offer-limit", connConfig.RuntimeParams["application_name"])
+
+	// the server certificate can not be verified without the root certificate
+	cfg.SSLMode, cfg.SSLRootCert = "verify-full", "/missing/root.pem"
+	_, err = pgx.ParseConfig(connString(cfg))
+	assert.Error(t, err)
+}
+
+func TestConnectRetryDelay(t *testing.T) {
+	cfg := config.Database{ConnectRetryBackoff: 1, MaxConnectRetryBackoff: 5}
+	delays := []time.Duration{}
+	for attempt := 1; attempt <= 5; attempt++ {
+		delays = append(delays, connectRetryDelay(cfg, attempt))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+}
+
+func TestConnectGivesUp(t *testing.T) {
+	// nothing listens on the port, the single attempt fails right away
+	_, err := connect(config.Database{Host: "127.0.0.1", Port: 1, DBname: "offers", User: "app", SSLMode: "disable", ConnectTimeout: 1, ConnectAttempts: 1})
+	assert.ErrorContains(t, err, "unable to reach the database after 1 attempts")
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation          = "23505"
+	foreignKeyViolation      = "23503"
+	checkViolation           = "23514"
+	notNullViolation         = "23502"
+	serializationFailure     = "40001"
+	deadlockDetected         = "40P01"
+	tooManyConnections       = "53300"
+	adminShutdown            = "57P01"
+	crashShutdown            = "57P02"
+	cannotConnectNow         = "57P03"
+	connectionExceptionClass = "08"
+)
+
+// constraintError is the error reported when a specific constraint is violated
+type constraintError struct {
+	code    int
+	message string
+}
+
+// constraintErrors maps the constraint names to errors which make sense to the client,
+// violations of constraints which are not listed get a generic message for their SQLSTATE.
+var constraintErrors = map[string]constraintError{
+	"account_pkey":                   {http.StatusConflict, "account already added"},
+	"limit_offer_pkey":               {http.StatusConflict, "limit offer already added"},
+	"limit_offer_account_id_fkey":    {http.StatusNotFound, "account not found"},
+	"limit_offer_superseded_by_fkey": {http.StatusNotFound, "superseding limit offer not found"},
+}
+
+// dbFailure carries the message to report when a db error can not be mapped to something more specific
+type dbFailure struct {
+	message string
+	err     error
+}
+
+func (d *dbFailure) Error() string {
+	return fmt.Sprintf("%v : %v", d.message, d.err)
+}
+
+func (d *dbFailure) Unwrap() error {
+	return d.err
+}
+
+// failure wraps a db error along with the message reported for it when it is not mapped to something more specific
+func failure(message string, err error) error {
+	return &dbFailure{message: message, err: err}
+}
+
+// isRetryable tells whether the transaction which failed with err can be retried as a whole
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+	}
+	return false
+}
+
+// isConnectionError tells whether err is caused by the database being unreachable
+func isConnectionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case tooManyConnections, adminShutdown, crashShutdown, cannotConnectNow:
+			return true
+		}
+		return strings.HasPrefix(pgErr.Code, connectionExceptionClass)
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) || pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// translateError maps an error returned while talking to postgres to a CreditCardError, errors which
+// already are CreditCardErrors (business rule violations) are returned as they are.
+func translateError(txid string, err error, fallbackMessage string) *limitoffererror.CreditCardError {
+	var creditCardErr *limitoffererror.CreditCardError
+	if errors.As(err, &creditCardErr) {
+		return creditCardErr
+	}
+
+	var dbErr *dbFailure
+	if errors.As(err, &dbErr) {
+		fallbackMessage = dbErr.message
+	}
+
+	newError := func(code int, message string) *limitoffererror.CreditCardError {
+		return &limitoffererror.CreditCardError{Code: code, Message: message, Trace: txid}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if constraintErr, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return newError(constraintErr.code, constraintErr.message)
+		}
+		switch pgErr.Code {
+		case uniqueViolation:
+			return newError(http.StatusConflict, "record already exists")
+		case foreignKeyViolation:
+			return newError(http.StatusUnprocessableEntity, "referenced record does not exist")
+		case checkViolation, notNullViolation:
+			return newError(http.StatusBadRequest, "record violates a data constraint")
+		case serializationFailure, deadlockDetected:
+			return newError(http.StatusConflict, "request conflicted with a concurrent update, please retry")
+		}
+	}
+
+	if isConnectionError(err) {
+		return newError(http.StatusServiceUnavailable, "database is unavailable, please retry")
+	}
+
+	return newError(http.StatusInternalServerError, fallbackMessage)
+}
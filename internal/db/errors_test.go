@@ -0,0 +1,92 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{"duplicate account", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "account_pkey"}, http.StatusConflict, "account already added"},
+		{"duplicate offer", failure("unable to add offer limit info", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "limit_offer_pkey"}), http.StatusConflict, "limit offer already added"},
+		{"unknown account", &pgconn.PgError{Code: foreignKeyViolation, ConstraintName: "limit_offer_account_id_fkey"}, http.StatusNotFound, "account not found"},
+		{"unknown unique constraint", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "some_idx"}, http.StatusConflict, "record already exists"},
+		{"check violation", &pgconn.PgError{Code: checkViolation}, http.StatusBadRequest, "record violates a data constraint"},
+		{"serialization failure", &pgconn.PgError{Code: serializationFailure}, http.StatusConflict, "request conflicted with a concurrent update, please retry"},
+		{"deadlock", &pgconn.PgError{Code: deadlockDetected}, http.StatusConflict, "request conflicted with a concurrent update, please retry"},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, http.StatusServiceUnavailable, "database is unavailable, please retry"},
+		{"bad connection", driver.ErrBadConn, http.StatusServiceUnavailable, "database is unavailable, please retry"},
+		{"unknown error", failure("unable to get the limit offer", errors.New("boom")), http.StatusInternalServerError, "unable to get the limit offer"},
+		{"business error", &limitoffererror.CreditCardError{Code: http.StatusNotFound, Message: "account not found"}, http.StatusNotFound, "account not found"},
+	}
+
+	for _, c := range cases {
+		creditCardErr := translateError("txid", c.err, "fallback")
+		assert.Equal(t, c.code, creditCardErr.Code, c.name)
+		assert.Equal(t, c.message, creditCardErr.Message, c.name)
+	}
+}
+
+func TestRunInTxRetriesSerializationFailures(t *testing.T) {
+	utils.InitLogClient()
+	txRetryBackoff = time.Millisecond
+
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	// case 1 : a serialization failure is retried and the next attempt commits
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE limit_offer").WillReturnError(&pgconn.PgError{Code: serializationFailure})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE limit_offer").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	creditCardErr := p.runInTx(utils.NewBackgroundContext(), "fallback", func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE limit_offer SET status = 'ACCEPTED'")
+		return err
+	})
+	assert.Nil(t, creditCardErr)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// case 2 : a business error aborts the transaction without any retry
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	creditCardErr = p.runInTx(utils.NewBackgroundContext(), "fallback", func(tx *sql.Tx) error {
+		return &limitoffererror.CreditCardError{Code: http.StatusNotFound, Message: "account not found"}
+	})
+	assert.Equal(t, http.StatusNotFound, creditCardErr.Code)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// case 3 : deadlocks are given up after the maximum number of attempts
+	for i := 0; i < maxTxAttempts; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE limit_offer").WillReturnError(&pgconn.PgError{Code: deadlockDetected})
+		mock.ExpectRollback()
+	}
+
+	creditCardErr = p.runInTx(utils.NewBackgroundContext(), "fallback", func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE limit_offer SET status = 'ACCEPTED'")
+		return err
+	})
+	assert.Equal(t, http.StatusConflict, creditCardErr.Code)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
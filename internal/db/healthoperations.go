@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// SchemaVersion is the script of sql-scripts the application expects to be applied last, it is bumped along
+// with every new script
+const SchemaVersion = "020"
+
+// Ping checks that the database can be reached
+func (p postgres) Ping(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// CheckMigrations checks that the scripts up to SchemaVersion are applied
+func (p postgres) CheckMigrations(ctx context.Context) error {
+	var applied bool
+	err := p.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, SchemaVersion).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("unable to read the applied migrations : %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("the %v migration is not applied", SchemaVersion)
+	}
+	return nil
+}
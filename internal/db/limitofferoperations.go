@@ -3,8 +3,8 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,81 +13,151 @@ import (
 	"github.com/ankit/project/credit-card-offer-limit/internal/models"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-func (p postgres) IsLimitOfferExists(ctx *gin.Context, limitOffer models.LimitOffer) (bool, string, *limitoffererror.CreditCardError) {
-	txid := ctx.Request.Header.Get(constants.TransactionID)
+// columns of limit_offer in the order expected by scanLimitOffer
+const limitOfferColumns = `id, account_id, limit_type, new_limit, offer_activation_time, offer_expiry_time, status,
+	created_at, updated_at, decided_at, decided_by, decision_channel, superseded_by, created_by, reviewed_at, reviewed_by`
 
-	query := `
-		SELECT id
-		FROM limit_offer
-		WHERE account_id = $1 AND limit_type = $2 AND status = $3
-		LIMIT 1`
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	var offerLimitId sql.NullString
-	//var offerStatusId sql.NullString
-	err := p.db.QueryRow(query, limitOffer.AccountID, limitOffer.LimitType, models.Pending).Scan(&offerLimitId)
-	if err != nil {
+func scanLimitOffer(row rowScanner) (models.LimitOffer, error) {
+	var offer models.LimitOffer
+	err := row.Scan(
+		&offer.ID, &offer.AccountID, &offer.LimitType, &offer.NewLimit,
+		&offer.OfferActivationTime, &offer.OfferExpiryTime, &offer.Status,
+		&offer.CreatedAt, &offer.UpdatedAt, &offer.DecidedAt, &offer.DecidedBy, &offer.DecisionChannel, &offer.SupersededBy,
+		&offer.CreatedBy, &offer.ReviewedAt, &offer.ReviewedBy,
+	)
+	return offer, err
+}
+
+// CreateLimitOffer inserts the offer as a new row, pending offers for the same account and limit type
+// are handled according to the duplicate policy within the same transaction:
+// supersede marks them SUPERSEDED with a link to the new offer, reject_duplicate refuses the new offer
+// and allow_multiple leaves them untouched. The policy of an offer AWAITING_APPROVAL is applied once it is approved.
+func (p postgres) CreateLimitOffer(ctx *gin.Context, limitOffer models.LimitOffer, duplicatePolicy string) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	err := p.runInTx(ctx, "unable to add offer limit info", func(tx *sql.Tx) error {
+		// locking the account row serializes the offer creation per account, so that two concurrent
+		// requests can not both see no pending offer
+		var lockedAccountID string
+		err := tx.QueryRowContext(ctx.Request.Context(), `SELECT account_id FROM account WHERE account_id = $1 FOR UPDATE`, limitOffer.AccountID).Scan(&lockedAccountID)
 		if err == sql.ErrNoRows {
-			return false, "", nil
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "account not found",
+				Trace:   txid,
+			}
+		}
+		if err != nil {
+			return failure("unable to add offer limit info", err)
 		}
 
-		utils.Logger.Error(fmt.Sprintf("error querying limit offer existence from db, txid: %v, error: %v", txid, err))
-		return false, "", &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "error checking limit offer existence",
-			Trace:   txid,
+		pendingOffers := []models.LimitOffer{}
+		if limitOffer.Status == models.Pending {
+			pendingOffers, err = checkDuplicatePolicy(ctx, tx, limitOffer, duplicatePolicy)
+			if err != nil {
+				return err
+			}
+		}
+
+		query := `
+			INSERT INTO limit_offer(id, account_id, limit_type, new_limit, offer_activation_time, offer_expiry_time, status, created_by) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING ` + limitOfferColumns
+
+		createdOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), query, limitOffer.ID, limitOffer.AccountID, limitOffer.LimitType, limitOffer.NewLimit,
+			limitOffer.OfferActivationTime, limitOffer.OfferExpiryTime, limitOffer.Status, limitOffer.CreatedBy))
+		if err != nil {
+			return failure("unable to add offer limit info", err)
+		}
+		err = appendAuditEvent(ctx, tx, constants.AuditLimitOfferCreated, constants.AuditEntityLimitOffer, createdOffer.ID, nil, createdOffer)
+		if err != nil {
+			return err
+		}
+		if err = enqueueOutboxEvent(ctx, tx, constants.EventLimitOfferCreated, createdOffer); err != nil {
+			return err
 		}
-	}
 
-	if offerLimitId.Valid {
-		return true, offerLimitId.String, nil
+		return supersedeLimitOffers(ctx, tx, pendingOffers, limitOffer.ID, duplicatePolicy)
+	})
+	if err != nil {
+		return err
 	}
 
-	return false, "", nil
+	utils.RequestLogger(ctx).Info("successfully added the offer limit entry in db")
+	return nil
 }
 
-func (p postgres) CreateLimitOffer(ctx *gin.Context, limitOffer models.LimitOffer, isLimitOfferExsits bool) *limitoffererror.CreditCardError {
+// checkDuplicatePolicy returns the PENDING offers of the account for the limit type of the offer which is becoming
+// PENDING, or a conflict when the policy rejects duplicates. The account row has to be locked by the caller.
+func checkDuplicatePolicy(ctx *gin.Context, tx *sql.Tx, limitOffer models.LimitOffer, duplicatePolicy string) ([]models.LimitOffer, error) {
 	txid := ctx.Request.Header.Get(constants.TransactionID)
 
-	if isLimitOfferExsits {
-		fmt.Println("limitOffer.NewLimit, limitOffer.AccountID, limitOffer.LimitType :", *limitOffer.NewLimit, ":", *limitOffer.AccountID, ":", *limitOffer.LimitType)
-		_, err := p.db.Exec("UPDATE limit_offer SET new_limit = $1 WHERE account_id = $2 AND limit_type = $3", *limitOffer.NewLimit, *limitOffer.AccountID, *limitOffer.LimitType)
-		fmt.Println("err 3 ", err)
+	pendingOffers, err := pendingLimitOffers(ctx, tx, *limitOffer.AccountID, *limitOffer.LimitType)
+	if err != nil {
+		return nil, failure("error checking limit offer existence", err)
+	}
+
+	if len(pendingOffers) > 0 && duplicatePolicy == constants.RejectDuplicatePolicy {
+		utils.RequestLogger(ctx).Info("pending limit offer already exists for the account and limit type")
+		return nil, &limitoffererror.CreditCardError{
+			Code:    http.StatusConflict,
+			Message: "a pending limit offer already exists for the account and limit type",
+			Trace:   txid,
+		}
+	}
+	return pendingOffers, nil
+}
+
+// supersedeLimitOffers marks the pending offers SUPERSEDED by the offer which became PENDING when the policy asks for it
+func supersedeLimitOffers(ctx *gin.Context, tx *sql.Tx, pendingOffers []models.LimitOffer, supersededBy string, duplicatePolicy string) error {
+	if len(pendingOffers) == 0 || duplicatePolicy != constants.SupersedePolicy {
+		return nil
+	}
+	for _, pendingOffer := range pendingOffers {
+		supersededOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), `
+			UPDATE limit_offer SET status = $1, superseded_by = $2, updated_at = now() WHERE id = $3
+			RETURNING `+limitOfferColumns, models.Superseded, supersededBy, pendingOffer.ID))
 		if err != nil {
-			log.Println("error updating limit offer status:", err)
-			return &limitoffererror.CreditCardError{
-				Code:    http.StatusInternalServerError,
-				Message: "error while updating limit offer status",
-				Trace:   txid,
-			}
+			return failure("unable to supersede the pending limit offer", err)
 		}
-	} else {
-		query := `
-			INSERT INTO limit_offer(id, account_id, limit_type, new_limit, offer_activation_time, offer_expiry_time, status) 
-			VALUES($1, $2, $3, $4, $5, $6, $7)`
+		err = appendAuditEvent(ctx, tx, constants.AuditLimitOfferSuperseded, constants.AuditEntityLimitOffer, pendingOffer.ID, pendingOffer, supersededOffer)
+		if err != nil {
+			return err
+		}
+		if err = enqueueOutboxEvent(ctx, tx, constants.EventLimitOfferSuperseded, supersededOffer); err != nil {
+			return err
+		}
+	}
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("superseded %v pending limit offers by %v", len(pendingOffers), supersededBy))
+	return nil
+}
 
-		_, err := p.db.Exec(query, limitOffer.ID, limitOffer.AccountID, limitOffer.LimitType, limitOffer.NewLimit,
-			limitOffer.OfferActivationTime, limitOffer.OfferExpiryTime, limitOffer.Status)
+// pendingLimitOffers locks and returns the PENDING offers of the account for the limit type
+func pendingLimitOffers(ctx *gin.Context, tx *sql.Tx, accountID string, limitType models.LimitType) ([]models.LimitOffer, error) {
+	rows, err := tx.QueryContext(ctx.Request.Context(), `SELECT `+limitOfferColumns+` FROM limit_offer WHERE account_id = $1 AND limit_type = $2 AND status = $3 FOR UPDATE`,
+		accountID, limitType, models.Pending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	pendingOffers := []models.LimitOffer{}
+	for rows.Next() {
+		pendingOffer, err := scanLimitOffer(rows)
 		if err != nil {
-			utils.Logger.Error(fmt.Sprintf("error while running insert query, txid : %v", txid))
-			if strings.Contains(err.Error(), "duplicate key value") {
-				return &limitoffererror.CreditCardError{
-					Trace:   ctx.Request.Header.Get(constants.TransactionID),
-					Code:    http.StatusBadRequest,
-					Message: "account already added",
-				}
-			}
-			return &limitoffererror.CreditCardError{
-				Trace:   txid,
-				Code:    http.StatusInternalServerError,
-				Message: "unable to add offer limit info",
-			}
+			return nil, err
 		}
+		pendingOffers = append(pendingOffers, pendingOffer)
 	}
-	utils.Logger.Info(fmt.Sprintf("successfully added the offer limit entry in db, txid : %v", txid))
-	return nil
+	return pendingOffers, rows.Err()
 }
 
 func (p postgres) ListActiveLimitOffers(ctx *gin.Context, limitOffer models.ActiveLimitOffer) ([]models.LimitOffer, *limitoffererror.CreditCardError) {
@@ -96,12 +166,8 @@ func (p postgres) ListActiveLimitOffers(ctx *gin.Context, limitOffer models.Acti
 	// Check if the account with the provided account_id exists
 	var accountExists bool
 	accountCheckQuery := `SELECT EXISTS (SELECT 1 FROM account WHERE account_id = $1)`
-	if err := p.db.QueryRow(accountCheckQuery, limitOffer.AccountID).Scan(&accountExists); err != nil {
-		return nil, &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "error checking account existence",
-			Trace:   txid,
-		}
+	if err := p.db.QueryRowContext(ctx.Request.Context(), accountCheckQuery, limitOffer.AccountID).Scan(&accountExists); err != nil {
+		return nil, translateError(txid, err, "error checking account existence")
 	}
 
 	if !accountExists {
@@ -113,35 +179,23 @@ func (p postgres) ListActiveLimitOffers(ctx *gin.Context, limitOffer models.Acti
 	}
 
 	query := `
-		SELECT id, account_id, limit_type, new_limit, offer_activation_time, offer_expiry_time, status
+		SELECT ` + limitOfferColumns + `
 		FROM limit_offer
 		WHERE account_id = $1 AND status = $2 AND offer_activation_time <= $3 AND offer_expiry_time >= $4`
 
 	activeOffers := []models.LimitOffer{}
-	rows, err := p.db.Query(query, limitOffer.AccountID, models.Pending, limitOffer.ActiveDate, limitOffer.ActiveDate)
+	rows, err := p.db.QueryContext(ctx.Request.Context(), query, limitOffer.AccountID, models.Pending, limitOffer.ActiveDate, limitOffer.ActiveDate)
 	if err != nil {
 		// Handle the error if the query fails
-		return nil, &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "unable to retrieve active limit offers",
-			Trace:   txid,
-		}
+		return nil, translateError(txid, err, "unable to retrieve active limit offers")
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var offer models.LimitOffer
-		err := rows.Scan(
-			&offer.ID, &offer.AccountID, &offer.LimitType, &offer.NewLimit,
-			&offer.OfferActivationTime, &offer.OfferExpiryTime, &offer.Status,
-		)
+		offer, err := scanLimitOffer(rows)
 		if err != nil {
 			// Handle the error if scanning fails
-			return nil, &limitoffererror.CreditCardError{
-				Code:    http.StatusInternalServerError,
-				Message: "error scanning limit offer rows",
-				Trace:   txid,
-			}
+			return nil, translateError(txid, err, "error scanning limit offer rows")
 		}
 		activeOffers = append(activeOffers, offer)
 	}
@@ -152,151 +206,238 @@ func (p postgres) ListActiveLimitOffers(ctx *gin.Context, limitOffer models.Acti
 func (p postgres) UpdateLimitOfferStatus(ctx *gin.Context, updateLimitOfferStatus models.UpdateLimitOfferStatus) *limitoffererror.CreditCardError {
 	txid := ctx.Request.Header.Get(constants.TransactionID)
 
-	// Check if the account with the provided offer_limit_id exists
-	var offerLimitExists bool
-	offerLimitCheckQuery := `SELECT EXISTS (SELECT 1 FROM limit_offer WHERE id = $1)`
-	if err := p.db.QueryRow(offerLimitCheckQuery, updateLimitOfferStatus.LimitOfferID).Scan(&offerLimitExists); err != nil {
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "error checking account existence",
-			Trace:   txid,
+	return p.runInTx(ctx, "error while updating limit offer status", func(tx *sql.Tx) error {
+		if err := lockOfferAccount(ctx, tx, updateLimitOfferStatus.LimitOfferID); err != nil {
+			return err
 		}
-	}
-
-	if !offerLimitExists {
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusNotFound,
-			Message: "offer limit not found",
-			Trace:   txid,
+		query := `SELECT ` + limitOfferColumns + ` FROM limit_offer WHERE id = $1 FOR UPDATE`
+		limitOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), query, updateLimitOfferStatus.LimitOfferID))
+		if err == sql.ErrNoRows {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "offer limit not found",
+				Trace:   txid,
+			}
+		}
+		if err != nil {
+			return failure("error while fetching limit offer details", err)
 		}
-	}
 
-	tx, err := p.db.Begin()
-	fmt.Println("err 1 ", err)
-	if err != nil {
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "unable to begin transaction",
-			Trace:   txid,
+		// the offer may have been decided or superseded since the service layer checked it
+		if limitOffer.Status != models.Pending {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("limit offer is already in %v state", strings.ToLower(string(limitOffer.Status))),
+				Trace:   txid,
+			}
 		}
-	}
-	defer tx.Rollback()
 
-	var limitOffer models.LimitOffer
-	query := `SELECT * FROM limit_offer WHERE id = $1`
-	err = tx.QueryRow(query, updateLimitOfferStatus.LimitOfferID).Scan(&limitOffer.ID,
-		&limitOffer.AccountID,
-		&limitOffer.LimitType,
-		&limitOffer.NewLimit,
-		&limitOffer.OfferActivationTime,
-		&limitOffer.OfferExpiryTime,
-		&limitOffer.Status)
-	fmt.Println("err 2 ", err)
-	if err != nil {
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "error while fetching limit offer details",
-			Trace:   txid,
+		isActiveOffer := limitOffer.OfferActivationTime.Before(time.Now().UTC()) && limitOffer.OfferExpiryTime.After(time.Now().UTC())
+		if !isActiveOffer {
+			// if not in range
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "limit offer already expired",
+				Trace:   txid,
+			}
 		}
-	}
 
-	isActiveOffer := limitOffer.OfferActivationTime.Before(time.Now().UTC()) && limitOffer.OfferExpiryTime.After(time.Now().UTC())
-	if !isActiveOffer {
-		// if not in range
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusNotFound,
-			Message: "limit offer already expired",
-			Trace:   txid,
+		// update the status to ACCEPTED/REJECTED along with who decided it and through which channel
+		undecidedOffer := limitOffer
+		limitOffer.Status = models.OfferStatus(updateLimitOfferStatus.Status)
+		var decidedBy *string
+		if updateLimitOfferStatus.DecidedBy != constants.EmptyString {
+			decidedBy = &updateLimitOfferStatus.DecidedBy
+		}
+		decidedOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), `
+			UPDATE limit_offer
+			SET status = $1, decided_at = now(), decided_by = $2, decision_channel = $3, updated_at = now()
+			WHERE id = $4
+			RETURNING `+limitOfferColumns, limitOffer.Status, decidedBy, updateLimitOfferStatus.DecisionChannel, limitOffer.ID))
+		if err != nil {
+			return failure("error while updating limit offer status", err)
+		}
+		err = appendAuditEvent(ctx, tx, constants.AuditLimitOfferStatusUpdate, constants.AuditEntityLimitOffer, limitOffer.ID, undecidedOffer, decidedOffer)
+		if err != nil {
+			return err
 		}
-	}
 
-	// update the status to ACCEPTED/REJECTED
-	limitOffer.Status = models.OfferStatus(updateLimitOfferStatus.Status)
-	_, err = tx.Exec("UPDATE limit_offer SET status = $1 WHERE id = $2", limitOffer.Status, limitOffer.ID)
-	fmt.Println("err 3 ", err)
-	if err != nil {
-		log.Println("error updating limit offer status:", err)
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "error while updating limit offer status",
-			Trace:   txid,
+		switch limitOffer.Status {
+		case models.Rejected:
+			// if status is REJECTED, only the event is left to be recorded, no updation required in the account.
+			return enqueueOutboxEvent(ctx, tx, constants.EventLimitOfferRejected, decidedOffer)
+		case models.Accepted:
+			// if status is ACCEPTED, update limit values (current and last), as well as limit update date in the account object.
+			// the account was locked along with the offer by lockOfferAccount
+			accountQuery := `SELECT ` + accountColumns + ` FROM account WHERE account_id = $1`
+			accountInfo, err := scanAccount(tx.QueryRowContext(ctx.Request.Context(), accountQuery, limitOffer.AccountID))
+			if err != nil {
+				return failure("error while reteriving get account info", err)
+			}
+			previousAccountInfo := accountInfo
+
+			if *limitOffer.LimitType == models.AccountLimit {
+				accountInfo.LastAccountLimit = accountInfo.AccountLimit
+				accountInfo.AccountLimit = limitOffer.NewLimit
+				accountInfo.AccountLimitUpdateTime = time.Now().UTC()
+
+				// update the db
+				_, err = tx.ExecContext(ctx.Request.Context(), "UPDATE account SET last_account_limit = $1, account_limit = $2, account_limit_update_time = $3 WHERE account_id = $4",
+					accountInfo.LastAccountLimit, accountInfo.AccountLimit, accountInfo.AccountLimitUpdateTime, accountInfo.AccountID)
+				if err != nil {
+					return failure("unable to update the account limit info in db", err)
+				}
+
+			} else if *limitOffer.LimitType == models.PerTransactionLimit {
+				accountInfo.LastPerTransactionLimit = accountInfo.PerTransactionLimit
+				accountInfo.PerTransactionLimit = limitOffer.NewLimit
+				accountInfo.PerTransactionLimitUpdateTime = time.Now().UTC()
+				_, err = tx.ExecContext(ctx.Request.Context(), "UPDATE account SET last_per_transaction_limit = $1, per_transaction_limit = $2, per_transaction_limit_update_time = $3 WHERE account_id = $4",
+					accountInfo.LastPerTransactionLimit, accountInfo.PerTransactionLimit, accountInfo.PerTransactionLimitUpdateTime, accountInfo.AccountID)
+				if err != nil {
+					return failure("unable to update the account limit info in db", err)
+				}
+			}
+
+			updatedAccountInfo, err := scanAccount(tx.QueryRowContext(ctx.Request.Context(), `SELECT `+accountColumns+` FROM account WHERE account_id = $1`, limitOffer.AccountID))
+			if err != nil {
+				return failure("error while reteriving get account info", err)
+			}
+			err = appendAuditEvent(ctx, tx, constants.AuditAccountLimitUpdated, constants.AuditEntityAccount, updatedAccountInfo.AccountID, previousAccountInfo, updatedAccountInfo)
+			if err != nil {
+				return err
+			}
+			return enqueueOutboxEvent(ctx, tx, constants.EventLimitOfferAccepted, decidedOffer)
+
+		default:
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusInternalServerError,
+				Message: "received status not supported",
+				Trace:   txid,
+			}
 		}
-	}
+	})
+}
 
-	switch limitOffer.Status {
-	case models.Rejected:
-		// if status is REJECTED, your work is done, no updation required in db.
-	case models.Accepted:
-		// if status is ACCEPTED, update limit values (current and last), as well as limit update date in the account object.
-		var accountInfo models.Account
-		accountQuery := `SELECT * FROM account WHERE account_id = $1`
-		err = tx.QueryRow(accountQuery, limitOffer.AccountID).Scan(&accountInfo.AccountID,
-			&accountInfo.CustomerID,
-			&accountInfo.AccountLimit,
-			&accountInfo.PerTransactionLimit,
-			&accountInfo.LastAccountLimit,
-			&accountInfo.LastPerTransactionLimit,
-			&accountInfo.AccountLimitUpdateTime,
-			&accountInfo.PerTransactionLimitUpdateTime)
-		fmt.Println("err 4 ", err)
+// CancelLimitOffer withdraws a PENDING or AWAITING_APPROVAL offer, the canceller is recorded as the decider of the offer
+func (p postgres) CancelLimitOffer(ctx *gin.Context, limitOfferID string, cancelledBy string) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	return p.runInTx(ctx, "error while cancelling limit offer", func(tx *sql.Tx) error {
+		if err := lockOfferAccount(ctx, tx, limitOfferID); err != nil {
+			return err
+		}
+		query := `SELECT ` + limitOfferColumns + ` FROM limit_offer WHERE id = $1 FOR UPDATE`
+		limitOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), query, limitOfferID))
+		if err == sql.ErrNoRows {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "limit offer not found",
+				Trace:   txid,
+			}
+		}
 		if err != nil {
-			log.Println("error fetching account:", err)
+			return failure("error while fetching limit offer details", err)
+		}
+
+		if limitOffer.Status != models.Pending && limitOffer.Status != models.AwaitingApproval {
 			return &limitoffererror.CreditCardError{
-				Code:    http.StatusInternalServerError,
-				Message: "error while reteriving get account info",
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("limit offer is already in %v state", strings.ToLower(string(limitOffer.Status))),
 				Trace:   txid,
 			}
 		}
 
-		if *limitOffer.LimitType == models.AccountLimit {
-			accountInfo.LastAccountLimit = accountInfo.AccountLimit
-			accountInfo.AccountLimit = limitOffer.NewLimit
-			accountInfo.AccountLimitUpdateTime = time.Now().UTC()
+		cancelledOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), `
+			UPDATE limit_offer
+			SET status = $1, decided_at = now(), decided_by = $2, updated_at = now()
+			WHERE id = $3
+			RETURNING `+limitOfferColumns, models.Cancelled, cancelledBy, limitOfferID))
+		if err != nil {
+			return failure("error while cancelling limit offer", err)
+		}
+		err = appendAuditEvent(ctx, tx, constants.AuditLimitOfferCancelled, constants.AuditEntityLimitOffer, limitOfferID, limitOffer, cancelledOffer)
+		if err != nil {
+			return err
+		}
+		return enqueueOutboxEvent(ctx, tx, constants.EventLimitOfferCancelled, cancelledOffer)
+	})
+}
+
+// ReviewLimitOffer approves or declines an offer AWAITING_APPROVAL. An approved offer becomes PENDING and
+// the duplicate policy is applied to the pending offers of the account as if the offer was created now.
+func (p postgres) ReviewLimitOffer(ctx *gin.Context, reviewLimitOffer models.ReviewLimitOffer, duplicatePolicy string) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
 
-			// update the db
-			_, err = tx.Exec("UPDATE account SET last_account_limit = $1, account_limit = $2, account_limit_update_time = $3 WHERE account_id = $4",
-				accountInfo.LastAccountLimit, accountInfo.AccountLimit, accountInfo.AccountLimitUpdateTime, accountInfo.AccountID)
-			if err != nil {
-				log.Println("error updating account:", err)
-				return &limitoffererror.CreditCardError{
-					Code:    http.StatusInternalServerError,
-					Message: "unable to update the account limit info in db",
-					Trace:   txid,
-				}
+	return p.runInTx(ctx, "error while reviewing limit offer", func(tx *sql.Tx) error {
+		if err := lockOfferAccount(ctx, tx, reviewLimitOffer.LimitOfferID); err != nil {
+			return err
+		}
+		query := `SELECT ` + limitOfferColumns + ` FROM limit_offer WHERE id = $1 FOR UPDATE`
+		limitOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), query, reviewLimitOffer.LimitOfferID))
+		if err == sql.ErrNoRows {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "limit offer not found",
+				Trace:   txid,
 			}
+		}
+		if err != nil {
+			return failure("error while fetching limit offer details", err)
+		}
 
-		} else if *limitOffer.LimitType == models.PerTransactionLimit {
-			accountInfo.LastPerTransactionLimit = accountInfo.PerTransactionLimit
-			accountInfo.PerTransactionLimit = limitOffer.NewLimit
-			accountInfo.PerTransactionLimitUpdateTime = time.Now().UTC()
-			_, err = tx.Exec("UPDATE account SET last_per_transaction_limit = $1, per_transaction_limit = $2, per_transaction_limit_update_time = $3 WHERE account_id = $4",
-				accountInfo.LastPerTransactionLimit, accountInfo.PerTransactionLimit, accountInfo.PerTransactionLimitUpdateTime, accountInfo.AccountID)
+		// the offer may have been reviewed or cancelled since the service layer checked it
+		if limitOffer.Status != models.AwaitingApproval {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("limit offer is already in %v state", strings.ToLower(string(limitOffer.Status))),
+				Trace:   txid,
+			}
+		}
+
+		pendingOffers := []models.LimitOffer{}
+		if reviewLimitOffer.Status == models.Pending {
+			pendingOffers, err = checkDuplicatePolicy(ctx, tx, limitOffer, duplicatePolicy)
 			if err != nil {
-				log.Println("error updating account:", err)
-				return &limitoffererror.CreditCardError{
-					Code:    http.StatusInternalServerError,
-					Message: "unable to update the account limit info in db",
-					Trace:   txid,
-				}
+				return err
 			}
 		}
 
-	default:
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "received status not supported",
-			Trace:   txid,
+		reviewedOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), `
+			UPDATE limit_offer
+			SET status = $1, reviewed_at = now(), reviewed_by = $2, updated_at = now()
+			WHERE id = $3
+			RETURNING `+limitOfferColumns, reviewLimitOffer.Status, reviewLimitOffer.ReviewedBy, limitOffer.ID))
+		if err != nil {
+			return failure("error while reviewing limit offer", err)
 		}
-	}
+		err = appendAuditEvent(ctx, tx, constants.AuditLimitOfferReviewed, constants.AuditEntityLimitOffer, limitOffer.ID, limitOffer, reviewedOffer)
+		if err != nil {
+			return err
+		}
+		eventType := constants.EventLimitOfferApproved
+		if reviewedOffer.Status == models.Declined {
+			eventType = constants.EventLimitOfferDeclined
+		}
+		if err = enqueueOutboxEvent(ctx, tx, eventType, reviewedOffer); err != nil {
+			return err
+		}
+
+		return supersedeLimitOffers(ctx, tx, pendingOffers, limitOffer.ID, duplicatePolicy)
+	})
+}
 
-	err = tx.Commit()
+// lockOfferAccount locks the account of the offer ahead of the offer itself, in the order CreateLimitOffer locks
+// them. Every transaction writing an outbox event holds the lock of the account of the event, so that the events
+// of an account are written in the order their transactions commit. An unknown offer locks nothing.
+func lockOfferAccount(ctx *gin.Context, tx *sql.Tx, limitOfferID string) error {
+	_, err := tx.ExecContext(ctx.Request.Context(), `
+		SELECT a.account_id
+		FROM account a JOIN limit_offer o ON o.account_id = a.account_id
+		WHERE o.id = $1
+		FOR UPDATE OF a`, limitOfferID)
 	if err != nil {
-		log.Println("error committing transaction:", err)
-		return &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "unable to commit changes in db",
-			Trace:   txid,
-		}
+		return failure("error while locking the account", err)
 	}
 	return nil
 }
@@ -304,20 +445,8 @@ func (p postgres) UpdateLimitOfferStatus(ctx *gin.Context, updateLimitOfferStatu
 func (p postgres) GetLimitOffer(ctx *gin.Context, offerLimitID string) (models.LimitOffer, *limitoffererror.CreditCardError) {
 	txid := ctx.Request.Header.Get(constants.TransactionID)
 
-	scannedLimitOffer := models.LimitOffer{}
-
-	query := `SELECT * FROM limit_offer WHERE id=$1`
-	row := p.db.QueryRow(query, offerLimitID)
-
-	err := row.Scan(
-		&scannedLimitOffer.ID,
-		&scannedLimitOffer.AccountID,
-		&scannedLimitOffer.LimitType,
-		&scannedLimitOffer.NewLimit,
-		&scannedLimitOffer.OfferActivationTime,
-		&scannedLimitOffer.OfferExpiryTime,
-		&scannedLimitOffer.Status,
-	)
+	query := `SELECT ` + limitOfferColumns + ` FROM limit_offer WHERE id=$1`
+	scannedLimitOffer, err := scanLimitOffer(p.db.QueryRowContext(ctx.Request.Context(), query, offerLimitID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Handle case where no rows were found
@@ -328,14 +457,196 @@ func (p postgres) GetLimitOffer(ctx *gin.Context, offerLimitID string) (models.L
 			}
 		}
 
-		utils.Logger.Error(fmt.Sprintf("error while scanning account from db, txid : %v, error: %v", txid, err))
-		return scannedLimitOffer, &limitoffererror.CreditCardError{
-			Code:    http.StatusInternalServerError,
-			Message: "unable to get the limit offer",
+		utils.RequestLogger(ctx).Error("error while scanning account from db", zap.Error(err))
+		return scannedLimitOffer, translateError(txid, err, "unable to get the limit offer")
+	}
+
+	utils.RequestLogger(ctx).Info("successfully fetched limit offer from db")
+	return scannedLimitOffer, nil
+}
+
+// buildLimitOfferFilter converts the filter into where conditions along with their positional arguments
+func buildLimitOfferFilter(filter models.LimitOfferFilter) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+	addIn := func(column string, values []string) {
+		placeholders := make([]string, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")))
+	}
+
+	if filter.AccountID != "" {
+		addCondition("account_id = $%d", filter.AccountID)
+	}
+	if len(filter.Status) > 0 {
+		statuses := make([]string, 0, len(filter.Status))
+		for _, status := range filter.Status {
+			statuses = append(statuses, string(status))
+		}
+		addIn("status", statuses)
+	}
+	if len(filter.LimitType) > 0 {
+		limitTypes := make([]string, 0, len(filter.LimitType))
+		for _, limitType := range filter.LimitType {
+			limitTypes = append(limitTypes, string(limitType))
+		}
+		addIn("limit_type", limitTypes)
+	}
+
+	ranges := []struct {
+		column string
+		from   *time.Time
+		to     *time.Time
+	}{
+		{"created_at", filter.CreatedFrom, filter.CreatedTo},
+		{"offer_activation_time", filter.ActivationFrom, filter.ActivationTo},
+		{"offer_expiry_time", filter.ExpiryFrom, filter.ExpiryTo},
+	}
+	for _, r := range ranges {
+		if r.from != nil {
+			addCondition(r.column+" >= $%d", *r.from)
+		}
+		if r.to != nil {
+			addCondition(r.column+" <= $%d", *r.to)
+		}
+	}
+
+	return conditions, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return constants.EmptyString
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func (p postgres) ExportLimitOffers(ctx *gin.Context, filter models.LimitOfferFilter, write func(models.LimitOffer) error) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	conditions, args := buildLimitOfferFilter(filter)
+	query := `
+		SELECT ` + limitOfferColumns + `
+		FROM limit_offer` + whereClause(conditions) + `
+		ORDER BY offer_activation_time, id`
+
+	rows, err := p.db.QueryContext(ctx.Request.Context(), query, args...)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while querying limit offers for export", zap.Error(err))
+		return translateError(txid, err, "unable to export limit offers")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		offer, err := scanLimitOffer(rows)
+		if err != nil {
+			utils.RequestLogger(ctx).Error("error while scanning limit offer for export", zap.Error(err))
+			return translateError(txid, err, "error scanning limit offer rows")
+		}
+
+		if err := write(offer); err != nil {
+			utils.RequestLogger(ctx).Error("error while writing exported limit offer", zap.Error(err))
+			return translateError(txid, err, "unable to write exported limit offers")
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		utils.RequestLogger(ctx).Error("error while iterating limit offers for export", zap.Error(err))
+		return translateError(txid, err, "unable to export limit offers")
+	}
+
+	utils.RequestLogger(ctx).Info("successfully exported limit offers from db")
+	return nil
+}
+
+// ListLimitOffers returns a page of the limit offers matching the filter using keyset pagination on
+// the sort column and id, along with the total number of matching offers.
+func (p postgres) ListLimitOffers(ctx *gin.Context, request models.ListLimitOffers) (models.LimitOfferPage, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	page := models.LimitOfferPage{LimitOffers: []models.LimitOffer{}}
+
+	sortColumn, ok := sortColumns[request.SortBy]
+	if !ok {
+		return page, &limitoffererror.CreditCardError{
+			Code:    http.StatusBadRequest,
+			Message: "sort_by is not supported",
 			Trace:   txid,
 		}
 	}
+	direction := "ASC"
+	if request.SortOrder == constants.SortOrderDesc {
+		direction = "DESC"
+	}
 
-	utils.Logger.Info(fmt.Sprintf("successfully fetched limit offer from db, txid : %v", txid))
-	return scannedLimitOffer, nil
+	conditions, args := buildLimitOfferFilter(request.LimitOfferFilter)
+
+	countQuery := `SELECT COUNT(*) FROM limit_offer` + whereClause(conditions)
+	if err := p.db.QueryRowContext(ctx.Request.Context(), countQuery, args...).Scan(&page.TotalCount); err != nil {
+		utils.RequestLogger(ctx).Error("error while counting limit offers", zap.Error(err))
+		return page, translateError(txid, err, "unable to retrieve limit offers")
+	}
+
+	if request.Cursor != constants.EmptyString {
+		c, err := decodeCursor(request.Cursor, request.SortBy, request.SortOrder)
+		var condition string
+		var cursorArgs []interface{}
+		if err == nil {
+			condition, cursorArgs, err = cursorCondition(c, sortColumn, len(args)+1)
+		}
+		if err != nil {
+			utils.RequestLogger(ctx).Error("invalid cursor received while listing limit offers")
+			return page, &limitoffererror.CreditCardError{
+				Code:    http.StatusBadRequest,
+				Message: constants.InvalidCursor,
+				Trace:   txid,
+			}
+		}
+		args = append(args, cursorArgs...)
+		conditions = append(conditions, condition)
+	}
+
+	// one extra row is fetched to know whether there is a next page
+	args = append(args, request.PageSize+1)
+	query := `
+		SELECT ` + limitOfferColumns + `
+		FROM limit_offer` + whereClause(conditions) + `
+		ORDER BY ` + sortColumn + ` ` + direction + ` NULLS LAST, id ` + direction + `
+		LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := p.db.QueryContext(ctx.Request.Context(), query, args...)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while listing limit offers", zap.Error(err))
+		return page, translateError(txid, err, "unable to retrieve limit offers")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		offer, err := scanLimitOffer(rows)
+		if err != nil {
+			utils.RequestLogger(ctx).Error("error while scanning limit offer", zap.Error(err))
+			return page, translateError(txid, err, "error scanning limit offer rows")
+		}
+		page.LimitOffers = append(page.LimitOffers, offer)
+	}
+	if err := rows.Err(); err != nil {
+		utils.RequestLogger(ctx).Error("error while iterating limit offers", zap.Error(err))
+		return page, translateError(txid, err, "unable to retrieve limit offers")
+	}
+
+	if len(page.LimitOffers) > request.PageSize {
+		page.LimitOffers = page.LimitOffers[:request.PageSize]
+		last := page.LimitOffers[len(page.LimitOffers)-1]
+		page.NextCursor = encodeCursor(newCursor(request.SortBy, request.SortOrder, last))
+	}
+
+	utils.RequestLogger(ctx).Info("successfully listed limit offers from db")
+	return page, nil
 }
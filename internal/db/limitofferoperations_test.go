@@ -0,0 +1,132 @@
+package db
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountID = "2b4e1e64-624f-4a4e-9911-e0b13f526e10"
+
+// limitOfferRows returns the limit_offer rows of the offers of the test account, by id and status
+func limitOfferRows(offers ...[2]string) *sqlmock.Rows {
+	columns := strings.Split(strings.Join(strings.Fields(limitOfferColumns), ""), ",")
+	rows := sqlmock.NewRows(columns)
+	now := time.Now().UTC()
+	for _, offer := range offers {
+		rows.AddRow(offer[0], testAccountID, string(models.AccountLimit), 6000, now.Add(-time.Hour), now.Add(24*time.Hour), offer[1],
+			now, nil, nil, nil, nil, nil, nil, nil, nil)
+	}
+	return rows
+}
+
+// expectAuditEvent expects the statements of appendAuditEvent
+func expectAuditEvent(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT hash FROM audit_log").WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectQuery("SELECT nextval").WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(int64(1)))
+	mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// expectOutboxEvent expects the statements of enqueueOutboxEvent, the event is written only when the outbox is enabled
+func expectOutboxEvent(mock sqlmock.Sqlmock) {
+	if config.GetConfig().Outbox.Enabled {
+		mock.ExpectExec("INSERT INTO outbox").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec("SELECT pg_notify").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestCreateLimitOfferDuplicatePolicy(t *testing.T) {
+	utils.InitLogClient()
+	pending := [2]string{"pending-offer", string(models.Pending)}
+
+	cases := []struct {
+		name    string
+		policy  string
+		status  models.OfferStatus
+		pending [][2]string
+		// the statements after the offer is inserted along with its audit and outbox events
+		expectSupersede bool
+		code            int
+	}{
+		{"supersede marks the pending offer superseded", constants.SupersedePolicy, models.Pending, [][2]string{pending}, true, 0},
+		{"supersede without a pending offer", constants.SupersedePolicy, models.Pending, nil, false, 0},
+		{"reject_duplicate refuses the new offer", constants.RejectDuplicatePolicy, models.Pending, [][2]string{pending}, false, http.StatusConflict},
+		{"reject_duplicate without a pending offer", constants.RejectDuplicatePolicy, models.Pending, nil, false, 0},
+		{"allow_multiple keeps the pending offer", constants.AllowMultiplePolicy, models.Pending, [][2]string{pending}, false, 0},
+		{"the policy of an offer awaiting approval waits for the approval", constants.RejectDuplicatePolicy, models.AwaitingApproval, nil, false, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conn, mock, err := sqlmock.New()
+			assert.Nil(t, err)
+			defer conn.Close()
+			p := postgres{db: conn}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT account_id FROM account WHERE account_id = \\$1 FOR UPDATE").WithArgs(testAccountID).
+				WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(testAccountID))
+			if c.status == models.Pending {
+				// the pending offers are read again under the lock of the account
+				mock.ExpectQuery("FROM limit_offer WHERE account_id = \\$1 AND limit_type = \\$2 AND status = \\$3 FOR UPDATE").
+					WithArgs(testAccountID, models.AccountLimit, models.Pending).WillReturnRows(limitOfferRows(c.pending...))
+			}
+			if c.code != 0 {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery("INSERT INTO limit_offer").WillReturnRows(limitOfferRows([2]string{"new-offer", string(c.status)}))
+				expectAuditEvent(mock)
+				expectOutboxEvent(mock)
+				if c.expectSupersede {
+					mock.ExpectQuery("UPDATE limit_offer SET status = \\$1, superseded_by = \\$2").
+						WithArgs(models.Superseded, "new-offer", "pending-offer").
+						WillReturnRows(limitOfferRows([2]string{"pending-offer", string(models.Superseded)}))
+					expectAuditEvent(mock)
+					expectOutboxEvent(mock)
+				}
+				mock.ExpectCommit()
+			}
+
+			accountID, limitType, newLimit := testAccountID, models.AccountLimit, 6000
+			creditCardErr := p.CreateLimitOffer(utils.NewBackgroundContext(), models.LimitOffer{
+				ID: "new-offer", AccountID: &accountID, LimitType: &limitType, NewLimit: &newLimit, Status: c.status,
+			}, c.policy)
+			if c.code == 0 {
+				assert.Nil(t, creditCardErr)
+			} else {
+				assert.Equal(t, c.code, creditCardErr.Code)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateLimitOfferStatusRechecksPending(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	// the offer was superseded after the service layer read it PENDING, the locked row tells
+	mock.ExpectBegin()
+	mock.ExpectExec("FOR UPDATE OF a").WithArgs("pending-offer").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM limit_offer WHERE id = \\$1 FOR UPDATE").WithArgs("pending-offer").
+		WillReturnRows(limitOfferRows([2]string{"pending-offer", string(models.Superseded)}))
+	mock.ExpectRollback()
+
+	creditCardErr := p.UpdateLimitOfferStatus(utils.NewBackgroundContext(), models.UpdateLimitOfferStatus{
+		LimitOfferID: "pending-offer", Status: string(models.Accepted),
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, creditCardErr.Code)
+	assert.Equal(t, "limit offer is already in superseded state", creditCardErr.Message)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
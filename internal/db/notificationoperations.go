@@ -0,0 +1,184 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EnqueueNotification queues the notification of the kind about the offer for the customer of its account.
+// A notification is queued only once per dedup key and only for the accounts having an email.
+func (p postgres) EnqueueNotification(ctx *gin.Context, kind models.NotificationKind, limitOfferID string, dedupKey string) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	_, err := p.db.ExecContext(ctx.Request.Context(), `
+		INSERT INTO notification(dedup_key, kind, account_id, limit_offer_id, status)
+		SELECT $1, $2, o.account_id, o.id, $3
+		FROM limit_offer o JOIN account a ON a.account_id = o.account_id
+		WHERE o.id = $4 AND a.email IS NOT NULL
+		ON CONFLICT (dedup_key) DO NOTHING`,
+		dedupKey, kind, models.NotificationPending, limitOfferID)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("error while queuing %v notification of %v limit offer", kind, limitOfferID), zap.Error(err))
+		return translateError(txid, err, "unable to queue the notification")
+	}
+	return nil
+}
+
+// EnqueueExpiryReminders queues a reminder for the active PENDING offers getting within each of the numbers of days
+// of their expiry and returns how many were queued. An offer only gets the reminder of the smallest number of days it is
+// within, e.g. with 3 and 1 an offer expiring in 2 days gets the 3 days reminder now and the 1 day reminder tomorrow.
+func (p postgres) EnqueueExpiryReminders(ctx *gin.Context, reminderDays []int) (int, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	days := append([]int(nil), reminderDays...)
+	sort.Ints(days)
+	queued := 0
+	for i, within := range days {
+		notWithin := 0
+		if i > 0 {
+			notWithin = days[i-1]
+		}
+		result, err := p.db.ExecContext(ctx.Request.Context(), `
+			INSERT INTO notification(dedup_key, kind, account_id, limit_offer_id, days_left, status)
+			SELECT $1 || ':' || o.id || ':' || $6, $1, o.account_id, o.id, $3, $2
+			FROM limit_offer o JOIN account a ON a.account_id = o.account_id
+			WHERE o.status = $4 AND a.email IS NOT NULL AND o.offer_activation_time <= now()
+				AND o.offer_expiry_time > now() + make_interval(days => $5)
+				AND o.offer_expiry_time <= now() + make_interval(days => $3)
+			ON CONFLICT (dedup_key) DO NOTHING`,
+			models.NotificationOfferExpiring, models.NotificationPending, within, models.Pending, notWithin, strconv.Itoa(within))
+		if err != nil {
+			utils.RequestLogger(ctx).Error(fmt.Sprintf("error while queuing the %v days expiry reminders", within), zap.Error(err))
+			return queued, translateError(txid, err, "unable to queue the expiry reminders")
+		}
+		affected, _ := result.RowsAffected()
+		queued += int(affected)
+	}
+	return queued, nil
+}
+
+// DispatchNotifications sends a batch of the queued notifications which are due and returns how many were processed.
+// A notification is SENT once send succeeds, retried after retryAfter(attempts) when it fails and FAILED once it
+// failed maxAttempts times. An expiry reminder of an offer which is no longer PENDING is SKIPPED.
+// The batch is leased for lease in a transaction of its own, the notifications are sent with no transaction open
+// and every outcome is recorded in a transaction of its own. A notification whose lease ran out is sent again.
+func (p postgres) DispatchNotifications(ctx *gin.Context, batchSize int, maxAttempts int, lease time.Duration, send func(models.Notification) error,
+	retryAfter func(attempts int) time.Duration) (int, *limitoffererror.CreditCardError) {
+	notifications := []models.Notification{}
+	err := p.runInTx(ctx, "unable to dispatch the notifications", func(tx *sql.Tx) error {
+		notifications = notifications[:0]
+		// RETURNING does not keep the order of the subquery, the leased notifications are sorted again
+		rows, err := tx.QueryContext(ctx.Request.Context(), `
+			WITH leased AS (
+				UPDATE notification SET leased_until = now() + make_interval(secs => $3)
+				WHERE id IN (
+					SELECT id
+					FROM notification
+					WHERE status = $1 AND next_attempt_at <= now() AND (leased_until IS NULL OR leased_until < now())
+					ORDER BY next_attempt_at, id
+					LIMIT $2
+					FOR UPDATE SKIP LOCKED)
+				RETURNING id, kind, days_left, attempts, account_id, limit_offer_id, next_attempt_at)
+			SELECT n.id, n.kind, n.days_left, n.attempts, `+qualifiedColumns("a", accountColumns)+`, `+qualifiedColumns("o", limitOfferColumns)+`
+			FROM leased n
+				JOIN account a ON a.account_id = n.account_id
+				JOIN limit_offer o ON o.id = n.limit_offer_id
+			ORDER BY n.next_attempt_at, n.id`, models.NotificationPending, batchSize, lease.Seconds())
+		if err != nil {
+			return failure("unable to lease the notifications", err)
+		}
+		for rows.Next() {
+			notification, err := scanNotification(rows)
+			if err != nil {
+				rows.Close()
+				return failure("error scanning notification rows", err)
+			}
+			notifications = append(notifications, notification)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return failure("error scanning notification rows", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, notification := range notifications {
+		var sendErr error
+		skipped := notification.Kind == models.NotificationOfferExpiring && notification.LimitOffer.Status != models.Pending
+		if !skipped {
+			sendErr = send(notification)
+		}
+		// the notifications left are sent again once their lease runs out
+		if err := p.recordNotification(ctx, notification, skipped, sendErr, maxAttempts, retryAfter); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// recordNotification moves the notification to its next state after it was sent or skipped, releasing its lease.
+// A notification which is no longer PENDING, e.g. sent by another dispatcher after its lease ran out, is left as it is.
+func (p postgres) recordNotification(ctx *gin.Context, notification models.Notification, skipped bool, sendErr error,
+	maxAttempts int, retryAfter func(attempts int) time.Duration) *limitoffererror.CreditCardError {
+	return p.runInTx(ctx, "unable to record the notification", func(tx *sql.Tx) error {
+		var err error
+		attempts := notification.Attempts + 1
+		switch {
+		case skipped:
+			_, err = tx.ExecContext(ctx.Request.Context(), `UPDATE notification SET status = $1, leased_until = NULL WHERE id = $2 AND status = $3`,
+				models.NotificationSkipped, notification.ID, models.NotificationPending)
+		case sendErr == nil:
+			_, err = tx.ExecContext(ctx.Request.Context(), `
+				UPDATE notification SET status = $1, attempts = $2, last_error = NULL, sent_at = now(), leased_until = NULL
+				WHERE id = $3 AND status = $4`,
+				models.NotificationSent, attempts, notification.ID, models.NotificationPending)
+		case attempts >= maxAttempts:
+			utils.RequestLogger(ctx).Info(fmt.Sprintf("giving up %v notification after %v attempts", notification.ID, attempts), zap.Error(sendErr))
+			_, err = tx.ExecContext(ctx.Request.Context(), `
+				UPDATE notification SET status = $1, attempts = $2, last_error = $3, leased_until = NULL
+				WHERE id = $4 AND status = $5`,
+				models.NotificationFailed, attempts, sendErr.Error(), notification.ID, models.NotificationPending)
+		default:
+			utils.RequestLogger(ctx).Info(fmt.Sprintf("unable to send %v notification", notification.ID), zap.Error(sendErr))
+			_, err = tx.ExecContext(ctx.Request.Context(), `
+				UPDATE notification SET attempts = $1, last_error = $2, next_attempt_at = $3, leased_until = NULL
+				WHERE id = $4 AND status = $5`,
+				attempts, sendErr.Error(), time.Now().UTC().Add(retryAfter(attempts)), notification.ID, models.NotificationPending)
+		}
+		if err != nil {
+			return failure("unable to update the notification", err)
+		}
+		return nil
+	})
+}
+
+func scanNotification(row rowScanner) (models.Notification, error) {
+	var notification models.Notification
+	account := &notification.Account
+	offer := &notification.LimitOffer
+	err := row.Scan(&notification.ID, &notification.Kind, &notification.DaysLeft, &notification.Attempts,
+		&account.AccountID, &account.CustomerID, &account.AccountLimit, &account.PerTransactionLimit, &account.LastAccountLimit,
+		&account.LastPerTransactionLimit, &account.AccountLimitUpdateTime, &account.PerTransactionLimitUpdateTime,
+		&account.Email, &account.Locale,
+		&offer.ID, &offer.AccountID, &offer.LimitType, &offer.NewLimit,
+		&offer.OfferActivationTime, &offer.OfferExpiryTime, &offer.Status,
+		&offer.CreatedAt, &offer.UpdatedAt, &offer.DecidedAt, &offer.DecidedBy, &offer.DecisionChannel, &offer.SupersededBy,
+		&offer.CreatedBy, &offer.ReviewedAt, &offer.ReviewedBy,
+	)
+	return notification, err
+}
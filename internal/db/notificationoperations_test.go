@@ -0,0 +1,72 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatchNotifications(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	columns := []string{"id", "kind", "days_left", "attempts"}
+	for _, list := range []string{accountColumns, limitOfferColumns} {
+		columns = append(columns, strings.Split(strings.Join(strings.Fields(list), ""), ",")...)
+	}
+	now := time.Now().UTC()
+	notification := func(id int64, kind models.NotificationKind, offerStatus models.OfferStatus) []driver.Value {
+		return []driver.Value{id, string(kind), nil, 0,
+			testAccountID, "customer", 6000, 500, 5000, nil, now, now, "jane@example.com", "en",
+			"offer", testAccountID, string(models.AccountLimit), 6000, now.Add(-time.Hour), now.Add(24 * time.Hour), string(offerStatus),
+			now, nil, nil, nil, nil, nil, nil, nil, nil}
+	}
+	// the lease is committed before the first message is sent, and every outcome is recorded in a transaction of its own
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE notification SET leased_until").WithArgs(models.NotificationPending, 50, float64(60)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(notification(1, models.NotificationOfferExpiring, models.Accepted)...).
+			AddRow(notification(2, models.NotificationOfferCreated, models.Pending)...).
+			AddRow(notification(3, models.NotificationLimitChanged, models.Accepted)...))
+	mock.ExpectCommit()
+	// case 1 : the reminder of an offer which is no longer pending is skipped without being sent
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notification SET status = \\$1, leased_until = NULL").WithArgs(models.NotificationSkipped, int64(1), models.NotificationPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// case 2 : a sent notification is SENT
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notification SET status = \\$1, attempts = \\$2, last_error = NULL, sent_at = now\\(\\), leased_until = NULL").
+		WithArgs(models.NotificationSent, 1, int64(2), models.NotificationPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// case 3 : a failed notification is retried after the backoff of its attempts
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE notification SET attempts = \\$1, last_error = \\$2, next_attempt_at = \\$3, leased_until = NULL").
+		WithArgs(1, "i/o timeout", retryAt(time.Hour), int64(3), models.NotificationPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var sent []int64
+	send := func(notification models.Notification) error {
+		sent = append(sent, notification.ID)
+		if notification.Kind == models.NotificationLimitChanged {
+			return errors.New("i/o timeout")
+		}
+		return nil
+	}
+	retryAfter := func(attempts int) time.Duration { return time.Duration(attempts) * time.Hour }
+
+	processed, creditCardErr := p.DispatchNotifications(utils.NewBackgroundContext(), 50, 5, time.Minute, send, retryAfter)
+	assert.Nil(t, creditCardErr)
+	assert.Equal(t, 3, processed)
+	assert.Equal(t, []int64{2, 3}, sent)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
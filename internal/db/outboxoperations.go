@@ -0,0 +1,294 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+)
+
+// columns of outbox in the order expected by scanOutboxEvent
+const outboxEventColumns = `id, event_id, event_type, account_id, aggregate_id, payload, occurred_at, attempts`
+
+func scanOutboxEvent(row rowScanner) (models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	var payload []byte
+	err := row.Scan(&event.ID, &event.EventID, &event.EventType, &event.AccountID, &event.AggregateID, &payload,
+		&event.OccurredAt, &event.Attempts)
+	event.Payload = payload
+	return event, err
+}
+
+// enqueueOutboxEvent writes the domain event of the offer within the transaction changing it, so that the event
+// is published if and only if the change is committed. The listeners of the outbox channel are notified of the
+// event once the transaction commits, in the order the transactions commit.
+// The transaction has to hold the lock of the account of the offer, the events of an account are then numbered
+// in the order their transactions commit, which is the order the relay publishes them in.
+// Without a relay the event is not written, nothing would ever publish nor purge it, the listeners are notified
+// all the same.
+func enqueueOutboxEvent(ctx *gin.Context, tx *sql.Tx, eventType string, limitOffer models.LimitOffer) error {
+	payload, err := json.Marshal(limitOffer)
+	if err != nil {
+		return failure("unable to record the outbox event", err)
+	}
+
+	event := models.OutboxEvent{
+		EventID:     uuid.New().String(),
+		EventType:   eventType,
+		AggregateID: limitOffer.ID,
+		Payload:     payload,
+		OccurredAt:  time.Now().UTC(),
+	}
+	if limitOffer.AccountID != nil {
+		event.AccountID = *limitOffer.AccountID
+	}
+	if config.GetConfig().Outbox.Enabled {
+		_, err = tx.ExecContext(ctx.Request.Context(), `
+			INSERT INTO outbox(event_id, event_type, account_id, aggregate_id, payload, occurred_at)
+			VALUES($1, $2, $3, $4, $5, $6)`,
+			event.EventID, event.EventType, event.AccountID, event.AggregateID, string(event.Payload), event.OccurredAt)
+		if err != nil {
+			return failure("unable to record the outbox event", err)
+		}
+	}
+
+	notification, err := json.Marshal(event)
+	if err != nil {
+		return failure("unable to record the outbox event", err)
+	}
+	if _, err = tx.ExecContext(ctx.Request.Context(), `SELECT pg_notify($1, $2)`, constants.OutboxNotifyChannel, string(notification)); err != nil {
+		return failure("unable to notify the outbox event", err)
+	}
+	return nil
+}
+
+// RelayOutboxEvents publishes a batch of the unpublished events whose retry is due and returns how many of them
+// were published. Only the oldest unpublished event of every account is eligible, so the events of an account are
+// published in the order they were written even with several relays, and a failing event holds back the events
+// of its account until it is published. A failed event is retried after retryAfter(attempts).
+// The batch is leased for lease in a transaction of its own and published with no transaction open, the outcomes
+// are recorded in a second transaction. An event is marked published after publish returns, and an event whose
+// lease ran out is claimed again, so an event may be published more than once.
+func (p postgres) RelayOutboxEvents(ctx *gin.Context, batchSize int, lease time.Duration, publish func(models.OutboxEvent) error, retryAfter func(attempts int) time.Duration) (int, *limitoffererror.CreditCardError) {
+	events := []models.OutboxEvent{}
+	err := p.runInTx(ctx, "unable to relay the outbox events", func(tx *sql.Tx) error {
+		events = events[:0]
+		// RETURNING does not keep the order of the subquery, the leased events are sorted again
+		rows, err := tx.QueryContext(ctx.Request.Context(), `
+			WITH leased AS (
+				UPDATE outbox SET leased_until = now() + make_interval(secs => $2)
+				WHERE id IN (
+					SELECT id
+					FROM outbox o
+					WHERE published_at IS NULL AND next_attempt_at <= now() AND (leased_until IS NULL OR leased_until < now())
+						AND NOT EXISTS (SELECT 1 FROM outbox e WHERE e.account_id = o.account_id AND e.published_at IS NULL AND e.id < o.id)
+					ORDER BY id
+					LIMIT $1
+					FOR UPDATE SKIP LOCKED)
+				RETURNING `+outboxEventColumns+`)
+			SELECT `+outboxEventColumns+` FROM leased ORDER BY id`, batchSize, lease.Seconds())
+		if err != nil {
+			return failure("unable to lease the outbox events", err)
+		}
+		for rows.Next() {
+			event, err := scanOutboxEvent(rows)
+			if err != nil {
+				rows.Close()
+				return failure("error scanning outbox event rows", err)
+			}
+			events = append(events, event)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return failure("error scanning outbox event rows", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	publishErrs := make([]error, len(events))
+	for i, event := range events {
+		publishErrs[i] = publish(event)
+	}
+
+	published := 0
+	err = p.runInTx(ctx, "unable to record the relayed outbox events", func(tx *sql.Tx) error {
+		published = 0
+		for i, event := range events {
+			var err error
+			if publishErr := publishErrs[i]; publishErr != nil {
+				attempts := event.Attempts + 1
+				utils.RequestLogger(ctx).Info(fmt.Sprintf("unable to publish %v outbox event after %v attempts", event.EventID, attempts), zap.Error(publishErr))
+				_, err = tx.ExecContext(ctx.Request.Context(), `UPDATE outbox SET attempts = $1, next_attempt_at = $2, last_error = $3, leased_until = NULL WHERE id = $4`,
+					attempts, time.Now().UTC().Add(retryAfter(attempts)), publishErr.Error(), event.ID)
+			} else {
+				published++
+				_, err = tx.ExecContext(ctx.Request.Context(), `UPDATE outbox SET attempts = attempts + 1, published_at = now(), last_error = NULL, leased_until = NULL WHERE id = $1`, event.ID)
+			}
+			if err != nil {
+				return failure("unable to update the outbox event", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// PurgeOutboxEvents deletes up to batchSize events published more than retention ago and returns how many were
+// deleted. The unpublished events are kept whatever their age.
+func (p postgres) PurgeOutboxEvents(ctx *gin.Context, retention time.Duration, batchSize int) (int, *limitoffererror.CreditCardError) {
+	purged := 0
+	err := p.runInTx(ctx, "unable to purge the outbox events", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx.Request.Context(), `
+			DELETE FROM outbox
+			WHERE id IN (
+				SELECT id
+				FROM outbox
+				WHERE published_at < now() - make_interval(secs => $1)
+				ORDER BY published_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED)`, retention.Seconds(), batchSize)
+		if err != nil {
+			return failure("unable to purge the outbox events", err)
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return failure("unable to purge the outbox events", err)
+		}
+		purged = int(deleted)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// ExpireLimitOffers marks the PENDING and AWAITING_APPROVAL offers whose expiry time has passed EXPIRED and
+// returns the expired offers. As every change writing an event, the account of an offer is locked before the
+// offer, offers whose account or row is locked by a concurrent change are left to the next sweep.
+func (p postgres) ExpireLimitOffers(ctx *gin.Context) ([]models.LimitOffer, *limitoffererror.CreditCardError) {
+	expired := []models.LimitOffer{}
+	err := p.runInTx(ctx, "unable to expire the limit offers", func(tx *sql.Tx) error {
+		expired = expired[:0]
+		rows, err := tx.QueryContext(ctx.Request.Context(), `
+			SELECT id, account_id
+			FROM limit_offer
+			WHERE status IN ($1, $2) AND offer_expiry_time < now()
+			ORDER BY offer_expiry_time, id`, models.Pending, models.AwaitingApproval)
+		if err != nil {
+			return failure("unable to read the expired limit offers", err)
+		}
+		candidates := [][2]string{}
+		for rows.Next() {
+			var candidate [2]string
+			if err := rows.Scan(&candidate[0], &candidate[1]); err != nil {
+				rows.Close()
+				return failure("error scanning limit offer rows", err)
+			}
+			candidates = append(candidates, candidate)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return failure("error scanning limit offer rows", err)
+		}
+
+		for _, candidate := range candidates {
+			offerID, accountID := candidate[0], candidate[1]
+			var lockedAccountID string
+			err := tx.QueryRowContext(ctx.Request.Context(), `SELECT account_id FROM account WHERE account_id = $1 FOR UPDATE SKIP LOCKED`, accountID).Scan(&lockedAccountID)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return failure("error while locking the account", err)
+			}
+			// the offer may have been decided before its account was locked
+			offer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), `
+				SELECT `+limitOfferColumns+`
+				FROM limit_offer
+				WHERE id = $1 AND status IN ($2, $3) AND offer_expiry_time < now()
+				FOR UPDATE SKIP LOCKED`, offerID, models.Pending, models.AwaitingApproval))
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return failure("unable to read the expired limit offer", err)
+			}
+
+			expiredOffer, err := scanLimitOffer(tx.QueryRowContext(ctx.Request.Context(), `
+				UPDATE limit_offer SET status = $1, updated_at = now() WHERE id = $2
+				RETURNING `+limitOfferColumns, models.Expired, offer.ID))
+			if err != nil {
+				return failure("unable to expire the limit offer", err)
+			}
+			err = appendAuditEvent(ctx, tx, constants.AuditLimitOfferExpired, constants.AuditEntityLimitOffer, offer.ID, offer, expiredOffer)
+			if err != nil {
+				return err
+			}
+			if err = enqueueOutboxEvent(ctx, tx, constants.EventLimitOfferExpired, expiredOffer); err != nil {
+				return err
+			}
+			expired = append(expired, expiredOffer)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(expired) > 0 {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("expired %v limit offers", len(expired)))
+	}
+	return expired, nil
+}
+
+// ListenOutboxEvents calls fn with every outbox event committed from now on, in commit order, until ctx is
+// cancelled or the connection is lost. The events committed while nobody listens are not replayed.
+func (p postgres) ListenOutboxEvents(ctx context.Context, fn func(models.OutboxEvent)) error {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+constants.OutboxNotifyChannel); err != nil {
+			return err
+		}
+		// the connection goes back to the pool, it must not keep receiving the notifications
+		defer pgxConn.Exec(context.Background(), "UNLISTEN "+constants.OutboxNotifyChannel)
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			var event models.OutboxEvent
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				utils.Logger.Error("unable to decode the outbox notification", zap.Error(err))
+				continue
+			}
+			fn(event)
+		}
+	})
+}
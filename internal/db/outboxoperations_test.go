@@ -0,0 +1,149 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayOutboxEvents(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	columns := strings.Split(strings.Join(strings.Fields(outboxEventColumns), ""), ",")
+	now := time.Now().UTC()
+	// case 1 : the leased events are published in the order they were written, with no transaction open
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE outbox SET leased_until").WithArgs(10, float64(60)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(int64(1), "e1", "limit_offer.created", "a1", "o1", []byte(`{}`), now, 0).
+		AddRow(int64(2), "e2", "limit_offer.created", "a2", "o2", []byte(`{}`), now, 3))
+	mock.ExpectCommit()
+	// case 2 : the outcomes are recorded in a second transaction, which releases the lease
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE outbox SET attempts = attempts \\+ 1, published_at = now\\(\\), last_error = NULL, leased_until = NULL").
+		WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox SET attempts = \\$1, next_attempt_at = \\$2, last_error = \\$3, leased_until = NULL").
+		WithArgs(4, sqlmock.AnyArg(), "unreachable", int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var publishedIDs []string
+	publish := func(event models.OutboxEvent) error {
+		publishedIDs = append(publishedIDs, event.EventID)
+		if event.EventID == "e2" {
+			return errors.New("unreachable")
+		}
+		return nil
+	}
+	published, creditCardErr := p.RelayOutboxEvents(utils.NewBackgroundContext(), 10, time.Minute, publish, func(int) time.Duration { return time.Second })
+	assert.Nil(t, creditCardErr)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"e1", "e2"}, publishedIDs)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// case 3 : nothing is published when there is nothing to lease
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE outbox SET leased_until").WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectCommit()
+	published, creditCardErr = p.RelayOutboxEvents(utils.NewBackgroundContext(), 10, time.Minute, publish, func(int) time.Duration { return time.Second })
+	assert.Nil(t, creditCardErr)
+	assert.Equal(t, 0, published)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestExpireLimitOffersLocksTheAccountFirst(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, account_id FROM limit_offer").WillReturnRows(sqlmock.NewRows([]string{"id", "account_id"}).
+		AddRow("busy-offer", "busy-account").AddRow("decided-offer", testAccountID).AddRow("expired-offer", testAccountID))
+	// case 1 : the offers of an account locked by a concurrent change are left to the next sweep
+	mock.ExpectQuery("FROM account WHERE account_id = \\$1 FOR UPDATE SKIP LOCKED").WithArgs("busy-account").
+		WillReturnRows(sqlmock.NewRows([]string{"account_id"}))
+	// case 2 : an offer decided before its account was locked is left as it is
+	mock.ExpectQuery("FROM account WHERE account_id = \\$1 FOR UPDATE SKIP LOCKED").WithArgs(testAccountID).
+		WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(testAccountID))
+	mock.ExpectQuery("FROM limit_offer WHERE id = \\$1 AND status IN").WithArgs("decided-offer", models.Pending, models.AwaitingApproval).
+		WillReturnRows(limitOfferRows())
+	// case 3 : the offer is expired under the lock of its account
+	mock.ExpectQuery("FROM account WHERE account_id = \\$1 FOR UPDATE SKIP LOCKED").WithArgs(testAccountID).
+		WillReturnRows(sqlmock.NewRows([]string{"account_id"}).AddRow(testAccountID))
+	mock.ExpectQuery("FROM limit_offer WHERE id = \\$1 AND status IN").WithArgs("expired-offer", models.Pending, models.AwaitingApproval).
+		WillReturnRows(limitOfferRows([2]string{"expired-offer", string(models.Pending)}))
+	mock.ExpectQuery("UPDATE limit_offer SET status = \\$1").WithArgs(models.Expired, "expired-offer").
+		WillReturnRows(limitOfferRows([2]string{"expired-offer", string(models.Expired)}))
+	expectAuditEvent(mock)
+	expectOutboxEvent(mock)
+	mock.ExpectCommit()
+
+	expired, creditCardErr := p.ExpireLimitOffers(utils.NewBackgroundContext())
+	assert.Nil(t, creditCardErr)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "expired-offer", expired[0].ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestEnqueueOutboxEvent(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	t.Cleanup(func() { config.SetConfig(config.GlobalConfig{}) })
+	accountID := testAccountID
+	offer := models.LimitOffer{ID: "offer", AccountID: &accountID}
+
+	enqueue := func() error {
+		tx, err := conn.Begin()
+		assert.Nil(t, err)
+		defer tx.Rollback()
+		return enqueueOutboxEvent(utils.NewBackgroundContext(), tx, constants.EventLimitOfferCreated, offer)
+	}
+
+	// case 1 : without a relay the event is not written, the listeners are notified all the same
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_notify").WithArgs(constants.OutboxNotifyChannel, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.Nil(t, enqueue())
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// case 2 : the event is written for the relay along with the notification
+	config.SetConfig(config.GlobalConfig{Outbox: config.Outbox{Enabled: true}})
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox").WithArgs(sqlmock.AnyArg(), constants.EventLimitOfferCreated, testAccountID, "offer", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SELECT pg_notify").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.Nil(t, enqueue())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeOutboxEvents(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	// the events published more than the retention ago are deleted, oldest first
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM outbox").WithArgs(float64(3600), 100).WillReturnResult(sqlmock.NewResult(0, 42))
+	mock.ExpectCommit()
+	purged, creditCardErr := p.PurgeOutboxEvents(utils.NewBackgroundContext(), time.Hour, 100)
+	assert.Nil(t, creditCardErr)
+	assert.Equal(t, 42, purged)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
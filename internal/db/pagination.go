@@ -0,0 +1,117 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+)
+
+// sortColumns maps the accepted sort_by values to the limit_offer columns, id is always
+// used as the tie breaker so that the keyset is unique.
+var sortColumns = map[string]string{
+	constants.SortByCreatedAt:           "created_at",
+	constants.SortByOfferActivationTime: "offer_activation_time",
+	constants.SortByOfferExpiryTime:     "offer_expiry_time",
+	constants.SortByNewLimit:            "new_limit",
+}
+
+var errInvalidCursor = errors.New(constants.InvalidCursor)
+
+// cursor is the position after the last row of a page, it is handed to the client as an opaque token.
+// Null is set when the sort column of the row is NULL, those rows are sorted last whatever the order.
+type cursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"`
+	Null      bool   `json:"n,omitempty"`
+	ID        string `json:"i"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses the token and makes sure it was issued for the same sorting
+func decodeCursor(token, sortBy, sortOrder string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, errInvalidCursor
+	}
+	if c.SortBy != sortBy || c.SortOrder != sortOrder || c.ID == "" || (c.Null && c.Value != "") {
+		return c, errInvalidCursor
+	}
+	return c, nil
+}
+
+// newCursor returns the cursor of the position after the row
+func newCursor(sortBy, sortOrder string, offer models.LimitOffer) cursor {
+	c := cursor{SortBy: sortBy, SortOrder: sortOrder, ID: offer.ID}
+	switch sortBy {
+	case constants.SortByOfferActivationTime:
+		c.Value, c.Null = formatCursorTime(offer.OfferActivationTime)
+	case constants.SortByOfferExpiryTime:
+		c.Value, c.Null = formatCursorTime(offer.OfferExpiryTime)
+	case constants.SortByNewLimit:
+		if offer.NewLimit == nil {
+			c.Null = true
+		} else {
+			c.Value = strconv.Itoa(*offer.NewLimit)
+		}
+	default:
+		c.Value, c.Null = formatCursorTime(offer.CreatedAt)
+	}
+	return c
+}
+
+func formatCursorTime(value *time.Time) (string, bool) {
+	if value == nil {
+		return constants.EmptyString, true
+	}
+	return value.UTC().Format(time.RFC3339Nano), false
+}
+
+// cursorCondition returns the condition selecting the rows after the cursor in the order of the sort column, NULLS
+// LAST, and id, the placeholders of its arguments start at $next
+func cursorCondition(c cursor, column string, next int) (string, []interface{}, error) {
+	comparison := ">"
+	if c.SortOrder == constants.SortOrderDesc {
+		comparison = "<"
+	}
+	// the rows whose column is NULL come last, ordered by id alone
+	if c.Null {
+		return fmt.Sprintf("(%s IS NULL AND id %s $%d)", column, comparison, next), []interface{}{c.ID}, nil
+	}
+	value, err := typedCursorValue(c)
+	if err != nil {
+		return "", nil, err
+	}
+	condition := fmt.Sprintf("((%s, id) %s ($%d, $%d) OR %s IS NULL)", column, comparison, next, next+1, column)
+	return condition, []interface{}{value, c.ID}, nil
+}
+
+// typedCursorValue converts the cursor value back into the type of the sort column
+func typedCursorValue(c cursor) (interface{}, error) {
+	if c.SortBy == constants.SortByNewLimit {
+		value, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		return value, nil
+	}
+	value, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	return value, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	activation := time.Date(2023, 8, 1, 10, 30, 0, 500, time.FixedZone("IST", 19800))
+	newLimit := 6000
+	offer := models.LimitOffer{ID: "f3a1", OfferActivationTime: &activation, NewLimit: &newLimit}
+
+	cases := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+		offer     models.LimitOffer
+		condition string
+		args      []interface{}
+	}{
+		{"time", constants.SortByOfferActivationTime, constants.SortOrderAsc, offer,
+			"((offer_activation_time, id) > ($3, $4) OR offer_activation_time IS NULL)", []interface{}{activation.UTC(), "f3a1"}},
+		{"integer", constants.SortByNewLimit, constants.SortOrderDesc, offer,
+			"((new_limit, id) < ($3, $4) OR new_limit IS NULL)", []interface{}{6000, "f3a1"}},
+		{"null time", constants.SortByOfferExpiryTime, constants.SortOrderAsc, offer,
+			"(offer_expiry_time IS NULL AND id > $3)", []interface{}{"f3a1"}},
+		{"null integer", constants.SortByNewLimit, constants.SortOrderDesc, models.LimitOffer{ID: "f3a1"},
+			"(new_limit IS NULL AND id < $3)", []interface{}{"f3a1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			token := encodeCursor(newCursor(c.sortBy, c.sortOrder, c.offer))
+			decoded, err := decodeCursor(token, c.sortBy, c.sortOrder)
+			assert.NoError(t, err)
+			condition, args, err := cursorCondition(decoded, sortColumns[c.sortBy], 3)
+			assert.NoError(t, err)
+			assert.Equal(t, c.condition, condition)
+			assert.Equal(t, c.args, args)
+		})
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	token := encodeCursor(cursor{SortBy: constants.SortByCreatedAt, SortOrder: constants.SortOrderDesc, Value: "2023-08-01T10:30:00Z", ID: "f3a1"})
+
+	// the cursor of another sorting
+	_, err := decodeCursor(token, constants.SortByCreatedAt, constants.SortOrderAsc)
+	assert.Equal(t, errInvalidCursor, err)
+	_, err = decodeCursor(token, constants.SortByNewLimit, constants.SortOrderDesc)
+	assert.Equal(t, errInvalidCursor, err)
+
+	// tokens which were not issued by the listing
+	for _, invalid := range []string{
+		"not base64!",
+		"bm90IGpzb24",
+		encodeCursor(cursor{SortBy: constants.SortByCreatedAt, SortOrder: constants.SortOrderDesc, Value: "2023-08-01T10:30:00Z"}),
+		encodeCursor(cursor{SortBy: constants.SortByCreatedAt, SortOrder: constants.SortOrderDesc, Value: "2023-08-01T10:30:00Z", Null: true, ID: "f3a1"}),
+	} {
+		_, err = decodeCursor(invalid, constants.SortByCreatedAt, constants.SortOrderDesc)
+		assert.Equal(t, errInvalidCursor, err, invalid)
+	}
+
+	// a value which does not have the type of the column
+	c, err := decodeCursor(encodeCursor(cursor{SortBy: constants.SortByNewLimit, SortOrder: constants.SortOrderAsc, Value: "6000.5", ID: "f3a1"}), constants.SortByNewLimit, constants.SortOrderAsc)
+	assert.NoError(t, err)
+	_, _, err = cursorCondition(c, "new_limit", 1)
+	assert.Equal(t, errInvalidCursor, err)
+}
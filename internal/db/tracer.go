@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	operation string
+	start     time.Time
+	span      trace.Span
+}
+
+// queryTracer records the latency of every sql statement sent on the connections of the pool and traces it
+// as a child of the span of the context the statement was run with
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := queryOperation(data.SQL)
+	ctx, span := tracing.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatement(strings.TrimSpace(data.SQL))))
+	return context.WithValue(ctx, queryStartKey{}, queryStart{operation: operation, start: time.Now(), span: span})
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	started, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	metrics.ObserveQuery(started.operation, time.Since(started.start), data.Err)
+	if data.Err != nil {
+		started.span.RecordError(data.Err)
+		started.span.SetStatus(codes.Error, data.Err.Error())
+	}
+	started.span.End()
+}
+
+// queryOperation returns the command of the sql statement, e.g. SELECT, the statements of this application
+// all start with it
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "UNKNOWN"
+	}
+	return strings.ToUpper(fields[0])
+}
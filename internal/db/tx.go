@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// number of times a transaction failing with a serialization failure or a deadlock is attempted
+const maxTxAttempts = 3
+
+// base delay between the attempts, it grows with every attempt and a random jitter is added
+var txRetryBackoff = 50 * time.Millisecond
+
+// runInTx runs fn within a transaction and commits it. The whole transaction is retried when it fails with
+// a serialization failure or a deadlock. fn returns either a CreditCardError to abort because of a business
+// rule or the db error (optionally wrapped with failure) which is translated into a CreditCardError.
+func (p postgres) runInTx(ctx *gin.Context, fallbackMessage string, fn func(tx *sql.Tx) error) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = p.attemptTx(ctx, fn)
+		if err == nil || !isRetryable(err) || attempt == maxTxAttempts {
+			break
+		}
+
+		delay := txRetryBackoff*time.Duration(attempt) + time.Duration(rand.Int63n(int64(txRetryBackoff)))
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("retrying transaction after attempt %v failed", attempt), zap.Error(err))
+		select {
+		case <-ctx.Request.Context().Done():
+			return translateError(txid, ctx.Request.Context().Err(), fallbackMessage)
+		case <-time.After(delay):
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	creditCardErr := translateError(txid, err, fallbackMessage)
+	if creditCardErr.Code >= 500 {
+		utils.RequestLogger(ctx).Error("transaction failed", zap.Error(err))
+	}
+	return creditCardErr
+}
+
+func (p postgres) attemptTx(ctx *gin.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.BeginTx(ctx.Request.Context(), nil)
+	if err != nil {
+		return failure("unable to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return failure("unable to commit changes in db", err)
+	}
+	return nil
+}
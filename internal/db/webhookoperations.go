@@ -0,0 +1,348 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// columns of webhook_subscription in the order expected by scanWebhookSubscription
+const webhookSubscriptionColumns = `id, url, event_types, secret, created_by, created_at`
+
+// columns of webhook_delivery in the order expected by scanWebhookDelivery
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, redelivered_attempts,
+	next_attempt_at, last_error, created_at, delivered_at`
+
+func scanWebhookSubscription(row rowScanner) (models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var eventTypes string
+	err := row.Scan(&subscription.ID, &subscription.URL, &eventTypes, &subscription.Secret, &subscription.CreatedBy, &subscription.CreatedAt)
+	subscription.EventTypes = strings.Fields(eventTypes)
+	return subscription, err
+}
+
+func scanWebhookDelivery(row rowScanner, extra ...interface{}) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload []byte
+	dest := []interface{}{&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &delivery.RedeliveredAttempts, &delivery.NextAttemptAt, &delivery.LastError,
+		&delivery.CreatedAt, &delivery.DeliveredAt}
+	err := row.Scan(append(dest, extra...)...)
+	delivery.Payload = payload
+	return delivery, err
+}
+
+// CreateWebhookSubscription registers the subscription, the secret is recorded in the audit log as redacted
+func (p postgres) CreateWebhookSubscription(ctx *gin.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, *limitoffererror.CreditCardError) {
+	var createdSubscription models.WebhookSubscription
+	err := p.runInTx(ctx, "unable to create the webhook subscription", func(tx *sql.Tx) error {
+		var err error
+		createdSubscription, err = scanWebhookSubscription(tx.QueryRowContext(ctx.Request.Context(), `
+			INSERT INTO webhook_subscription(id, url, event_types, secret, created_by)
+			VALUES($1, $2, $3, $4, $5)
+			RETURNING `+webhookSubscriptionColumns,
+			subscription.ID, subscription.URL, strings.Join(subscription.EventTypes, " "), subscription.Secret, subscription.CreatedBy))
+		if err != nil {
+			return failure("unable to create the webhook subscription", err)
+		}
+
+		audited := createdSubscription
+		audited.Secret = constants.EmptyString
+		return appendAuditEvent(ctx, tx, constants.AuditWebhookSubCreated, constants.AuditEntityWebhookSub, audited.ID, nil, audited)
+	})
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("successfully created the %v webhook subscription", createdSubscription.ID))
+	return createdSubscription, nil
+}
+
+// ListWebhookSubscriptions returns every subscription in the order they were created, without their secrets
+func (p postgres) ListWebhookSubscriptions(ctx *gin.Context) ([]models.WebhookSubscription, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	rows, err := p.db.QueryContext(ctx.Request.Context(), `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscription ORDER BY created_at, id`)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while listing webhook subscriptions", zap.Error(err))
+		return nil, translateError(txid, err, "unable to list the webhook subscriptions")
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, translateError(txid, err, "error scanning webhook subscription rows")
+		}
+		subscription.Secret = constants.EmptyString
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(txid, err, "error scanning webhook subscription rows")
+	}
+	return subscriptions, nil
+}
+
+// EnqueueWebhookDeliveries creates a delivery of the event for every subscription interested in its type.
+// An event relayed again is not delivered again to the subscriptions which already have it.
+func (p postgres) EnqueueWebhookDeliveries(ctx *gin.Context, event models.OutboxEvent, body []byte) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	_, err := p.db.ExecContext(ctx.Request.Context(), `
+		INSERT INTO webhook_delivery(id, subscription_id, event_id, event_type, payload, status)
+		SELECT gen_random_uuid()::text, id, $1, $2, $3, $4
+		FROM webhook_subscription
+		WHERE event_types = '' OR $2 = ANY(string_to_array(event_types, ' '))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.EventID, event.EventType, string(body), models.DeliveryPending)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("error while enqueuing webhook deliveries of %v event", event.EventID), zap.Error(err))
+		return translateError(txid, err, "unable to enqueue the webhook deliveries")
+	}
+	return nil
+}
+
+// DispatchWebhookDeliveries attempts a batch of the PENDING deliveries which are due and returns how many were attempted.
+// Every attempt is logged, a delivery is DELIVERED once an attempt succeeds, retried after retryAfter(attempts)
+// when it fails and dead-lettered once it failed maxAttempts times, the attempts are counted since its last redelivery.
+// The batch is leased for lease in a transaction of its own, the deliveries are posted with no transaction open
+// and every attempt is recorded in a transaction of its own. A delivery whose lease ran out is posted again.
+func (p postgres) DispatchWebhookDeliveries(ctx *gin.Context, batchSize int, maxAttempts int, lease time.Duration,
+	deliver func(models.WebhookDelivery, models.WebhookSubscription) models.WebhookDeliveryAttempt,
+	retryAfter func(attempts int) time.Duration) (int, *limitoffererror.CreditCardError) {
+	deliveries := []models.WebhookDelivery{}
+	subscriptions := []models.WebhookSubscription{}
+	err := p.runInTx(ctx, "unable to dispatch the webhook deliveries", func(tx *sql.Tx) error {
+		deliveries, subscriptions = deliveries[:0], subscriptions[:0]
+		// RETURNING does not keep the order of the subquery, the leased deliveries are sorted again
+		rows, err := tx.QueryContext(ctx.Request.Context(), `
+			WITH leased AS (
+				UPDATE webhook_delivery SET leased_until = now() + make_interval(secs => $3)
+				WHERE id IN (
+					SELECT id
+					FROM webhook_delivery
+					WHERE status = $1 AND next_attempt_at <= now() AND (leased_until IS NULL OR leased_until < now())
+					ORDER BY next_attempt_at, id
+					LIMIT $2
+					FOR UPDATE SKIP LOCKED)
+				RETURNING `+webhookDeliveryColumns+`)
+			SELECT `+qualifiedColumns("d", webhookDeliveryColumns)+`, s.url, s.secret
+			FROM leased d JOIN webhook_subscription s ON s.id = d.subscription_id
+			ORDER BY d.next_attempt_at, d.id`, models.DeliveryPending, batchSize, lease.Seconds())
+		if err != nil {
+			return failure("unable to lease the webhook deliveries", err)
+		}
+		for rows.Next() {
+			var subscription models.WebhookSubscription
+			delivery, err := scanWebhookDelivery(rows, &subscription.URL, &subscription.Secret)
+			if err != nil {
+				rows.Close()
+				return failure("error scanning webhook delivery rows", err)
+			}
+			subscription.ID = delivery.SubscriptionID
+			deliveries = append(deliveries, delivery)
+			subscriptions = append(subscriptions, subscription)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return failure("error scanning webhook delivery rows", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for i, delivery := range deliveries {
+		attempt := deliver(delivery, subscriptions[i])
+		attempt.Attempt = delivery.Attempts + 1
+		// the deliveries left are posted again once their lease runs out
+		if err := p.recordWebhookDeliveryAttempt(ctx, delivery, attempt, maxAttempts, retryAfter); err != nil {
+			return attempted, err
+		}
+		attempted++
+	}
+	return attempted, nil
+}
+
+// recordWebhookDeliveryAttempt logs the attempt and moves the delivery to its next state, releasing its lease.
+// A delivery which is no longer PENDING, e.g. delivered by another dispatcher after its lease ran out, is left as it is.
+func (p postgres) recordWebhookDeliveryAttempt(ctx *gin.Context, delivery models.WebhookDelivery, attempt models.WebhookDeliveryAttempt,
+	maxAttempts int, retryAfter func(attempts int) time.Duration) *limitoffererror.CreditCardError {
+	return p.runInTx(ctx, "unable to record the webhook delivery attempt", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx.Request.Context(), `
+			INSERT INTO webhook_delivery_attempt(delivery_id, attempt, attempted_at, status_code, error, duration_ms)
+			VALUES($1, $2, $3, $4, $5, $6)`,
+			delivery.ID, attempt.Attempt, attempt.AttemptedAt, attempt.StatusCode, attempt.Error, attempt.DurationMS)
+		if err != nil {
+			return failure("unable to log the webhook delivery attempt", err)
+		}
+
+		// the attempts made before the last redelivery keep their numbers in the log but not their weight
+		attempts := attempt.Attempt - delivery.RedeliveredAttempts
+		switch {
+		case attempt.Error == nil:
+			_, err = tx.ExecContext(ctx.Request.Context(), `
+				UPDATE webhook_delivery SET status = $1, attempts = $2, last_error = NULL, delivered_at = now(), leased_until = NULL
+				WHERE id = $3 AND status = $4`,
+				models.DeliveryDelivered, attempt.Attempt, delivery.ID, models.DeliveryPending)
+		case attempts >= maxAttempts:
+			utils.RequestLogger(ctx).Info(fmt.Sprintf("dead-lettered %v webhook delivery after %v attempts", delivery.ID, attempt.Attempt))
+			_, err = tx.ExecContext(ctx.Request.Context(), `
+				UPDATE webhook_delivery SET status = $1, attempts = $2, last_error = $3, leased_until = NULL
+				WHERE id = $4 AND status = $5`,
+				models.DeliveryDeadLetter, attempt.Attempt, *attempt.Error, delivery.ID, models.DeliveryPending)
+		default:
+			_, err = tx.ExecContext(ctx.Request.Context(), `
+				UPDATE webhook_delivery SET attempts = $1, last_error = $2, next_attempt_at = $3, leased_until = NULL
+				WHERE id = $4 AND status = $5`,
+				attempt.Attempt, *attempt.Error, time.Now().UTC().Add(retryAfter(attempts)), delivery.ID, models.DeliveryPending)
+		}
+		if err != nil {
+			return failure("unable to update the webhook delivery", err)
+		}
+		return nil
+	})
+}
+
+// ListWebhookDeliveries returns the latest deliveries of the subscription along with their attempt log
+func (p postgres) ListWebhookDeliveries(ctx *gin.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	var subscriptionExists bool
+	err := p.db.QueryRowContext(ctx.Request.Context(), `SELECT EXISTS (SELECT 1 FROM webhook_subscription WHERE id = $1)`, filter.SubscriptionID).Scan(&subscriptionExists)
+	if err != nil {
+		return nil, translateError(txid, err, "error checking webhook subscription existence")
+	}
+	if !subscriptionExists {
+		return nil, &limitoffererror.CreditCardError{
+			Code:    http.StatusNotFound,
+			Message: "webhook subscription not found",
+			Trace:   txid,
+		}
+	}
+
+	args := []interface{}{filter.SubscriptionID}
+	conditions := []string{"subscription_id = $1"}
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, 0, len(filter.Status))
+		for _, status := range filter.Status {
+			args = append(args, status)
+			placeholders = append(placeholders, "$"+strconv.Itoa(len(args)))
+		}
+		conditions = append(conditions, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	args = append(args, filter.PageSize)
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_delivery` + whereClause(conditions) +
+		` ORDER BY created_at DESC, id DESC LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := p.db.QueryContext(ctx.Request.Context(), query, args...)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while listing webhook deliveries", zap.Error(err))
+		return nil, translateError(txid, err, "unable to list the webhook deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	positions := map[string]int{}
+	deliveryIDs := []string{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, translateError(txid, err, "error scanning webhook delivery rows")
+		}
+		positions[delivery.ID] = len(deliveries)
+		deliveryIDs = append(deliveryIDs, delivery.ID)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(txid, err, "error scanning webhook delivery rows")
+	}
+	rows.Close()
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+
+	attemptRows, err := p.db.QueryContext(ctx.Request.Context(), `
+		SELECT delivery_id, attempt, attempted_at, status_code, error, duration_ms
+		FROM webhook_delivery_attempt
+		WHERE delivery_id = ANY($1)
+		ORDER BY id`, deliveryIDs)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while listing webhook delivery attempts", zap.Error(err))
+		return nil, translateError(txid, err, "unable to list the webhook delivery attempts")
+	}
+	defer attemptRows.Close()
+
+	for attemptRows.Next() {
+		var attempt models.WebhookDeliveryAttempt
+		err := attemptRows.Scan(&attempt.DeliveryID, &attempt.Attempt, &attempt.AttemptedAt, &attempt.StatusCode, &attempt.Error, &attempt.DurationMS)
+		if err != nil {
+			return nil, translateError(txid, err, "error scanning webhook delivery attempt rows")
+		}
+		delivery := &deliveries[positions[attempt.DeliveryID]]
+		delivery.AttemptLog = append(delivery.AttemptLog, attempt)
+	}
+	if err := attemptRows.Err(); err != nil {
+		return nil, translateError(txid, err, "error scanning webhook delivery attempt rows")
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhookDelivery makes the delivery PENDING and due right away with a fresh budget of attempts,
+// the attempts made so far are kept in the attempt log and the next one is numbered after them
+func (p postgres) RedeliverWebhookDelivery(ctx *gin.Context, deliveryID string) (models.WebhookDelivery, *limitoffererror.CreditCardError) {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	var redelivered models.WebhookDelivery
+	err := p.runInTx(ctx, "unable to redeliver the webhook delivery", func(tx *sql.Tx) error {
+		delivery, err := scanWebhookDelivery(tx.QueryRowContext(ctx.Request.Context(), `SELECT `+webhookDeliveryColumns+` FROM webhook_delivery WHERE id = $1 FOR UPDATE`, deliveryID))
+		if err == sql.ErrNoRows {
+			return &limitoffererror.CreditCardError{
+				Code:    http.StatusNotFound,
+				Message: "webhook delivery not found",
+				Trace:   txid,
+			}
+		}
+		if err != nil {
+			return failure("error while fetching the webhook delivery", err)
+		}
+
+		redelivered, err = scanWebhookDelivery(tx.QueryRowContext(ctx.Request.Context(), `
+			UPDATE webhook_delivery SET status = $1, redelivered_attempts = attempts, next_attempt_at = now(), delivered_at = NULL,
+				leased_until = NULL
+			WHERE id = $2
+			RETURNING `+webhookDeliveryColumns, models.DeliveryPending, deliveryID))
+		if err != nil {
+			return failure("unable to redeliver the webhook delivery", err)
+		}
+		return appendAuditEvent(ctx, tx, constants.AuditWebhookRedelivered, constants.AuditEntityWebhookDelivery, deliveryID, delivery, redelivered)
+	})
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("scheduled the redelivery of %v webhook delivery", deliveryID))
+	return redelivered, nil
+}
+
+// qualifiedColumns prefixes every column of the list with the alias of its table
+func qualifiedColumns(alias string, columns string) string {
+	qualified := []string{}
+	for _, column := range strings.Split(columns, ",") {
+		qualified = append(qualified, alias+"."+strings.TrimSpace(column))
+	}
+	return strings.Join(qualified, ", ")
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// retryAt matches the next attempt time of a delivery retried after delay
+type retryAt time.Duration
+
+func (r retryAt) Match(v driver.Value) bool {
+	at, ok := v.(time.Time)
+	delay := time.Until(at)
+	return ok && delay > time.Duration(r)-time.Minute && delay <= time.Duration(r)
+}
+
+func TestDispatchWebhookDeliveries(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	columns := append(strings.Split(strings.Join(strings.Fields(webhookDeliveryColumns), ""), ","), "url", "secret")
+	now := time.Now().UTC()
+	delivery := func(id string, attempts int, redeliveredAttempts int) []driver.Value {
+		return []driver.Value{id, "sub", "event-" + id, "limit_offer.created", []byte(`{}`), string(models.DeliveryPending), attempts,
+			redeliveredAttempts, now, nil, now, nil, "https://partner.example.com/" + id, "secret"}
+	}
+	// the lease is committed before the first post, and every attempt is recorded in a transaction of its own
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE webhook_delivery SET leased_until").WithArgs(models.DeliveryPending, 10, float64(300)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(delivery("ok", 0, 0)...).AddRow(delivery("retried", 1, 0)...).
+			AddRow(delivery("dead", 4, 0)...).AddRow(delivery("redelivered", 5, 5)...).AddRow(delivery("lost", 0, 0)...))
+	mock.ExpectCommit()
+
+	// case 1 : a successful attempt delivers the delivery
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempt").WithArgs("ok", 1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE webhook_delivery SET status = \\$1, attempts = \\$2, last_error = NULL, delivered_at = now\\(\\), leased_until = NULL").
+		WithArgs(models.DeliveryDelivered, 1, "ok", models.DeliveryPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// case 2 : a failed attempt under the max attempts is retried after the backoff of its attempts
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempt").WithArgs("retried", 2, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("UPDATE webhook_delivery SET attempts = \\$1, last_error = \\$2, next_attempt_at = \\$3, leased_until = NULL").
+		WithArgs(2, "subscription responded with 503", retryAt(2*time.Hour), "retried", models.DeliveryPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// case 3 : the failed attempt reaching the max attempts dead-letters the delivery
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempt").WithArgs("dead", 5, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("UPDATE webhook_delivery SET status = \\$1, attempts = \\$2, last_error = \\$3, leased_until = NULL").
+		WithArgs(models.DeliveryDeadLetter, 5, "subscription responded with 503", "dead", models.DeliveryPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// case 4 : the attempt of a redelivered delivery is numbered after its log and retried with a fresh budget
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempt").WithArgs("redelivered", 6, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("UPDATE webhook_delivery SET attempts = \\$1, last_error = \\$2, next_attempt_at = \\$3, leased_until = NULL").
+		WithArgs(6, "subscription responded with 503", retryAt(time.Hour), "redelivered", models.DeliveryPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// case 5 : an attempt which can not be recorded stops the batch, the deliveries left wait for their lease to run out
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempt").WithArgs("lost", 1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	var posted []string
+	deliver := func(delivery models.WebhookDelivery, subscription models.WebhookSubscription) models.WebhookDeliveryAttempt {
+		posted = append(posted, delivery.ID)
+		statusCode := http.StatusOK
+		attempt := models.WebhookDeliveryAttempt{DeliveryID: delivery.ID, AttemptedAt: time.Now().UTC(), StatusCode: &statusCode}
+		if delivery.ID != "ok" {
+			statusCode = http.StatusServiceUnavailable
+			message := "subscription responded with 503"
+			attempt.Error = &message
+		}
+		return attempt
+	}
+	retryAfter := func(attempts int) time.Duration { return time.Duration(attempts) * time.Hour }
+
+	attempted, creditCardErr := p.DispatchWebhookDeliveries(utils.NewBackgroundContext(), 10, 5, 5*time.Minute, deliver, retryAfter)
+	assert.NotNil(t, creditCardErr)
+	assert.Equal(t, 4, attempted)
+	assert.Equal(t, []string{"ok", "retried", "dead", "redelivered", "lost"}, posted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	utils.InitLogClient()
+	conn, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer conn.Close()
+	p := postgres{db: conn}
+
+	columns := strings.Split(strings.Join(strings.Fields(webhookDeliveryColumns), ""), ",")
+	now := time.Now().UTC()
+	row := func(status models.WebhookDeliveryStatus, redeliveredAttempts int) *sqlmock.Rows {
+		return sqlmock.NewRows(columns).AddRow("dead", "sub", "event-dead", "limit_offer.created", []byte(`{}`), string(status), 5,
+			redeliveredAttempts, now, "subscription responded with 503", now, nil)
+	}
+
+	// the attempts are kept, the ones made so far are no longer counted against the budget
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM webhook_delivery WHERE id = \\$1 FOR UPDATE").WithArgs("dead").
+		WillReturnRows(row(models.DeliveryDeadLetter, 0))
+	mock.ExpectQuery("UPDATE webhook_delivery SET status = \\$1, redelivered_attempts = attempts, next_attempt_at = now\\(\\)").
+		WithArgs(models.DeliveryPending, "dead").WillReturnRows(row(models.DeliveryPending, 5))
+	expectAuditEvent(mock)
+	mock.ExpectCommit()
+
+	redelivered, creditCardErr := p.RedeliverWebhookDelivery(utils.NewBackgroundContext(), "dead")
+	assert.Nil(t, creditCardErr)
+	assert.Equal(t, 5, redelivered.Attempts)
+	assert.Equal(t, 5, redelivered.RedeliveredAttempts)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,129 @@
+// Package health answers the probes of the orchestrator: /healthz as long as the process serves requests and
+// /readyz while the database is reachable, its migrations are applied, the background workers run and no shutdown
+// is under way.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// results of the checks of the readiness probe
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// Database is the part of the db layer the readiness probe checks
+type Database interface {
+	Ping(ctx context.Context) error
+	CheckMigrations(ctx context.Context) error
+}
+
+// Probe holds the state the readiness of the application is derived from
+type Probe struct {
+	db      Database
+	timeout time.Duration
+
+	draining atomic.Bool
+	mu       sync.Mutex
+	// whether each background worker runs
+	workers map[string]bool
+}
+
+// NewProbe returns the probe checking db, each check of the database gives up after timeout
+func NewProbe(db Database, timeout time.Duration) *Probe {
+	return &Probe{db: db, timeout: timeout, workers: make(map[string]bool)}
+}
+
+// WorkerStarted records the worker as running, the returned function records it as stopped
+func (p *Probe) WorkerStarted(name string) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.workers[name] = true
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.workers[name] = false
+	}
+}
+
+// Drain makes the readiness probe fail from now on, so that no new traffic is routed to the application
+// while it shuts down
+func (p *Probe) Drain() {
+	p.draining.Store(true)
+}
+
+// Live answers the liveness probe, the process is alive as long as it serves it
+func (p *Probe) Live() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": statusOK})
+	}
+}
+
+// Ready answers the readiness probe with the result of every check, it fails with 503 when one of them fails
+func (p *Probe) Ready() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		checks := p.check(ctx.Request.Context())
+		status, code := statusOK, http.StatusOK
+		for _, result := range checks {
+			if result != statusOK {
+				status, code = statusUnavailable, http.StatusServiceUnavailable
+				break
+			}
+		}
+		ctx.JSON(code, gin.H{"status": status, "checks": checks})
+	}
+}
+
+// check runs the checks of the readiness probe. The probe needs no credentials, the errors of the database are
+// logged rather than answered as they may name the host, the user or the statements.
+func (p *Probe) check(ctx context.Context) map[string]string {
+	checks := map[string]string{
+		"shutdown":   statusOK,
+		"database":   statusOK,
+		"migrations": statusOK,
+		"workers":    statusOK,
+	}
+	if p.draining.Load() {
+		checks["shutdown"] = "draining"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := p.db.Ping(ctx); err != nil {
+		utils.Logger.Error("the database is not ready", zap.Error(err))
+		checks["database"] = statusUnavailable
+		checks["migrations"] = "unknown"
+	} else if err := p.db.CheckMigrations(ctx); err != nil {
+		utils.Logger.Error("the migrations are not ready", zap.Error(err))
+		checks["migrations"] = statusUnavailable
+	}
+
+	if stopped := p.stoppedWorkers(); len(stopped) > 0 {
+		checks["workers"] = "stopped " + strings.Join(stopped, ", ")
+	}
+	return checks
+}
+
+func (p *Probe) stoppedWorkers() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var stopped []string
+	for name, running := range p.workers {
+		if !running {
+			stopped = append(stopped, name)
+		}
+	}
+	sort.Strings(stopped)
+	return stopped
+}
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDatabase struct {
+	pingDelay  time.Duration
+	migrations error
+}
+
+func (db *fakeDatabase) Ping(ctx context.Context) error {
+	select {
+	case <-time.After(db.pingDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (db *fakeDatabase) CheckMigrations(context.Context) error {
+	return db.migrations
+}
+
+type probeResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func probe(t *testing.T, router *gin.Engine, path string) (int, probeResponse) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	var response probeResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return recorder.Code, response
+}
+
+func TestProbe(t *testing.T) {
+	utils.InitLogClient()
+	db := &fakeDatabase{}
+	p := NewProbe(db, 50*time.Millisecond)
+	router := gin.New()
+	router.GET("/healthz", p.Live())
+	router.GET("/readyz", p.Ready())
+	stopped := p.WorkerStarted("outbox_relay")
+
+	code, response := probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]string{"shutdown": "ok", "database": "ok", "migrations": "ok", "workers": "ok"}, response.Checks)
+
+	// the database does not answer within the timeout
+	db.pingDelay = time.Second
+	code, response = probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", response.Status)
+	assert.Equal(t, "unavailable", response.Checks["database"])
+
+	db.pingDelay = 0
+	// the errors are logged, the probe does not answer them
+	db.migrations = errors.New("the 013 migration is not applied")
+	code, response = probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", response.Checks["migrations"])
+
+	db.migrations = nil
+	stopped()
+	code, response = probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "stopped outbox_relay", response.Checks["workers"])
+
+	// the readiness fails once the shutdown starts while the process is still alive
+	p.WorkerStarted("outbox_relay")
+	p.Drain()
+	code, response = probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "draining", response.Checks["shutdown"])
+	code, response = probe(t, router, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", response.Status)
+}
@@ -5,3 +5,8 @@ type CreditCardError struct {
 	Message string `json:"message"`
 	Trace   string `json:"trace"`
 }
+
+// Error makes CreditCardError usable as an error, e.g. to abort a db transaction because of a business rule
+func (e *CreditCardError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,143 @@
+// Package metrics holds the prometheus metrics of the application: the latency and the status of the http requests,
+// the latency of the sql statements, the connection pool of the database and the outcomes of the limit offers.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "credit_card_offer_limit"
+
+// outcomes of the limit offers
+const (
+	OfferCreated  = "created"
+	OfferAccepted = "accepted"
+	OfferRejected = "rejected"
+	OfferExpired  = "expired"
+)
+
+// route of the requests which did not match any endpoint, so that scanning the api does not create a series per path
+const unmatchedRoute = "unmatched"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of http requests by route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the http requests by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latency of the sql statements by their command, e.g. SELECT or UPDATE, and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "outcome"})
+
+	limitOffers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "limit_offers_total",
+		Help:      "Number of limit offers created, accepted, rejected and expired by limit type.",
+	}, []string{"outcome", "limit_type"})
+
+	validationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "validation_failures_total",
+		Help:      "Number of requests rejected by the input validation by route and reason.",
+	}, []string{"route", "reason"})
+
+	rateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Number of requests rejected by the rate limits by route and key.",
+	}, []string{"route", "key"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpRequestDuration, dbQueryDuration, limitOffers, validationFailures, rateLimitedRequests,
+	)
+}
+
+// RegisterDB exposes the statistics of the connection pool of the database, sql.DB.Stats()
+func RegisterDB(db *sql.DB, name string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// This function serves the metrics on the http api
+func ServeMetrics() func(ctx *gin.Context) {
+	handler := Handler()
+	return func(ctx *gin.Context) {
+		handler.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
+
+// Middleware records the latency and the status code of every request under the route it matched
+func Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := ctx.Request.Method
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+		httpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveQuery records the latency of a sql statement under its command, e.g. SELECT
+func ObserveQuery(operation string, duration time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	dbQueryDuration.WithLabelValues(operation, outcome).Observe(duration.Seconds())
+}
+
+// LimitOfferOutcome counts a limit offer which was created, accepted, rejected or expired, an offer is created once
+// it is PENDING, i.e. offered to the customer, so the one awaiting approval is created when approved
+func LimitOfferOutcome(outcome string, limitOffer models.LimitOffer) {
+	limitType := ""
+	if limitOffer.LimitType != nil {
+		limitType = string(*limitOffer.LimitType)
+	}
+	limitOffers.WithLabelValues(outcome, limitType).Inc()
+}
+
+// ValidationFailure counts a request rejected by the input validation, the reason is the error message
+// which must not contain values of the request
+func ValidationFailure(route string, reason string) {
+	validationFailures.WithLabelValues(route, reason).Inc()
+}
+
+// RateLimited counts a request rejected by the limit of the route keyed by the key, client, ip or account
+func RateLimited(route string, key string) {
+	rateLimitedRequests.WithLabelValues(route, key).Inc()
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/v1/accounts/:account_id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNotFound)
+	})
+
+	for _, path := range []string{"/v1/accounts/1", "/v1/accounts/2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// the requests are counted under the route, not the path
+	assert.Equal(t, 2.0, testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/v1/accounts/:account_id", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")))
+}
+
+func TestObserveQuery(t *testing.T) {
+	ObserveQuery("UPDATE", time.Millisecond, nil)
+	ObserveQuery("UPDATE", time.Millisecond, errors.New("serialization failure"))
+	assert.Equal(t, 2, testutil.CollectAndCount(dbQueryDuration))
+}
+
+func TestCounters(t *testing.T) {
+	limitType := models.AccountLimit
+	LimitOfferOutcome(OfferAccepted, models.LimitOffer{LimitType: &limitType})
+	ValidationFailure("/v1/limit-offer", "account_id is required")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(limitOffers.WithLabelValues(OfferAccepted, string(models.AccountLimit))))
+	assert.Equal(t, 1.0, testutil.ToFloat64(validationFailures.WithLabelValues("/v1/limit-offer", "account_id is required")))
+
+	// the counters are exposed by the handler
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `credit_card_offer_limit_limit_offers_total{limit_type="ACCOUNT_LIMIT",outcome="accepted"} 1`)
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxLoggedBody is the size of the bodies added to the access log, the larger ones are left out
+const maxLoggedBody = 4096
+
+// AccessLog logs every request once it is served with its status, latency and response size. The query string
+// is left out as it may carry personal data, the bodies are logged redacted when configured.
+func AccessLog() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		var (
+			requestBody []byte
+			response    *bodyRecorder
+		)
+		logBodies := config.GetConfig().Logging.LogBodies
+		if logBodies {
+			requestBody = readRequestBody(ctx)
+			response = &bodyRecorder{ResponseWriter: ctx.Writer}
+			ctx.Writer = response
+		}
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", ctx.Request.Method),
+			zap.String("path", ctx.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("size", ctx.Writer.Size()),
+			zap.String("client_ip", ctx.ClientIP()),
+		}
+		if lastError := ctx.Errors.Last(); lastError != nil {
+			fields = append(fields, zap.String("error", lastError.Error()))
+		}
+		if logBodies {
+			fields = append(fields,
+				zap.String("request_body", loggedBody(requestBody, len(requestBody))),
+				zap.String("response_body", loggedBody(response.body.Bytes(), ctx.Writer.Size())))
+		}
+
+		logger := utils.RequestLogger(ctx)
+		switch {
+		case status >= 500:
+			logger.Error("request served", fields...)
+		case status >= 400:
+			logger.Warn("request served", fields...)
+		default:
+			logger.Info("request served", fields...)
+		}
+	}
+}
+
+// readRequestBody reads the body of the request and puts it back for the handlers
+func readRequestBody(ctx *gin.Context) []byte {
+	if ctx.Request.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(ctx.Request.Body)
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// loggedBody returns the body redacted, or notes its size when it is too large to be logged
+func loggedBody(body []byte, size int) string {
+	if size > maxLoggedBody {
+		return fmt.Sprintf("[%v bytes omitted]", size)
+	}
+	return utils.RedactBody(body)
+}
+
+// bodyRecorder keeps the first maxLoggedBody bytes of the response, a larger response is not kept at all
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *bodyRecorder) Write(data []byte) (int, error) {
+	r.record(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *bodyRecorder) WriteString(data string) (int, error) {
+	r.record([]byte(data))
+	return r.ResponseWriter.WriteString(data)
+}
+
+func (r *bodyRecorder) record(data []byte) {
+	if r.truncated {
+		return
+	}
+	if r.body.Len()+len(data) > maxLoggedBody {
+		r.truncated = true
+		r.body.Reset()
+		return
+	}
+	r.body.Write(data)
+}
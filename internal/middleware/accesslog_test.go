@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	utils.Logger = zap.New(core)
+
+	router := gin.New()
+	router.Use(AccessLog())
+	router.GET("/v1/get_account/:account_id", ValidateInputRequest(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	// the invalid account id is rejected by the validation, a transaction id is generated for the request
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/get_account/1?email=jane@example.com", nil))
+
+	entries := logs.FilterMessage("request served").All()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, zap.WarnLevel, entries[0].Level)
+	assert.Equal(t, "/v1/get_account/1", fields["path"])
+	assert.Equal(t, int64(http.StatusBadRequest), fields["status"])
+	assert.Equal(t, constants.InvalidAccountID, fields["error"])
+	assert.Equal(t, "/v1/get_account/:account_id", fields["route"])
+	assert.Len(t, fields["transaction_id"], 36)
+}
+
+func TestAccessLogBodies(t *testing.T) {
+	config.SetConfig(config.GlobalConfig{Logging: config.Logging{LogBodies: true}})
+	t.Cleanup(func() { config.SetConfig(config.GlobalConfig{}) })
+	assert.NoError(t, utils.InitLogger(config.Logging{Level: "info", Redaction: []config.RedactionRule{
+		{Field: "email", Action: constants.DropRedaction},
+		{Field: "customer_id", Action: constants.MaskRedaction, Keep: 4},
+	}}))
+	core, logs := observer.New(zap.InfoLevel)
+	utils.Logger = zap.New(core)
+
+	router := gin.New()
+	router.Use(AccessLog())
+	router.POST("/v1/accounts", func(ctx *gin.Context) {
+		var account map[string]interface{}
+		assert.NoError(t, ctx.ShouldBindJSON(&account))
+		ctx.JSON(http.StatusCreated, account)
+	})
+	body := `{"customer_id":"7d1e2f3a","email":"jane@example.com","account_limit":5000}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/accounts", strings.NewReader(body)))
+
+	fields := logs.FilterMessage("request served").All()[0].ContextMap()
+	// the handler got the body as it was sent, the log the redacted one
+	assert.Equal(t, `{"account_limit":5000,"customer_id":"****2f3a"}`, fields["request_body"])
+	assert.Equal(t, `{"account_limit":5000,"customer_id":"****2f3a"}`, fields["response_body"])
+}
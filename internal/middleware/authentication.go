@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/auth"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Authenticate identifies the caller with the authenticator and stores the principal in the context,
+// requests without valid credentials are rejected with 401.
+func Authenticate(authenticator auth.Authenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, err := authenticator.Authenticate(ctx)
+
+		var creditCardErr *limitoffererror.CreditCardError
+		if errors.As(err, &creditCardErr) {
+			utils.RequestLogger(ctx).Error("unable to verify the credentials", zap.Error(err))
+			utils.RespondWithError(ctx, creditCardErr.Code, creditCardErr.Message)
+			return
+		}
+		if err != nil || principal == nil {
+			if err == nil {
+				err = auth.ErrInvalidCredentials
+			}
+			utils.RequestLogger(ctx).Info("request is not authenticated", zap.Error(err))
+			ctx.Header(constants.WWWAuthenticate, `Bearer realm="credit-card-offer-limit"`)
+			utils.RespondWithError(ctx, http.StatusUnauthorized, constants.Unauthorized)
+			return
+		}
+
+		ctx.Set(constants.Principal, *principal)
+		ctx.Next()
+	}
+}
+
+// RequireScopes rejects the request with 403 unless the principal was granted all the scopes
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := utils.GetPrincipal(ctx)
+		for _, scope := range scopes {
+			if !ok || !principal.HasScope(scope) {
+				utils.RequestLogger(ctx).Info(fmt.Sprintf("client %v is missing the %v scope", principal.ClientID, scope))
+				utils.RespondWithError(ctx, http.StatusForbidden, constants.Forbidden)
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
@@ -1,17 +1,23 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
 	"github.com/ankit/project/credit-card-offer-limit/internal/models"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // This function gets the unique transactionID
@@ -21,6 +27,8 @@ func getTransactionID(c *gin.Context) string {
 	if err != nil {
 		transactionID = uuid.New().String()
 		c.Set(constants.TransactionID, transactionID)
+		// the service and the db layers read the transaction id from the header
+		c.Request.Header.Set(constants.TransactionID, transactionID)
 	}
 	return transactionID
 }
@@ -28,148 +36,177 @@ func getTransactionID(c *gin.Context) string {
 func ValidateInputRequest() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// get the transactionID from headers if not present create a new.
-		transactionID := getTransactionID(ctx)
-		fmt.Printf("TimeStamp : %v", time.Now().UTC)
+		getTransactionID(ctx)
 		path := ctx.Request.URL.String()
 		switch {
 		case strings.Contains(path, constants.CreateAccount):
-			validateCreateAccountInput(ctx, transactionID)
+			validateCreateAccountInput(ctx)
 		case strings.Contains(path, constants.GetAccount):
-			validateGetAccountInput(ctx, transactionID)
+			validateGetAccountInput(ctx)
 		case strings.Contains(path, constants.CreateLimitOffer):
-			validateCreateLimitOfferInput(ctx, transactionID)
+			validateCreateLimitOfferInput(ctx)
 		case strings.Contains(path, constants.ListActiveLimitOffers):
-			validateListActiveLimitOffersInput(ctx, transactionID)
+			validateListActiveLimitOffersInput(ctx)
 		case strings.Contains(path, constants.UpdateLimitOfferStatus):
-			validateUpdateLimitOfferStatusInput(ctx, transactionID)
+			validateUpdateLimitOfferStatusInput(ctx)
+		case strings.Contains(path, constants.ExportLimitOffers):
+			validateExportLimitOffersInput(ctx)
+		case strings.Contains(path, constants.GetLimitOffer):
+			validateGetLimitOfferInput(ctx)
+		case strings.Contains(path, constants.ListLimitOffers):
+			validateListLimitOffersInput(ctx)
+		case strings.Contains(path, constants.CancelLimitOffer), strings.Contains(path, constants.ApproveLimitOffer),
+			strings.Contains(path, constants.DeclineLimitOffer):
+			validateGetLimitOfferInput(ctx)
+		case strings.Contains(path, constants.ListAuditEvents):
+			validateListAuditEventsInput(ctx)
+		case strings.Contains(path, constants.RevertAccountLimit):
+			validateRevertAccountLimitInput(ctx)
+		case strings.Contains(path, constants.StreamLimitOfferEvents):
+			validateGetAccountInput(ctx)
+		case strings.Contains(path, constants.CreateWebhookSub):
+			validateCreateWebhookSubscriptionInput(ctx)
+		case strings.Contains(path, constants.ListWebhookDeliveries):
+			validateListWebhookDeliveriesInput(ctx)
+		case strings.Contains(path, constants.RedeliverWebhook):
+			validateRedeliverWebhookDeliveryInput(ctx)
+		}
+		if ctx.IsAborted() {
+			metrics.ValidationFailure(ctx.FullPath(), validationFailureReason(ctx))
 		}
-		fmt.Println("txid : ", transactionID)
 
 		ctx.Next()
 	}
 }
 
-func validateCreateAccountInput(ctx *gin.Context, txid string) {
+// validationFailureReason returns the message of the error the request was rejected with
+func validationFailureReason(ctx *gin.Context) string {
+	if lastError := ctx.Errors.Last(); lastError != nil {
+		return lastError.Error()
+	}
+	return "unknown"
+}
+
+func validateCreateAccountInput(ctx *gin.Context) {
 	var accountInfo models.Account
 	err := ctx.ShouldBindBodyWith(&accountInfo, binding.JSON)
 	if err != nil {
-		utils.Logger.Error("error while unmarshaling the request field for create account data validation")
+		utils.RequestLogger(ctx).Error("error while unmarshaling the request field for create account data validation", zap.Error(err))
 		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidBodyCreateAccount)
 		return
 	}
 
-	if accountInfo.AccountLimit == nil {
-		utils.Logger.Error(fmt.Sprintf("account_limit field is missing while creating an account, txid : %v", txid))
-		errMessage := "account_limit field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
+	err = ValidateCreateAccountFields(accountInfo)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("%v while creating an account", err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
+}
+
+// This function validates the fields of an account creation request, it is shared by the
+// create account endpoint and the bulk import of accounts.
+func ValidateCreateAccountFields(accountInfo models.Account) error {
+	if accountInfo.AccountLimit == nil {
+		return errors.New("account_limit field is missing")
+	}
 	if accountInfo.LastAccountLimit == nil {
-		utils.Logger.Error(fmt.Sprintf("last_account_limit field is missing while creating an account, txid : %v", txid))
-		errMessage := "last_account_limit field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("last_account_limit field is missing")
 	}
 	if accountInfo.PerTransactionLimit == nil {
-		utils.Logger.Error(fmt.Sprintf("per_transaction_limit field is missing while creating an account, txid : %v", txid))
-		errMessage := "per_transaction_limit field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("per_transaction_limit field is missing")
 	}
 	if accountInfo.LastPerTransactionLimit == nil {
-		utils.Logger.Error(fmt.Sprintf("last_per_transaction_limit field is missing while creating an account, txid : %v", txid))
-		errMessage := "last_per_transaction_limit field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("last_per_transaction_limit field is missing")
 	}
 
 	if *accountInfo.AccountLimit < *accountInfo.LastAccountLimit {
-		utils.Logger.Error(fmt.Sprintf("amount_limit field should be greater than or equal to last_amount_limit while creating an account, txid : %v", txid))
-		errMessage := "amount_limit is less than last_amount_limit"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("amount_limit is less than last_amount_limit")
 	}
 
 	if *accountInfo.PerTransactionLimit < *accountInfo.LastPerTransactionLimit {
-		utils.Logger.Error(fmt.Sprintf("per_transaction_limit field should be greater than or equal to last_per_transaction_limit while creating an account, txid : %v", txid))
-		errMessage := "per_transaction_limit is less than last_per_transaction_limit"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("per_transaction_limit is less than last_per_transaction_limit")
 	}
+
+	if accountInfo.Email != nil {
+		if address, err := mail.ParseAddress(*accountInfo.Email); err != nil || address.Address != *accountInfo.Email {
+			return errors.New("email is not a valid email address")
+		}
+	}
+	if accountInfo.Locale != constants.EmptyString && !localePattern.MatchString(accountInfo.Locale) {
+		return errors.New("locale should be a language code optionally followed by a region, e.g. en or en-GB")
+	}
+	return nil
 }
 
-func validateGetAccountInput(ctx *gin.Context, txid string) {
+// language code optionally followed by a region code, e.g. en or pt-BR
+var localePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+func validateGetAccountInput(ctx *gin.Context) {
 	accountID := ctx.Param(constants.AccountID)
-	fmt.Println("accountID : ", accountID)
-	utils.Logger.Info(fmt.Sprintf("request received for get %v account, txid : %v", accountID, txid))
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("request received for get %v account", accountID))
 	_, erraccountUUID := uuid.Parse(accountID)
 	if erraccountUUID != nil {
-		utils.Logger.Error(fmt.Sprintf("Error parsing the %v accountID, txid : %v", accountID, txid))
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v accountID", accountID))
 		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidAccountID)
 		return
 	}
 }
 
-func validateCreateLimitOfferInput(ctx *gin.Context, txid string) {
+func validateCreateLimitOfferInput(ctx *gin.Context) {
 	var limitOffer models.LimitOffer
 	err := ctx.ShouldBindBodyWith(&limitOffer, binding.JSON)
 	if err != nil {
-		utils.Logger.Error("error while unmarshaling the request field for create limit offer data validation")
+		utils.RequestLogger(ctx).Error("error while unmarshaling the request field for create limit offer data validation", zap.Error(err))
 		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidBodyCreateLimitOffer)
 		return
 	}
 
-	if limitOffer.LimitType == nil {
-		utils.Logger.Error(fmt.Sprintf("limit_type field is missing while creating limit offer, txid : %v", txid))
-		errMessage := "limit_type field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
+	err = ValidateCreateLimitOfferFields(limitOffer)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("%v while creating limit offer", err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
+}
+
+// This function validates the fields of a limit offer creation request, it is shared by the
+// create limit offer endpoint and the bulk import of limit offers.
+func ValidateCreateLimitOfferFields(limitOffer models.LimitOffer) error {
+	if limitOffer.LimitType == nil {
+		return errors.New("limit_type field is missing")
+	}
 	if limitOffer.AccountID == nil {
-		utils.Logger.Error(fmt.Sprintf("account_id field is missing while creating limit offer, txid : %v", txid))
-		errMessage := "account_id field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("account_id field is missing")
 	}
 	if limitOffer.NewLimit == nil {
-		utils.Logger.Error(fmt.Sprintf("new_limit field is missing while creating limit offer, txid : %v", txid))
-		errMessage := "new_limit field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("new_limit field is missing")
 	}
 	if limitOffer.OfferActivationTime == nil {
-		utils.Logger.Error(fmt.Sprintf("offer_activation_time field is missing while creating limit offer, txid : %v", txid))
-		errMessage := "offer_activation_time field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("offer_activation_time field is missing")
 	}
 	if limitOffer.OfferExpiryTime == nil {
-		utils.Logger.Error(fmt.Sprintf("offer_expiry_time field is missing while creating limit offer, txid : %v", txid))
-		errMessage := "offer_expiry_time field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("offer_expiry_time field is missing")
 	}
 	// offer_expiry_time field should be greater than offer_activation_time
 	isExpireTimeBeforeActivationTime := limitOffer.OfferExpiryTime.Before(*limitOffer.OfferActivationTime)
 	if isExpireTimeBeforeActivationTime {
-		utils.Logger.Error(fmt.Sprintf("offer_expiry_time field should be greater than offer_activation_time, txid : %v", txid))
-		errMessage := "offer_expiry_time field should be greater than offer_activation_time"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
-		return
+		return errors.New("offer_expiry_time field should be greater than offer_activation_time")
 	}
+	return nil
 }
 
-func validateListActiveLimitOffersInput(ctx *gin.Context, txid string) {
+func validateListActiveLimitOffersInput(ctx *gin.Context) {
 	var activeLimitOffer models.ActiveLimitOffer
 	err := ctx.ShouldBindBodyWith(&activeLimitOffer, binding.JSON)
 	if err != nil {
-		utils.Logger.Error("error while unmarshaling the request field to list active limit offer data validation")
+		utils.RequestLogger(ctx).Error("error while unmarshaling the request field to list active limit offer data validation", zap.Error(err))
 		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidBody)
 		return
 	}
 
 	if activeLimitOffer.AccountID == "" {
-		utils.Logger.Error(fmt.Sprintf("account_id field is missing to list active limit offers, txid : %v", txid))
+		utils.RequestLogger(ctx).Error("account_id field is missing to list active limit offers")
 		errMessage := "account_id field is missing"
 		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
 		return
@@ -177,43 +214,303 @@ func validateListActiveLimitOffersInput(ctx *gin.Context, txid string) {
 
 	_, erraccountUUID := uuid.Parse(activeLimitOffer.AccountID)
 	if erraccountUUID != nil {
-		utils.Logger.Error(fmt.Sprintf("Error parsing the %v accountID, txid : %v", activeLimitOffer.AccountID, txid))
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v accountID", activeLimitOffer.AccountID))
 		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidAccountID)
 		return
 	}
 }
 
-func validateUpdateLimitOfferStatusInput(ctx *gin.Context, txid string) {
+func validateUpdateLimitOfferStatusInput(ctx *gin.Context) {
 	var updateLimitOfferStatus models.UpdateLimitOfferStatus
 	err := ctx.ShouldBindBodyWith(&updateLimitOfferStatus, binding.JSON)
 	if err != nil {
-		utils.Logger.Error("error while unmarshaling the request field to update limit offer status data validation")
+		utils.RequestLogger(ctx).Error("error while unmarshaling the request field to update limit offer status data validation", zap.Error(err))
 		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidBodyUpdateLimitOfferStatus)
 		return
 	}
-	utils.Logger.Info(fmt.Sprintf("received request for account creation is unmarshalled successfully, txid : %v", txid))
+	utils.RequestLogger(ctx).Info("received request for account creation is unmarshalled successfully")
 
-	if updateLimitOfferStatus.LimitOfferID == "" {
-		utils.Logger.Error(fmt.Sprintf("limit offer id is missing, txid : %v", txid))
-		errMessage := "limit_offer_id field is missing"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
+	err = ValidateUpdateLimitOfferStatusFields(updateLimitOfferStatus)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("%v while updating limit offer status", err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
+}
+
+// This function validates the fields of a limit offer status update request, it is shared by the
+// update limit offer status endpoint and the bulk import of offer decisions.
+func ValidateUpdateLimitOfferStatusFields(updateLimitOfferStatus models.UpdateLimitOfferStatus) error {
+	if updateLimitOfferStatus.LimitOfferID == "" {
+		return errors.New("limit_offer_id field is missing")
+	}
 
 	_, errlimitOfferUUID := uuid.Parse(updateLimitOfferStatus.LimitOfferID)
 	if errlimitOfferUUID != nil {
-		utils.Logger.Error(fmt.Sprintf("Error parsing the %v limitOfferID, txid : %v", updateLimitOfferStatus.LimitOfferID, txid))
-		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidOfferLimitID)
-		return
+		return errors.New(constants.InvalidOfferLimitID)
 	}
 
 	switch updateLimitOfferStatus.Status {
 	case string(models.Accepted), string(models.Rejected):
 	default:
-		utils.Logger.Error(fmt.Sprintf("invalid status is provided, txid : %v", txid))
+		return errors.New("received status is not supported")
+	}
+
+	switch updateLimitOfferStatus.DecisionChannel {
+	case models.MobileChannel, models.WebChannel, models.AgentChannel, models.APIChannel:
+	case "":
+		return errors.New("decision_channel field is missing")
+	default:
+		return errors.New("received decision_channel is not supported")
+	}
+	return nil
+}
 
-		errMessage := "received status is not supported"
-		utils.RespondWithError(ctx, http.StatusBadRequest, errMessage)
+func validateExportLimitOffersInput(ctx *gin.Context) {
+	var filter models.LimitOfferFilter
+	err := ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while binding the query params to export limit offers")
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidExportLimitOffersQuery)
 		return
 	}
+
+	if filter.AccountID != "" {
+		_, erraccountUUID := uuid.Parse(filter.AccountID)
+		if erraccountUUID != nil {
+			utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v accountID", filter.AccountID))
+			utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidAccountID)
+			return
+		}
+	}
+
+	err = ValidateLimitOfferFilter(filter)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("%v while exporting limit offers", err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+func validateGetLimitOfferInput(ctx *gin.Context) {
+	limitOfferID := ctx.Param(constants.LimitOfferID)
+	_, errlimitOfferUUID := uuid.Parse(limitOfferID)
+	if errlimitOfferUUID != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v limitOfferID", limitOfferID))
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidOfferLimitID)
+		return
+	}
+}
+
+func validateRevertAccountLimitInput(ctx *gin.Context) {
+	accountID := ctx.Param(constants.AccountID)
+	_, erraccountUUID := uuid.Parse(accountID)
+	if erraccountUUID != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v accountID", accountID))
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidAccountID)
+		return
+	}
+
+	var revertAccountLimit models.RevertAccountLimit
+	err := ctx.ShouldBindBodyWith(&revertAccountLimit, binding.JSON)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while unmarshaling the request field for revert account limit data validation")
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidBodyRevertAccountLimit)
+		return
+	}
+
+	switch revertAccountLimit.LimitType {
+	case models.AccountLimit, models.PerTransactionLimit:
+	default:
+		utils.RequestLogger(ctx).Error("received limit_type is not supported while reverting account limit")
+		utils.RespondWithError(ctx, http.StatusBadRequest, "received limit_type is not supported")
+		return
+	}
+}
+
+func validateListAuditEventsInput(ctx *gin.Context) {
+	var filter models.AuditEventFilter
+	err := ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while binding the query params to list audit events")
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidListAuditEventsQuery)
+		return
+	}
+
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		utils.RequestLogger(ctx).Error("to is before from while listing audit events")
+		utils.RespondWithError(ctx, http.StatusBadRequest, "to should not be before from")
+		return
+	}
+
+	if filter.PageSize < 0 || filter.PageSize > constants.MaxPageSize {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("invalid page_size %v is provided to list audit events", filter.PageSize))
+		utils.RespondWithError(ctx, http.StatusBadRequest, fmt.Sprintf("page_size should be between 1 and %v", constants.MaxPageSize))
+		return
+	}
+}
+
+func validateListLimitOffersInput(ctx *gin.Context) {
+	accountID := ctx.Param(constants.AccountID)
+	_, erraccountUUID := uuid.Parse(accountID)
+	if erraccountUUID != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v accountID", accountID))
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidAccountID)
+		return
+	}
+
+	var listLimitOffers models.ListLimitOffers
+	err := ctx.ShouldBindQuery(&listLimitOffers)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while binding the query params to list limit offers")
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidListLimitOffersQuery)
+		return
+	}
+
+	err = ValidateListLimitOffersFields(listLimitOffers)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("%v while listing limit offers", err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// This function validates the filter, sorting and page size of a limit offer listing, it is shared by the
+// list limit offers endpoint and the grpc api.
+func ValidateListLimitOffersFields(listLimitOffers models.ListLimitOffers) error {
+	if err := ValidateLimitOfferFilter(listLimitOffers.LimitOfferFilter); err != nil {
+		return err
+	}
+
+	switch listLimitOffers.SortBy {
+	case "", constants.SortByCreatedAt, constants.SortByOfferActivationTime, constants.SortByOfferExpiryTime, constants.SortByNewLimit:
+	default:
+		return errors.New("sort_by is not supported")
+	}
+
+	switch listLimitOffers.SortOrder {
+	case "", constants.SortOrderAsc, constants.SortOrderDesc:
+	default:
+		return errors.New("sort_order should be asc or desc")
+	}
+
+	if listLimitOffers.PageSize < 0 || listLimitOffers.PageSize > constants.MaxPageSize {
+		return fmt.Errorf("page_size should be between 1 and %v", constants.MaxPageSize)
+	}
+	return nil
+}
+
+func validateCreateWebhookSubscriptionInput(ctx *gin.Context) {
+	var subscription models.WebhookSubscription
+	err := ctx.ShouldBindBodyWith(&subscription, binding.JSON)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while unmarshaling the request field for webhook subscription data validation")
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidBodyWebhookSubscription)
+		return
+	}
+
+	err = ValidateWebhookSubscriptionFields(subscription)
+	if err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("%v while creating a webhook subscription", err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+}
+
+// This function validates the url and the event types of a webhook subscription
+func ValidateWebhookSubscriptionFields(subscription models.WebhookSubscription) error {
+	parsedURL, err := url.Parse(subscription.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == constants.EmptyString {
+		return errors.New("url should be an absolute http or https url")
+	}
+
+	for _, eventType := range subscription.EventTypes {
+		switch eventType {
+		case constants.EventLimitOfferCreated, constants.EventLimitOfferAccepted, constants.EventLimitOfferRejected,
+			constants.EventLimitOfferExpired, constants.EventLimitOfferSuperseded, constants.EventLimitOfferCancelled,
+			constants.EventLimitOfferApproved, constants.EventLimitOfferDeclined:
+		default:
+			return errors.New("received event type is not supported")
+		}
+	}
+	return nil
+}
+
+func validateListWebhookDeliveriesInput(ctx *gin.Context) {
+	subscriptionID := ctx.Param(constants.SubscriptionID)
+	if _, err := uuid.Parse(subscriptionID); err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v subscriptionID", subscriptionID))
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidSubscriptionID)
+		return
+	}
+
+	var filter models.WebhookDeliveryFilter
+	err := ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("error while binding the query params to list webhook deliveries")
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidListWebhookDeliveriesQuery)
+		return
+	}
+
+	for _, status := range filter.Status {
+		switch status {
+		case models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDeadLetter:
+		default:
+			utils.RequestLogger(ctx).Error("received status is not supported while listing webhook deliveries")
+			utils.RespondWithError(ctx, http.StatusBadRequest, "received status is not supported")
+			return
+		}
+	}
+
+	if filter.PageSize < 0 || filter.PageSize > constants.MaxPageSize {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("invalid page_size %v is provided to list webhook deliveries", filter.PageSize))
+		utils.RespondWithError(ctx, http.StatusBadRequest, fmt.Sprintf("page_size should be between 1 and %v", constants.MaxPageSize))
+		return
+	}
+}
+
+func validateRedeliverWebhookDeliveryInput(ctx *gin.Context) {
+	deliveryID := ctx.Param(constants.DeliveryID)
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("Error parsing the %v deliveryID", deliveryID))
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidDeliveryID)
+		return
+	}
+}
+
+// ValidateLimitOfferFilter checks the enum values and the time ranges of a limit offer filter, it is shared by the
+// http api and the export command
+func ValidateLimitOfferFilter(filter models.LimitOfferFilter) error {
+	for _, status := range filter.Status {
+		switch status {
+		case models.Pending, models.Accepted, models.Rejected, models.Superseded, models.Cancelled,
+			models.AwaitingApproval, models.Declined, models.Expired:
+		default:
+			return errors.New("received status is not supported")
+		}
+	}
+
+	for _, limitType := range filter.LimitType {
+		switch limitType {
+		case models.AccountLimit, models.PerTransactionLimit:
+		default:
+			return errors.New("received limit_type is not supported")
+		}
+	}
+
+	ranges := []struct {
+		name string
+		from *time.Time
+		to   *time.Time
+	}{
+		{"created", filter.CreatedFrom, filter.CreatedTo},
+		{"activation", filter.ActivationFrom, filter.ActivationTo},
+		{"expiry", filter.ExpiryFrom, filter.ExpiryTo},
+	}
+	for _, r := range ranges {
+		if r.from != nil && r.to != nil && r.to.Before(*r.from) {
+			return fmt.Errorf("%v_to field should be greater than %v_from field", r.name, r.name)
+		}
+	}
+	return nil
 }
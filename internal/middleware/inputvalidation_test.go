@@ -343,4 +343,94 @@ func TestValidateUpdateLimitOfferStatusRequestInput(t *testing.T) {
 	e.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
+	// case 3 : decision_channel is missing
+	updateLimitOfferStatus = models.UpdateLimitOfferStatus{
+		LimitOfferID: limitOfferID,
+		Status:       string(models.Accepted),
+	}
+	assert.NotNil(t, ValidateUpdateLimitOfferStatusFields(updateLimitOfferStatus))
+
+	// case 4 : unknown decision_channel
+	updateLimitOfferStatus.DecisionChannel = "FAX"
+	assert.NotNil(t, ValidateUpdateLimitOfferStatusFields(updateLimitOfferStatus))
+
+	// case 5 : valid request
+	updateLimitOfferStatus.DecisionChannel = models.MobileChannel
+	assert.Nil(t, ValidateUpdateLimitOfferStatusFields(updateLimitOfferStatus))
+
+}
+
+func TestValidateListLimitOffersRequestInput(t *testing.T) {
+	// init logging client
+	utils.InitLogClient()
+
+	r := gin.Default()
+	r.Use(ValidateInputRequest())
+	r.GET("/v1/list_limit_offers/:account_id", func(c *gin.Context) {})
+
+	accountID := "f83513e1-f0cb-4a49-85e4-8e9ddb1f3417"
+	cases := []struct {
+		name string
+		url  string
+		code int
+	}{
+		{"invalid account id", "/v1/list_limit_offers/f83513e1-f0cb", http.StatusBadRequest},
+		{"unsupported status", "/v1/list_limit_offers/" + accountID + "?status=PENDING&status=UNKNOWN", http.StatusBadRequest},
+		{"unsupported limit type", "/v1/list_limit_offers/" + accountID + "?limit_type=DAILY_LIMIT", http.StatusBadRequest},
+		{"created range reversed", "/v1/list_limit_offers/" + accountID + "?created_from=2023-08-24T00:00:00Z&created_to=2023-08-01T00:00:00Z", http.StatusBadRequest},
+		{"unsupported sort_by", "/v1/list_limit_offers/" + accountID + "?sort_by=status", http.StatusBadRequest},
+		{"page_size too large", "/v1/list_limit_offers/" + accountID + "?page_size=1000", http.StatusBadRequest},
+		{"valid request", "/v1/list_limit_offers/" + accountID + "?status=PENDING&status=ACCEPTED&limit_type=ACCOUNT_LIMIT&sort_by=new_limit&sort_order=asc&page_size=10", http.StatusOK},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, c.url, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, c.code, w.Code, c.name)
+	}
+}
+
+func TestValidateWebhookSubscriptionFields(t *testing.T) {
+	// Case 1 : valid subscription to every event
+	assert.NoError(t, ValidateWebhookSubscriptionFields(models.WebhookSubscription{URL: "https://partner.example.com/hooks"}))
+
+	// Case 2 : valid subscription to some events
+	assert.NoError(t, ValidateWebhookSubscriptionFields(models.WebhookSubscription{
+		URL:        "http://localhost:9000/hooks",
+		EventTypes: []string{constants.EventLimitOfferCreated, constants.EventLimitOfferExpired},
+	}))
+
+	// Case 3 : relative url or unsupported scheme
+	assert.Error(t, ValidateWebhookSubscriptionFields(models.WebhookSubscription{URL: "/hooks"}))
+	assert.Error(t, ValidateWebhookSubscriptionFields(models.WebhookSubscription{URL: "ftp://partner.example.com/hooks"}))
+
+	// Case 4 : unknown event type
+	assert.Error(t, ValidateWebhookSubscriptionFields(models.WebhookSubscription{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{"account.created"},
+	}))
+}
+
+func TestValidateCreateAccountContactFields(t *testing.T) {
+	limit := 1000
+	account := func(email string, locale string) models.Account {
+		accountInfo := models.Account{AccountLimit: &limit, LastAccountLimit: &limit, PerTransactionLimit: &limit, LastPerTransactionLimit: &limit, Locale: locale}
+		if email != "" {
+			accountInfo.Email = &email
+		}
+		return accountInfo
+	}
+
+	// Case 1 : contact details are optional
+	assert.NoError(t, ValidateCreateAccountFields(account("", "")))
+
+	// Case 2 : valid email and locales
+	assert.NoError(t, ValidateCreateAccountFields(account("jane@example.com", "en")))
+	assert.NoError(t, ValidateCreateAccountFields(account("jane@example.com", "pt-BR")))
+
+	// Case 3 : invalid email or locale
+	assert.Error(t, ValidateCreateAccountFields(account("Jane <jane@example.com>", "en")))
+	assert.Error(t, ValidateCreateAccountFields(account("jane", "en")))
+	assert.Error(t, ValidateCreateAccountFields(account("jane@example.com", "english")))
 }
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+)
+
+// RateLimit rejects with 429 the requests exceeding any of the limits of their route whose key is one of keys, so that
+// the ip can be limited ahead of the authentication and the client and the account once the client is known. The
+// limits are read from the configuration in use so that they follow its reloads.
+func RateLimit(store ratelimit.Store, keys ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		exceeded, retryAfter := TakeRateLimits(ctx, store, routeName(ctx), keys, func(key string) string {
+			return rateLimitKey(ctx, key)
+		})
+		if exceeded {
+			ctx.Header(constants.RetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			utils.RespondWithError(ctx, http.StatusTooManyRequests, constants.TooManyRequests)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// TakeRateLimits takes a token from the bucket of every limit of the route whose key is one of keys, the bucket is
+// the one of the value of the key, e.g. of the ip, given by keyValue. It tells whether a limit is exceeded and how
+// long until it is not, the tokens taken from the other buckets are then refunded since the request is not served.
+// The limits whose key has no value are skipped and the limits are not enforced when the store fails.
+func TakeRateLimits(ctx *gin.Context, store ratelimit.Store, route string, keys []string, keyValue func(key string) string) (bool, time.Duration) {
+	cfg := config.GetConfig().RateLimit
+	if !cfg.Enabled {
+		return false, 0
+	}
+
+	var taken []string
+	for _, limit := range routeLimits(cfg.Limits, route) {
+		if !hasKey(keys, limit.Key) {
+			continue
+		}
+		value := keyValue(limit.Key)
+		if value == "" {
+			continue
+		}
+		// the limits of "*" are shared by the routes they apply to
+		key := strings.Join([]string{limit.Route, limit.Key, value}, constants.Colon)
+		allowed, retryAfter, err := store.Take(ctx.Request.Context(), key, limit.Rate, limit.Burst)
+		if err != nil {
+			utils.RequestLogger(ctx).Error("unable to check the rate limit, the request is let through", zap.Error(err))
+			continue
+		}
+		if !allowed {
+			utils.RequestLogger(ctx).Info(fmt.Sprintf("request exceeds the %v rate limit of the %v route", limit.Key, limit.Route))
+			metrics.RateLimited(route, limit.Key)
+			refundRateLimits(ctx, store, taken)
+			return true, retryAfter
+		}
+		taken = append(taken, key)
+	}
+	return false, 0
+}
+
+// refundRateLimits puts back the tokens taken from the buckets of keys
+func refundRateLimits(ctx *gin.Context, store ratelimit.Store, keys []string) {
+	for _, key := range keys {
+		if err := store.Refund(ctx.Request.Context(), key); err != nil {
+			utils.RequestLogger(ctx).Error("unable to refund the rate limit", zap.Error(err))
+		}
+	}
+}
+
+// routeName returns the name of the route following /v1/, e.g. get_account for /v1/get_account/:account_id
+func routeName(ctx *gin.Context) string {
+	route := strings.TrimPrefix(ctx.FullPath(), constants.ForwardSlash+constants.Version+constants.ForwardSlash)
+	return strings.Split(route, constants.ForwardSlash)[0]
+}
+
+// routeLimits returns the limits of the route, or the ones of every route when it has none
+func routeLimits(limits []config.RouteLimit, route string) []config.RouteLimit {
+	var own, all []config.RouteLimit
+	for _, limit := range limits {
+		switch limit.Route {
+		case route:
+			own = append(own, limit)
+		case constants.AllRoutes:
+			all = append(all, limit)
+		}
+	}
+	if len(own) > 0 {
+		return own
+	}
+	return all
+}
+
+func hasKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitKey returns the client, the ip or the account the request is limited by, the account is read from the
+// path or from the json body and is empty when the request has none
+func rateLimitKey(ctx *gin.Context, key string) string {
+	switch key {
+	case constants.ClientRateLimitKey:
+		if principal, ok := utils.GetPrincipal(ctx); ok {
+			return principal.ClientID + constants.ForwardSlash + principal.Subject
+		}
+	case constants.IPRateLimitKey:
+		return ctx.ClientIP()
+	case constants.AccountRateLimitKey:
+		if accountID := ctx.Param(constants.AccountID); accountID != "" {
+			return accountID
+		}
+		if ctx.ContentType() == binding.MIMEJSON {
+			// the body is kept in the context for the handler to bind it again
+			var body struct {
+				AccountID string `json:"account_id"`
+			}
+			if err := ctx.ShouldBindBodyWith(&body, binding.JSON); err == nil {
+				return body.AccountID
+			}
+		}
+	}
+	return ""
+}
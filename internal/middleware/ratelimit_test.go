@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	utils.InitLogClient()
+	config.SetConfig(config.GlobalConfig{RateLimit: config.RateLimit{Enabled: true, Limits: []config.RouteLimit{
+		{Route: constants.AllRoutes, Key: constants.ClientRateLimitKey, Rate: 0.1, Burst: 3},
+		{Route: constants.CreateLimitOffer, Key: constants.AccountRateLimitKey, Rate: 0.5, Burst: 1},
+	}}})
+	t.Cleanup(func() { config.SetConfig(config.GlobalConfig{}) })
+
+	router := gin.New()
+	v1 := router.Group("/v1").Use(func(ctx *gin.Context) {
+		ctx.Set(constants.Principal, models.Principal{ClientID: ctx.GetHeader(constants.APIKeyHeader), Subject: "jane"})
+	}, RateLimit(ratelimit.NewMemoryStore(), constants.ClientRateLimitKey, constants.AccountRateLimitKey))
+	v1.POST("/create_limit_offer", func(ctx *gin.Context) {
+		// the body read by the rate limit is bound again
+		var limitOffer models.LimitOffer
+		assert.NoError(t, ctx.ShouldBindBodyWith(&limitOffer, binding.JSON))
+		ctx.Status(http.StatusCreated)
+	})
+	v1.GET("/get_account/:account_id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	serve := func(request *http.Request, client string) *httptest.ResponseRecorder {
+		request.Header.Set(constants.APIKeyHeader, client)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	createLimitOffer := func(accountID string) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/v1/create_limit_offer", strings.NewReader(`{"account_id":"`+accountID+`","limit_type":"ACCOUNT_LIMIT","new_limit":6000}`))
+		request.Header.Set("Content-Type", binding.MIMEJSON)
+		return request
+	}
+
+	// case 1 : the account of the body is limited, the route has limits of its own
+	assert.Equal(t, http.StatusCreated, serve(createLimitOffer("a1"), "back-office").Code)
+	recorder := serve(createLimitOffer("a1"), "back-office")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get(constants.RetryAfter))
+	var response limitoffererror.CreditCardError
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Equal(t, constants.TooManyRequests, response.Message)
+	assert.Equal(t, http.StatusCreated, serve(createLimitOffer("a2"), "back-office").Code)
+
+	// case 2 : the other routes share the limit of every client
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodGet, "/v1/get_account/a1", nil), "back-office").Code)
+	}
+	recorder = serve(httptest.NewRequest(http.MethodGet, "/v1/get_account/a2", nil), "back-office")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "10", recorder.Header().Get(constants.RetryAfter))
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodGet, "/v1/get_account/a1", nil), "offers-batch").Code)
+
+	// case 3 : the limits are reloaded along with the configuration
+	config.SetConfig(config.GlobalConfig{RateLimit: config.RateLimit{Enabled: false}})
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodGet, "/v1/get_account/a1", nil), "back-office").Code)
+}
+
+func TestRateLimitRefund(t *testing.T) {
+	utils.InitLogClient()
+	config.SetConfig(config.GlobalConfig{RateLimit: config.RateLimit{Enabled: true, Limits: []config.RouteLimit{
+		{Route: constants.CreateLimitOffer, Key: constants.ClientRateLimitKey, Rate: 0.1, Burst: 2},
+		{Route: constants.CreateLimitOffer, Key: constants.AccountRateLimitKey, Rate: 0.1, Burst: 1},
+	}}})
+	t.Cleanup(func() { config.SetConfig(config.GlobalConfig{}) })
+
+	store := ratelimit.NewMemoryStore()
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/create_limit_offer", nil)
+	take := func(accountID string) bool {
+		exceeded, _ := TakeRateLimits(ctx, store, constants.CreateLimitOffer, []string{constants.ClientRateLimitKey, constants.AccountRateLimitKey}, func(key string) string {
+			if key == constants.AccountRateLimitKey {
+				return accountID
+			}
+			return "back-office/jane"
+		})
+		return exceeded
+	}
+
+	// case 1 : the request rejected by the account limit does not use up the limit of the client
+	assert.False(t, take("a1"))
+	assert.True(t, take("a1"))
+	assert.True(t, take("a1"))
+	assert.False(t, take("a2"))
+
+	// case 2 : the client limit is then exceeded
+	assert.True(t, take("a3"))
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type LimitType string
 
@@ -12,19 +15,43 @@ const (
 type OfferStatus string
 
 const (
-	Pending  OfferStatus = "PENDING"
-	Accepted OfferStatus = "ACCEPTED"
-	Rejected OfferStatus = "REJECTED"
+	Pending    OfferStatus = "PENDING"
+	Accepted   OfferStatus = "ACCEPTED"
+	Rejected   OfferStatus = "REJECTED"
+	Superseded OfferStatus = "SUPERSEDED"
+	Cancelled  OfferStatus = "CANCELLED"
+	// offers above the approval threshold await the approval of a second back-office user before becoming PENDING
+	AwaitingApproval OfferStatus = "AWAITING_APPROVAL"
+	Declined         OfferStatus = "DECLINED"
+	Expired          OfferStatus = "EXPIRED"
+)
+
+type DecisionChannel string
+
+const (
+	MobileChannel DecisionChannel = "MOBILE"
+	WebChannel    DecisionChannel = "WEB"
+	AgentChannel  DecisionChannel = "AGENT"
+	APIChannel    DecisionChannel = "API"
 )
 
 type LimitOffer struct {
-	ID                  string      `json:"id"`
-	AccountID           *string     `json:"account_id"`
-	LimitType           *LimitType  `json:"limit_type"`
-	NewLimit            *int        `json:"new_limit"`
-	OfferActivationTime *time.Time  `json:"offer_activation_time"`
-	OfferExpiryTime     *time.Time  `json:"offer_expiry_time"`
-	Status              OfferStatus `json:"status"`
+	ID                  string           `json:"id"`
+	AccountID           *string          `json:"account_id"`
+	LimitType           *LimitType       `json:"limit_type"`
+	NewLimit            *int             `json:"new_limit"`
+	OfferActivationTime *time.Time       `json:"offer_activation_time"`
+	OfferExpiryTime     *time.Time       `json:"offer_expiry_time"`
+	Status              OfferStatus      `json:"status"`
+	CreatedAt           *time.Time       `json:"created_at"`
+	UpdatedAt           *time.Time       `json:"updated_at"`
+	DecidedAt           *time.Time       `json:"decided_at"`
+	DecidedBy           *string          `json:"decided_by"`
+	DecisionChannel     *DecisionChannel `json:"decision_channel"`
+	SupersededBy        *string          `json:"superseded_by"`
+	CreatedBy           *string          `json:"created_by"`
+	ReviewedAt          *time.Time       `json:"reviewed_at"`
+	ReviewedBy          *string          `json:"reviewed_by"`
 }
 
 type Account struct {
@@ -36,6 +63,9 @@ type Account struct {
 	LastPerTransactionLimit       *int      `json:"last_per_transaction_limit"`
 	AccountLimitUpdateTime        time.Time `json:"account_limit_update_time,omitempty"`
 	PerTransactionLimitUpdateTime time.Time `json:"per_transaction_limit_update_time,omitempty"`
+	// contact details for the notifications of the customer, an account without email is not notified
+	Email  *string `json:"email,omitempty"`
+	Locale string  `json:"locale,omitempty"`
 }
 
 type ActiveLimitOffer struct {
@@ -43,7 +73,234 @@ type ActiveLimitOffer struct {
 	ActiveDate *time.Time `json:"active_date,omitempty"`
 }
 
+// ReviewLimitOffer approves (Status PENDING) or declines (Status DECLINED) an offer awaiting approval
+type ReviewLimitOffer struct {
+	LimitOfferID string
+	Status       OfferStatus
+	ReviewedBy   string
+}
+
+// RevertAccountLimit restores the previous value of the limit of an account
+type RevertAccountLimit struct {
+	AccountID string    `json:"-"`
+	LimitType LimitType `json:"limit_type"`
+}
+
 type UpdateLimitOfferStatus struct {
-	LimitOfferID string `json:"limit_offer_id"`
-	Status       string `json:"status"`
+	LimitOfferID    string          `json:"limit_offer_id"`
+	Status          string          `json:"status"`
+	DecisionChannel DecisionChannel `json:"decision_channel"`
+	DecidedBy       string          `json:"decided_by,omitempty"`
+}
+
+// LimitOfferFilter narrows down the limit offers returned by an export or a listing, the time ranges are inclusive
+type LimitOfferFilter struct {
+	AccountID      string        `form:"account_id" json:"account_id"`
+	Status         []OfferStatus `form:"status" json:"status"`
+	LimitType      []LimitType   `form:"limit_type" json:"limit_type"`
+	CreatedFrom    *time.Time    `form:"created_from" json:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo      *time.Time    `form:"created_to" json:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	ActivationFrom *time.Time    `form:"activation_from" json:"activation_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	ActivationTo   *time.Time    `form:"activation_to" json:"activation_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	ExpiryFrom     *time.Time    `form:"expiry_from" json:"expiry_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	ExpiryTo       *time.Time    `form:"expiry_to" json:"expiry_to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ListLimitOffers is a page request of the limit offers of an account, Cursor is the NextCursor of the previous page
+type ListLimitOffers struct {
+	LimitOfferFilter
+	SortBy    string `form:"sort_by" json:"sort_by"`
+	SortOrder string `form:"sort_order" json:"sort_order"`
+	PageSize  int    `form:"page_size" json:"page_size"`
+	Cursor    string `form:"cursor" json:"cursor"`
+}
+
+type LimitOfferPage struct {
+	LimitOffers []LimitOffer `json:"limit_offers"`
+	NextCursor  string       `json:"next_cursor,omitempty"`
+	TotalCount  int          `json:"total_count"`
+}
+
+type BulkRowStatus string
+
+const (
+	RowSucceeded BulkRowStatus = "SUCCEEDED"
+	RowFailed    BulkRowStatus = "FAILED"
+)
+
+// BulkRowResult is the outcome of a single csv row of a bulk import
+type BulkRowResult struct {
+	Row    int           `json:"row"`
+	ID     string        `json:"id,omitempty"`
+	Status BulkRowStatus `json:"status"`
+	Error  string        `json:"error,omitempty"`
+}
+
+type BulkImportReport struct {
+	Total     int             `json:"total"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Results   []BulkRowResult `json:"results"`
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// ClientID identifies the calling application, the api key owner or the jwt client
+	ClientID string `json:"client_id"`
+	// CustomerID is set when the caller acts on behalf of a customer
+	CustomerID string   `json:"customer_id,omitempty"`
+	Subject    string   `json:"subject"`
+	Scopes     []string `json:"scopes"`
+	Roles      []string `json:"roles"`
+	AuthMethod string   `json:"auth_method"`
+}
+
+// HasRole tells whether the principal was granted the role
+func (p Principal) HasRole(role string) bool {
+	for _, granted := range p.Roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope tells whether the principal was granted the scope
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditEvent records a state change along with who made it, the events are chained by their hashes
+type AuditEvent struct {
+	Seq           int64           `json:"seq"`
+	TransactionID string          `json:"transaction_id"`
+	ClientID      string          `json:"client_id"`
+	Subject       string          `json:"subject"`
+	Action        string          `json:"action"`
+	EntityType    string          `json:"entity_type"`
+	EntityID      string          `json:"entity_id"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PrevHash      string          `json:"prev_hash"`
+	Hash          string          `json:"hash"`
+}
+
+// AuditEventFilter is a page request of the audit log, Cursor is the NextCursor of the previous page
+type AuditEventFilter struct {
+	EntityType    string     `form:"entity_type" json:"entity_type"`
+	EntityID      string     `form:"entity_id" json:"entity_id"`
+	Action        string     `form:"action" json:"action"`
+	ClientID      string     `form:"client_id" json:"client_id"`
+	TransactionID string     `form:"transaction_id" json:"transaction_id"`
+	From          *time.Time `form:"from" json:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To            *time.Time `form:"to" json:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	PageSize      int        `form:"page_size" json:"page_size"`
+	Cursor        string     `form:"cursor" json:"cursor"`
+}
+
+type AuditEventPage struct {
+	AuditEvents []AuditEvent `json:"audit_events"`
+	NextCursor  string       `json:"next_cursor,omitempty"`
+}
+
+// OutboxEvent is a domain event waiting in the outbox table to be published, Payload is the limit offer after the change
+type OutboxEvent struct {
+	ID          int64           `json:"-"`
+	EventID     string          `json:"event_id"`
+	EventType   string          `json:"event_type"`
+	AccountID   string          `json:"account_id"`
+	AggregateID string          `json:"limit_offer_id"`
+	Payload     json.RawMessage `json:"data"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Attempts    int             `json:"-"`
+}
+
+// WebhookSubscription is a partner endpoint receiving the events of EventTypes, every event when empty.
+// Secret signs the deliveries and is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending    WebhookDeliveryStatus = "PENDING"
+	DeliveryDelivered  WebhookDeliveryStatus = "DELIVERED"
+	DeliveryDeadLetter WebhookDeliveryStatus = "DEAD_LETTER"
+)
+
+// WebhookDelivery is an event to be delivered to a subscription, Payload is the body posted to the subscription.
+// A delivery failing max attempts times is moved to DEAD_LETTER until it is redelivered, the attempts made before its
+// last redelivery, RedeliveredAttempts, are then no longer counted against max attempts.
+type WebhookDelivery struct {
+	ID                  string                   `json:"id"`
+	SubscriptionID      string                   `json:"subscription_id"`
+	EventID             string                   `json:"event_id"`
+	EventType           string                   `json:"event_type"`
+	Payload             json.RawMessage          `json:"-"`
+	Status              WebhookDeliveryStatus    `json:"status"`
+	Attempts            int                      `json:"attempts"`
+	RedeliveredAttempts int                      `json:"-"`
+	NextAttemptAt       time.Time                `json:"next_attempt_at"`
+	LastError           *string                  `json:"last_error"`
+	CreatedAt           time.Time                `json:"created_at"`
+	DeliveredAt         *time.Time               `json:"delivered_at"`
+	AttemptLog          []WebhookDeliveryAttempt `json:"attempt_log,omitempty"`
+}
+
+// WebhookDeliveryAttempt is the outcome of a single post of a delivery, Error is nil when it succeeded
+type WebhookDeliveryAttempt struct {
+	DeliveryID  string    `json:"-"`
+	Attempt     int       `json:"attempt"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	StatusCode  *int      `json:"status_code"`
+	Error       *string   `json:"error"`
+	DurationMS  int64     `json:"duration_ms"`
+}
+
+// WebhookDeliveryFilter is a request of the latest deliveries of a subscription along with their attempt log
+type WebhookDeliveryFilter struct {
+	SubscriptionID string                  `form:"-" json:"-"`
+	Status         []WebhookDeliveryStatus `form:"status" json:"status"`
+	PageSize       int                     `form:"page_size" json:"page_size"`
+}
+
+type NotificationKind string
+
+const (
+	NotificationOfferCreated  NotificationKind = "offer_created"
+	NotificationOfferExpiring NotificationKind = "offer_expiring"
+	NotificationLimitChanged  NotificationKind = "limit_changed"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "PENDING"
+	NotificationSent    NotificationStatus = "SENT"
+	NotificationFailed  NotificationStatus = "FAILED"
+	// the reminder of an offer which was decided, cancelled or superseded before it was sent
+	NotificationSkipped NotificationStatus = "SKIPPED"
+)
+
+// Notification is a message queued for the customer of an account about one of its offers,
+// DaysLeft is only set for the expiry reminders
+type Notification struct {
+	ID         int64
+	Kind       NotificationKind
+	Account    Account
+	LimitOffer LimitOffer
+	DaysLeft   *int
+	Attempts   int
 }
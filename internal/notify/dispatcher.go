@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/outbox"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Repository is the part of the db layer the notifications are queued in
+type Repository interface {
+	EnqueueNotification(*gin.Context, models.NotificationKind, string, string) *limitoffererror.CreditCardError
+	EnqueueExpiryReminders(*gin.Context, []int) (int, *limitoffererror.CreditCardError)
+	DispatchNotifications(*gin.Context, int, int, time.Duration, func(models.Notification) error, func(int) time.Duration) (int, *limitoffererror.CreditCardError)
+}
+
+// EventPublisher is the outbox publisher queuing the notifications of the offer events
+type EventPublisher struct {
+	repo Repository
+}
+
+func NewEventPublisher(repo Repository) EventPublisher {
+	return EventPublisher{repo: repo}
+}
+
+// Publish queues the notification of the event, if any. A customer learns about an offer when it becomes PENDING,
+// which is either its creation or its approval, and about the limit change when the offer is accepted.
+func (p EventPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	kind, ok := notificationKind(event)
+	if !ok {
+		return nil
+	}
+	ginCtx := utils.NewBackgroundContext()
+	ginCtx.Request = ginCtx.Request.WithContext(ctx)
+	if err := p.repo.EnqueueNotification(ginCtx, kind, event.AggregateID, string(kind)+":"+event.AggregateID); err != nil {
+		return fmt.Errorf("unable to queue the notification : %v", err.Message)
+	}
+	return nil
+}
+
+func notificationKind(event models.OutboxEvent) (models.NotificationKind, bool) {
+	switch event.EventType {
+	case constants.EventLimitOfferApproved:
+		return models.NotificationOfferCreated, true
+	case constants.EventLimitOfferCreated:
+		// an offer awaiting approval is announced once it is approved
+		return models.NotificationOfferCreated, !isAwaitingApproval(event)
+	case constants.EventLimitOfferAccepted:
+		return models.NotificationLimitChanged, true
+	default:
+		return constants.EmptyString, false
+	}
+}
+
+func isAwaitingApproval(event models.OutboxEvent) bool {
+	var offer struct {
+		Status models.OfferStatus `json:"status"`
+	}
+	return json.Unmarshal(event.Payload, &offer) == nil && offer.Status == models.AwaitingApproval
+}
+
+// Dispatcher queues the expiry reminders and sends the queued notifications
+type Dispatcher struct {
+	repo             Repository
+	renderer         *Renderer
+	notifier         Notifier
+	reminderDays     []int
+	reminderInterval time.Duration
+	batchSize        int
+	maxAttempts      int
+	lease            time.Duration
+	pollInterval     time.Duration
+	retryBackoff     time.Duration
+	maxBackoff       time.Duration
+}
+
+func NewDispatcher(cfg config.Notifications, repo Repository, renderer *Renderer, notifier Notifier) *Dispatcher {
+	return &Dispatcher{
+		repo:             repo,
+		renderer:         renderer,
+		notifier:         notifier,
+		reminderDays:     cfg.ExpiryReminderDays,
+		reminderInterval: time.Duration(cfg.ReminderInterval) * time.Second,
+		batchSize:        cfg.BatchSize,
+		maxAttempts:      cfg.MaxAttempts,
+		lease:            time.Duration(cfg.LeaseDuration) * time.Second,
+		pollInterval:     time.Duration(cfg.PollInterval) * time.Millisecond,
+		retryBackoff:     time.Duration(cfg.RetryBackoff) * time.Second,
+		maxBackoff:       time.Duration(cfg.MaxRetryBackoff) * time.Second,
+	}
+}
+
+// Run queues the reminders every reminder interval and sends the notifications until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	utils.Logger.Info("starting the notification dispatcher")
+	var nextReminderScan time.Time
+	for {
+		if len(d.reminderDays) > 0 && !time.Now().Before(nextReminderScan) {
+			d.enqueueReminders(ctx)
+			nextReminderScan = time.Now().Add(d.reminderInterval)
+		}
+
+		processed, err := d.dispatch(ctx)
+		if err != nil {
+			utils.Logger.Error("unable to send the notifications", zap.String("transaction_id", err.Trace), zap.String("error", err.Message))
+		}
+		if err == nil && processed == d.batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			utils.Logger.Info("stopped the notification dispatcher")
+			return
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+func (d *Dispatcher) enqueueReminders(ctx context.Context) {
+	ginCtx := utils.NewBackgroundContext()
+	ginCtx.Request = ginCtx.Request.WithContext(ctx)
+	queued, err := d.repo.EnqueueExpiryReminders(ginCtx, d.reminderDays)
+	if err != nil {
+		utils.Logger.Error("unable to queue the expiry reminders", zap.String("transaction_id", err.Trace), zap.String("error", err.Message))
+		return
+	}
+	if queued > 0 {
+		utils.Logger.Info(fmt.Sprintf("queued %v expiry reminders", queued))
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) (int, *limitoffererror.CreditCardError) {
+	ginCtx := utils.NewBackgroundContext()
+	ginCtx.Request = ginCtx.Request.WithContext(ctx)
+	send := func(notification models.Notification) error {
+		message, err := d.renderer.Render(notification)
+		if err != nil {
+			return err
+		}
+		return d.notifier.Send(ctx, message)
+	}
+	retryAfter := func(attempts int) time.Duration {
+		return outbox.Backoff(d.retryBackoff, d.maxBackoff, attempts)
+	}
+	return d.repo.DispatchNotifications(ginCtx, d.batchSize, d.maxAttempts, d.lease, send, retryAfter)
+}
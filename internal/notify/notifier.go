@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Notifier sends a rendered message to the customer
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewNotifier builds the notifier selected by the notifications config
+func NewNotifier(cfg config.Notifications) (Notifier, error) {
+	switch cfg.Notifier {
+	case constants.SMTPNotifier:
+		return NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom, time.Duration(cfg.SMTPTimeout)*time.Second), nil
+	case constants.LogPublisher, constants.EmptyString:
+		if cfg.File == constants.EmptyString {
+			return LogNotifier{}, nil
+		}
+		file, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the notifications file %v : %w", cfg.File, err)
+		}
+		return NewWriterNotifier(file), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier %q", cfg.Notifier)
+	}
+}
+
+// LogNotifier writes the messages to the application log
+type LogNotifier struct{}
+
+func (LogNotifier) Send(ctx context.Context, message Message) error {
+	utils.Logger.Info(fmt.Sprintf("notification : %v", message.Subject), zap.String("email", message.To))
+	return nil
+}
+
+// WriterNotifier appends the messages to a writer, typically a file, as json lines
+type WriterNotifier struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterNotifier(writer io.Writer) *WriterNotifier {
+	return &WriterNotifier{writer: writer}
+}
+
+func (n *WriterNotifier) Send(ctx context.Context, message Message) error {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.writer.Write(append(encoded, '\n'))
+	return err
+}
+
+// SMTPNotifier sends the messages as plain text emails, authenticating when a username is configured.
+// The connection is upgraded with STARTTLS when the server offers it, as smtp.SendMail does.
+type SMTPNotifier struct {
+	host    string
+	address string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+// NewSMTPNotifier returns a notifier giving up on a message once it took timeout to send
+func NewSMTPNotifier(host string, port int, username string, password string, from string, timeout time.Duration) *SMTPNotifier {
+	notifier := &SMTPNotifier{host: host, address: net.JoinHostPort(host, strconv.Itoa(port)), from: from, timeout: timeout}
+	if username != constants.EmptyString {
+		notifier.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return notifier
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, message Message) error {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// the deadline does not follow a cancellation of ctx, e.g. on shutdown
+	sent := make(chan struct{})
+	defer close(sent)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-sent:
+		}
+	}()
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if err := client.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(n.compose(message)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) compose(message Message) []byte {
+	var email strings.Builder
+	email.WriteString("From: " + n.from + "\r\n")
+	email.WriteString("To: " + message.To + "\r\n")
+	email.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	email.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	email.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(email.String())
+}
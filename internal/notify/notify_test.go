@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func notification(kind models.NotificationKind, locale string) models.Notification {
+	email := "jane@example.com"
+	limitType := models.AccountLimit
+	newLimit, accountLimit, lastAccountLimit := 15000, 15000, 10000
+	expiry := time.Date(2023, 9, 1, 10, 30, 0, 0, time.UTC)
+	return models.Notification{
+		Kind: kind,
+		Account: models.Account{
+			AccountID:        "2b4e1e64-624f-4a4e-9911-e0b13f526e10",
+			AccountLimit:     &accountLimit,
+			LastAccountLimit: &lastAccountLimit,
+			Email:            &email,
+			Locale:           locale,
+		},
+		LimitOffer: models.LimitOffer{LimitType: &limitType, NewLimit: &newLimit, OfferExpiryTime: &expiry},
+	}
+}
+
+func TestRender(t *testing.T) {
+	renderer, err := NewRenderer("en")
+	assert.NoError(t, err)
+
+	// case 1 : offer created in english
+	message, err := renderer.Render(notification(models.NotificationOfferCreated, "en"))
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", message.To)
+	assert.Equal(t, "A new account limit offer is waiting for you", message.Subject)
+	assert.Contains(t, message.Body, "to 15000")
+	assert.Contains(t, message.Body, "01 Sep 2023 10:30 UTC")
+
+	// case 2 : expiry reminder in spanish, a regional locale falls back to its language
+	expiring := notification(models.NotificationOfferExpiring, "es-MX")
+	daysLeft := 3
+	expiring.DaysLeft = &daysLeft
+	message, err = renderer.Render(expiring)
+	assert.NoError(t, err)
+	assert.Equal(t, "Su oferta de límite de la cuenta vence en menos de 3 días", message.Subject)
+
+	// case 3 : limit changed in a locale without templates falls back to the default locale
+	message, err = renderer.Render(notification(models.NotificationLimitChanged, "fr"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Your account limit has changed", message.Subject)
+	assert.Contains(t, message.Body, "from 10000 to 15000")
+	assert.False(t, strings.HasPrefix(message.Body, "\n"))
+
+	// case 4 : an account without email can not be notified
+	withoutEmail := notification(models.NotificationOfferCreated, "en")
+	withoutEmail.Account.Email = nil
+	_, err = renderer.Render(withoutEmail)
+	assert.Error(t, err)
+
+	// case 5 : the default locale needs templates
+	_, err = NewRenderer("fr")
+	assert.Error(t, err)
+}
+
+func TestNotificationKind(t *testing.T) {
+	event := func(eventType string, status models.OfferStatus) models.OutboxEvent {
+		payload, _ := json.Marshal(models.LimitOffer{Status: status})
+		return models.OutboxEvent{EventType: eventType, Payload: payload}
+	}
+
+	kind, ok := notificationKind(event(constants.EventLimitOfferCreated, models.Pending))
+	assert.True(t, ok)
+	assert.Equal(t, models.NotificationOfferCreated, kind)
+
+	_, ok = notificationKind(event(constants.EventLimitOfferCreated, models.AwaitingApproval))
+	assert.False(t, ok)
+
+	kind, ok = notificationKind(event(constants.EventLimitOfferApproved, models.Pending))
+	assert.True(t, ok)
+	assert.Equal(t, models.NotificationOfferCreated, kind)
+
+	kind, ok = notificationKind(event(constants.EventLimitOfferAccepted, models.Accepted))
+	assert.True(t, ok)
+	assert.Equal(t, models.NotificationLimitChanged, kind)
+
+	_, ok = notificationKind(event(constants.EventLimitOfferRejected, models.Rejected))
+	assert.False(t, ok)
+}
+
+func TestNotifiers(t *testing.T) {
+	message := Message{To: "jane@example.com", Subject: "Su límite ha cambiado", Body: "line 1\nline 2\n"}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, NewWriterNotifier(&buffer).Send(context.Background(), message))
+	var written Message
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &written))
+	assert.Equal(t, message, written)
+
+	email := string(NewSMTPNotifier("smtp.example.com", 587, "", "", "offers@example.com", time.Second).compose(message))
+	assert.Contains(t, email, "To: jane@example.com\r\n")
+	assert.Contains(t, email, "Subject: =?utf-8?q?Su_l=C3=ADmite_ha_cambiado?=\r\n")
+	assert.True(t, strings.HasSuffix(email, "\r\n\r\nline 1\r\nline 2\r\n"))
+}
+
+func TestSMTPNotifierTimeout(t *testing.T) {
+	// a server which accepts the connection and never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			io.Copy(io.Discard, conn)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	message := Message{To: "jane@example.com", Subject: "Your account limit has changed", Body: "line 1\n"}
+
+	// case 1 : the message is given up after the timeout
+	start := time.Now()
+	err = NewSMTPNotifier(host, portNumber, "", "", "offers@example.com", 100*time.Millisecond).Send(context.Background(), message)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// case 2 : the message is given up once ctx is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start = time.Now()
+	err = NewSMTPNotifier(host, portNumber, "", "", "offers@example.com", time.Minute).Send(ctx, message)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+)
+
+// templates/<locale>/<kind>.tmpl, the first line of a rendered template is the subject
+//
+//go:embed templates
+var templateFS embed.FS
+
+const subjectPrefix = "Subject: "
+
+// names of the limit types in the messages of every locale
+var limitNames = map[string]map[models.LimitType]string{
+	"en": {models.AccountLimit: "account limit", models.PerTransactionLimit: "per transaction limit"},
+	"es": {models.AccountLimit: "límite de la cuenta", models.PerTransactionLimit: "límite por transacción"},
+}
+
+// Message is a rendered notification ready to be sent
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// templateData is what the templates render
+type templateData struct {
+	CustomerID    string
+	AccountID     string
+	LimitType     models.LimitType
+	NewLimit      int
+	PreviousLimit int
+	ExpiresAt     time.Time
+	DaysLeft      int
+}
+
+// Renderer renders the notifications with the templates of the locale of the account
+type Renderer struct {
+	templates     map[string]*template.Template
+	defaultLocale string
+}
+
+// NewRenderer parses the templates of every locale, defaultLocale is used for the accounts whose locale has no templates
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	renderer := &Renderer{templates: map[string]*template.Template{}, defaultLocale: defaultLocale}
+
+	locales, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, locale := range locales {
+		names := limitNames[locale.Name()]
+		funcs := template.FuncMap{"limitName": func(limitType models.LimitType) string {
+			if name, ok := names[limitType]; ok {
+				return name
+			}
+			return string(limitType)
+		}}
+		parsed, err := template.New(locale.Name()).Funcs(funcs).ParseFS(templateFS, path.Join("templates", locale.Name(), "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the %v notification templates : %w", locale.Name(), err)
+		}
+		renderer.templates[locale.Name()] = parsed
+	}
+
+	if _, ok := renderer.templates[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no notification templates for the default locale %q", defaultLocale)
+	}
+	return renderer, nil
+}
+
+// Render renders the notification for the email of its account
+func (r *Renderer) Render(notification models.Notification) (Message, error) {
+	account, offer := notification.Account, notification.LimitOffer
+	if account.Email == nil {
+		return Message{}, fmt.Errorf("account %v has no email", account.AccountID)
+	}
+
+	data := templateData{CustomerID: account.CustomerID, AccountID: account.AccountID}
+	if offer.LimitType != nil {
+		data.LimitType = *offer.LimitType
+	}
+	if offer.NewLimit != nil {
+		data.NewLimit = *offer.NewLimit
+	}
+	if offer.OfferExpiryTime != nil {
+		data.ExpiresAt = offer.OfferExpiryTime.UTC()
+	}
+	if notification.DaysLeft != nil {
+		data.DaysLeft = *notification.DaysLeft
+	}
+	previousLimit := account.LastAccountLimit
+	if data.LimitType == models.PerTransactionLimit {
+		previousLimit = account.LastPerTransactionLimit
+	}
+	if previousLimit != nil {
+		data.PreviousLimit = *previousLimit
+	}
+
+	var rendered bytes.Buffer
+	err := r.localeTemplates(account.Locale).ExecuteTemplate(&rendered, string(notification.Kind)+".tmpl", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	subject, body, _ := strings.Cut(rendered.String(), "\n")
+	if !strings.HasPrefix(subject, subjectPrefix) {
+		return Message{}, fmt.Errorf("%v template does not start with the subject", notification.Kind)
+	}
+	return Message{
+		To:      *account.Email,
+		Subject: strings.TrimPrefix(subject, subjectPrefix),
+		Body:    strings.TrimLeft(body, "\n"),
+	}, nil
+}
+
+// localeTemplates picks the templates of the locale, of its language or else of the default locale
+func (r *Renderer) localeTemplates(locale string) *template.Template {
+	if templates, ok := r.templates[locale]; ok {
+		return templates
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	if templates, ok := r.templates[language]; ok {
+		return templates
+	}
+	return r.templates[r.defaultLocale]
+}
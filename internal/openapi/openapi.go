@@ -0,0 +1,36 @@
+// Package openapi serves the OpenAPI document of the http api and a page rendering it. The document is maintained
+// by hand along with the routes and the models, the tests fail when they drift apart.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed docs.html
+var docs []byte
+
+// Spec returns the OpenAPI 3 document of the http api
+func Spec() []byte {
+	return spec
+}
+
+// This function serves the OpenAPI document
+func ServeSpec() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, constants.ApplicationJSON, spec)
+	}
+}
+
+// This function serves the page rendering the OpenAPI document, it does not load anything but the document
+func ServeDocs() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", docs)
+	}
+}
@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type document struct {
+	Paths map[string]map[string]struct {
+		Parameters []struct {
+			Name string `json:"name"`
+			In   string `json:"in"`
+		} `json:"parameters"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func load(t *testing.T) document {
+	var spec document
+	assert.NoError(t, json.Unmarshal(Spec(), &spec))
+	return spec
+}
+
+// fieldNames returns the names of the fields of the struct under the tag, the embedded structs are flattened
+func fieldNames(structType reflect.Type, tag string) []string {
+	names := []string{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous {
+			names = append(names, fieldNames(field.Type, tag)...)
+			continue
+		}
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSchemasMatchModels(t *testing.T) {
+	spec := load(t)
+
+	// every schema describing a model has the json fields of the model, the others are checked against
+	// the anonymous structs the handlers respond with
+	schemaModels := map[string]interface{}{
+		"Error":                  limitoffererror.CreditCardError{},
+		"Account":                models.Account{},
+		"LimitOffer":             models.LimitOffer{},
+		"ActiveLimitOffer":       models.ActiveLimitOffer{},
+		"UpdateLimitOfferStatus": models.UpdateLimitOfferStatus{},
+		"RevertAccountLimit":     models.RevertAccountLimit{},
+		"LimitOfferPage":         models.LimitOfferPage{},
+		"BulkRowResult":          models.BulkRowResult{},
+		"BulkImportReport":       models.BulkImportReport{},
+		"AuditEvent":             models.AuditEvent{},
+		"AuditEventPage":         models.AuditEventPage{},
+		"OutboxEvent":            models.OutboxEvent{},
+		"WebhookSubscription":    models.WebhookSubscription{},
+		"WebhookDelivery":        models.WebhookDelivery{},
+		"WebhookDeliveryAttempt": models.WebhookDeliveryAttempt{},
+		"CreatedAccount": struct {
+			AccountID  string `json:"account_id"`
+			CustomerID string `json:"customer_id"`
+		}{},
+		"CreatedLimitOffer": struct {
+			OfferLimitID string `json:"offer_limit_id"`
+			Status       string `json:"status"`
+		}{},
+		"WebhookSubscriptions": struct {
+			WebhookSubscriptions []models.WebhookSubscription `json:"webhook_subscriptions"`
+		}{},
+		"WebhookDeliveries": struct {
+			WebhookDeliveries []models.WebhookDelivery `json:"webhook_deliveries"`
+		}{},
+		"Health": struct {
+			Status string            `json:"status"`
+			Checks map[string]string `json:"checks"`
+		}{},
+	}
+
+	for name, schema := range spec.Components.Schemas {
+		model, ok := schemaModels[name]
+		if !assert.True(t, ok, "schema %v does not describe a model", name) {
+			continue
+		}
+		properties := []string{}
+		for property := range schema.Properties {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		assert.Equal(t, fieldNames(reflect.TypeOf(model), "json"), properties, "fields of schema %v", name)
+	}
+	assert.Len(t, spec.Components.Schemas, len(schemaModels))
+}
+
+func TestQueryParametersMatchModels(t *testing.T) {
+	spec := load(t)
+
+	// the query params of the operations are bound to these models
+	queries := map[string]interface{}{
+		"GET /v1/export_limit_offers":                       models.LimitOfferFilter{},
+		"GET /v1/list_limit_offers/{account_id}":            models.ListLimitOffers{},
+		"GET /v1/audit_events":                              models.AuditEventFilter{},
+		"GET /v1/list_webhook_deliveries/{subscription_id}": models.WebhookDeliveryFilter{},
+	}
+
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			key := strings.ToUpper(method) + " " + path
+			parameters := []string{}
+			for _, parameter := range operation.Parameters {
+				if parameter.In == "query" {
+					parameters = append(parameters, parameter.Name)
+				}
+			}
+			sort.Strings(parameters)
+
+			model, ok := queries[key]
+			if !ok {
+				assert.Empty(t, parameters, "query params of %v", key)
+				continue
+			}
+			expected := fieldNames(reflect.TypeOf(model), "form")
+			// the account of a listing is a path param
+			if path == "/v1/list_limit_offers/{account_id}" {
+				expected = remove(expected, "account_id")
+			}
+			assert.Equal(t, expected, parameters, "query params of %v", key)
+		}
+	}
+}
+
+func remove(names []string, name string) []string {
+	kept := []string{}
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
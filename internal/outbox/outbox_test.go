@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func event() models.OutboxEvent {
+	return models.OutboxEvent{
+		ID:          7,
+		EventID:     "0b9c3a3e-5b5b-4f4e-9c55-5d7c2d6c1f0e",
+		EventType:   constants.EventLimitOfferAccepted,
+		AccountID:   "2b4e1e64-624f-4a4e-9911-e0b13f526e10",
+		AggregateID: "abfe7bda-d59d-49ca-b2d0-39e3ecb12fb5",
+		Payload:     json.RawMessage(`{"status":"ACCEPTED"}`),
+		OccurredAt:  time.Date(2023, 8, 24, 2, 17, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var received []byte
+	var header http.Header
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	publisher := NewWebhookPublisher(server.URL, time.Second)
+
+	// case 1 : the event is posted as json along with its id and type
+	err := publisher.Publish(context.Background(), event())
+	assert.NoError(t, err)
+	assert.Equal(t, event().EventID, header.Get(constants.EventIDHeader))
+	assert.Equal(t, constants.EventLimitOfferAccepted, header.Get(constants.EventTypeHeader))
+	var envelope map[string]interface{}
+	assert.NoError(t, json.Unmarshal(received, &envelope))
+	assert.Equal(t, "ACCEPTED", envelope["data"].(map[string]interface{})["status"])
+	assert.Equal(t, event().AccountID, envelope["account_id"])
+
+	// case 2 : a non 2xx response fails the publishing so that the event is retried
+	status = http.StatusServiceUnavailable
+	assert.Error(t, publisher.Publish(context.Background(), event()))
+
+	// case 3 : an unreachable webhook fails the publishing
+	server.Close()
+	assert.Error(t, publisher.Publish(context.Background(), event()))
+}
+
+func TestWriterPublisher(t *testing.T) {
+	var buffer bytes.Buffer
+	publisher := NewWriterPublisher(&buffer)
+	assert.NoError(t, publisher.Publish(context.Background(), event()))
+	assert.NoError(t, publisher.Publish(context.Background(), event()))
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	var decoded models.OutboxEvent
+	assert.NoError(t, json.Unmarshal(lines[0], &decoded))
+	assert.Equal(t, event().EventID, decoded.EventID)
+	assert.JSONEq(t, `{"status":"ACCEPTED"}`, string(decoded.Payload))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(time.Second, time.Minute, 1))
+	assert.Equal(t, 2*time.Second, Backoff(time.Second, time.Minute, 2))
+	assert.Equal(t, 16*time.Second, Backoff(time.Second, time.Minute, 5))
+	assert.Equal(t, time.Minute, Backoff(time.Second, time.Minute, 7))
+	assert.Equal(t, time.Minute, Backoff(time.Second, time.Minute, 1000))
+}
+
+// purgingRepository purges the batches in order
+type purgingRepository struct {
+	Repository
+	batches []int
+	calls   int
+}
+
+func (r *purgingRepository) PurgeOutboxEvents(ctx *gin.Context, retention time.Duration, batchSize int) (int, *limitoffererror.CreditCardError) {
+	r.calls++
+	return r.batches[r.calls-1], nil
+}
+
+func TestRelayPurge(t *testing.T) {
+	utils.InitLogClient()
+	// the published events are purged batch by batch until a batch is not full
+	repo := &purgingRepository{batches: []int{2, 2, 1}}
+	relay := NewRelay(config.Outbox{BatchSize: 2, Retention: 60}, repo, nil)
+	relay.purge(context.Background())
+	assert.Equal(t, 3, repo.calls)
+}
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Publisher delivers a domain event to the outside world. An event whose publishing fails is retried, so
+// Publish must be safe to call again with an event which may already have been delivered.
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// NewPublisher builds the publisher selected by the outbox config
+func NewPublisher(cfg config.Outbox) (Publisher, error) {
+	switch cfg.Publisher {
+	case constants.WebhookPublisher:
+		return NewWebhookPublisher(cfg.WebhookURL, time.Duration(cfg.WebhookTimeout)*time.Second), nil
+	case constants.LogPublisher, constants.EmptyString:
+		if cfg.File == constants.EmptyString {
+			return LogPublisher{}, nil
+		}
+		file, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the outbox file %v : %w", cfg.File, err)
+		}
+		return NewWriterPublisher(file), nil
+	default:
+		return nil, fmt.Errorf("unsupported outbox publisher %q", cfg.Publisher)
+	}
+}
+
+// LogPublisher writes the events to the application log
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	// the event is logged as an object so that the personal data in its payload is redacted
+	utils.Logger.Info(fmt.Sprintf("published %v event", event.EventType), zap.Reflect("event", json.RawMessage(encoded)))
+	return nil
+}
+
+// WriterPublisher appends the events to a writer, typically a file, as json lines
+type WriterPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterPublisher(writer io.Writer) *WriterPublisher {
+	return &WriterPublisher{writer: writer}
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.writer.Write(append(encoded, '\n'))
+	return err
+}
+
+// WebhookPublisher posts every event as json to a url, any response other than 2xx is a failure
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{url: url, client: &http.Client{Timeout: timeout, Transport: tracing.Transport(http.DefaultTransport)}}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	// receivers deduplicate the redelivered events with the event id
+	request.Header.Set(constants.EventIDHeader, event.EventID)
+	request.Header.Set(constants.EventTypeHeader, event.EventType)
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %v", response.Status)
+	}
+	return nil
+}
+
+// MultiPublisher publishes every event to each of the publishers in turn. When one of them fails the event is
+// retried with all of them, so the publishers before it may get the event more than once.
+type MultiPublisher []Publisher
+
+func (p MultiPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Repository is the part of the db layer the relay reads the outbox through
+type Repository interface {
+	RelayOutboxEvents(*gin.Context, int, time.Duration, func(models.OutboxEvent) error, func(int) time.Duration) (int, *limitoffererror.CreditCardError)
+	PurgeOutboxEvents(*gin.Context, time.Duration, int) (int, *limitoffererror.CreditCardError)
+}
+
+// Relay moves the events written to the outbox table along with the changes to the publisher
+type Relay struct {
+	repo         Repository
+	publisher    Publisher
+	batchSize    int
+	lease        time.Duration
+	pollInterval time.Duration
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	// the published events older than retention are purged every purgeInterval
+	retention     time.Duration
+	purgeInterval time.Duration
+	lastPurge     time.Time
+}
+
+func NewRelay(cfg config.Outbox, repo Repository, publisher Publisher) *Relay {
+	return &Relay{
+		repo:          repo,
+		publisher:     publisher,
+		batchSize:     cfg.BatchSize,
+		lease:         time.Duration(cfg.LeaseDuration) * time.Second,
+		pollInterval:  time.Duration(cfg.PollInterval) * time.Millisecond,
+		retryBackoff:  time.Duration(cfg.RetryBackoff) * time.Second,
+		maxBackoff:    time.Duration(cfg.MaxRetryBackoff) * time.Second,
+		retention:     time.Duration(cfg.Retention) * time.Second,
+		purgeInterval: time.Duration(cfg.PurgeInterval) * time.Second,
+	}
+}
+
+// Run relays the events until ctx is cancelled. A full batch is followed by the next one right away,
+// otherwise the relay purges the published events if they are due and waits for the poll interval.
+func (r *Relay) Run(ctx context.Context) {
+	utils.Logger.Info("starting the outbox relay")
+	for {
+		published, err := r.relayBatch(ctx)
+		if err != nil {
+			utils.Logger.Error("unable to relay the outbox events", zap.String("transaction_id", err.Trace), zap.String("error", err.Message))
+		}
+		if err == nil && published == r.batchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		if time.Since(r.lastPurge) >= r.purgeInterval {
+			r.lastPurge = time.Now()
+			r.purge(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			utils.Logger.Info("stopped the outbox relay")
+			return
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) (int, *limitoffererror.CreditCardError) {
+	ginCtx := utils.NewBackgroundContext()
+	ginCtx.Request = ginCtx.Request.WithContext(ctx)
+
+	publish := func(event models.OutboxEvent) error {
+		return r.publisher.Publish(ctx, event)
+	}
+	retryAfter := func(attempts int) time.Duration {
+		return Backoff(r.retryBackoff, r.maxBackoff, attempts)
+	}
+	return r.repo.RelayOutboxEvents(ginCtx, r.batchSize, r.lease, publish, retryAfter)
+}
+
+// purge deletes the published events older than the retention batch by batch, a batch which is not full is the last one
+func (r *Relay) purge(ctx context.Context) {
+	for ctx.Err() == nil {
+		ginCtx := utils.NewBackgroundContext()
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+		purged, err := r.repo.PurgeOutboxEvents(ginCtx, r.retention, r.batchSize)
+		if err != nil {
+			utils.Logger.Error("unable to purge the outbox events", zap.String("transaction_id", err.Trace), zap.String("error", err.Message))
+			return
+		}
+		if purged < r.batchSize {
+			return
+		}
+	}
+}
+
+// Backoff returns the delay before the next attempt of an event which failed attempts times,
+// the base delay is doubled with every failed attempt up to max
+func Backoff(base time.Duration, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
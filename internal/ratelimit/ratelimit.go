@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Store holds the token buckets of the rate limits. The in-memory store limits the requests served by one
+// replica, a store shared by the replicas, e.g. backed by redis, limits the requests served by all of them.
+type Store interface {
+	// Take takes a token from the bucket of the key, which is refilled with rate tokens per second up to burst
+	// tokens, and tells whether there was one, otherwise how long until there is
+	Take(ctx context.Context, key string, rate float64, burst int) (allowed bool, retryAfter time.Duration, err error)
+	// Refund puts back the token taken from the bucket of the key, e.g. when another limit rejects the request
+	Refund(ctx context.Context, key string) error
+}
+
+// sweepInterval is the interval between the removals of the buckets refilled to their burst, which are
+// no different from the buckets not created yet
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	// the time the tokens were counted at
+	updated time.Time
+	rate    float64
+	burst   float64
+}
+
+// refill adds the tokens earned since the bucket was last updated
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.updated).Seconds()*b.rate)
+	b.updated = now
+}
+
+// MemoryStore keeps the buckets in the memory of the process
+type MemoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns a store without any bucket
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{now: time.Now, buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updated: now}
+		s.buckets[key] = b
+	}
+	// the limits may be reloaded, the bucket follows the current ones
+	b.rate, b.burst = rate, float64(burst)
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second)), nil
+}
+
+func (s *MemoryStore) Refund(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// the bucket swept since is full already
+	if b, ok := s.buckets[key]; ok {
+		b.refill(s.now())
+		b.tokens = math.Min(b.burst, b.tokens+1)
+	}
+	return nil
+}
+
+// sweep removes the full buckets every sweepInterval so that the keys seen once do not pile up
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	for key, b := range s.buckets {
+		if b.refill(now); b.tokens >= b.burst {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
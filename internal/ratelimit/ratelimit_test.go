@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	take := func(key string) (bool, time.Duration) {
+		allowed, retryAfter, err := store.Take(context.Background(), key, 2, 3)
+		assert.NoError(t, err)
+		return allowed, retryAfter
+	}
+
+	// the burst is allowed at once, then a token every half a second
+	for i := 0; i < 3; i++ {
+		allowed, _ := take("client:back-office")
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter := take("client:back-office")
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// the other keys have buckets of their own
+	allowed, _ = take("client:offers-batch")
+	assert.True(t, allowed)
+
+	now = now.Add(250 * time.Millisecond)
+	allowed, retryAfter = take("client:back-office")
+	assert.False(t, allowed)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+	now = now.Add(250 * time.Millisecond)
+	allowed, _ = take("client:back-office")
+	assert.True(t, allowed)
+
+	// the refunded token is taken again, up to the burst
+	allowed, _ = take("client:offers-batch")
+	assert.True(t, allowed)
+	assert.NoError(t, store.Refund(context.Background(), "client:offers-batch"))
+	allowed, _ = take("client:offers-batch")
+	assert.True(t, allowed)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, store.Refund(context.Background(), "client:offers-batch"))
+	}
+	assert.Equal(t, float64(3), store.buckets["client:offers-batch"].tokens)
+
+	// the buckets refilled to their burst are swept
+	now = now.Add(sweepInterval)
+	take("ip:10.0.0.1")
+	assert.Len(t, store.buckets, 1)
+}
@@ -0,0 +1,4 @@
+// Package limitofferpb holds the code generated from proto/limitoffer/v1/limitoffer.proto
+package limitofferpb
+
+//go:generate protoc -I ../../../proto --go_out=../../.. --go_opt=module=github.com/ankit/project/credit-card-offer-limit --go-grpc_out=../../.. --go-grpc_opt=module=github.com/ankit/project/credit-card-offer-limit limitoffer/v1/limitoffer.proto
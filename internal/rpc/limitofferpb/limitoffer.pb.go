@@ -0,0 +1,1577 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: limitoffer/v1/limitoffer.proto
+
+package limitofferpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LimitType int32
+
+const (
+	LimitType_LIMIT_TYPE_UNSPECIFIED           LimitType = 0
+	LimitType_LIMIT_TYPE_ACCOUNT_LIMIT         LimitType = 1
+	LimitType_LIMIT_TYPE_PER_TRANSACTION_LIMIT LimitType = 2
+)
+
+// Enum value maps for LimitType.
+var (
+	LimitType_name = map[int32]string{
+		0: "LIMIT_TYPE_UNSPECIFIED",
+		1: "LIMIT_TYPE_ACCOUNT_LIMIT",
+		2: "LIMIT_TYPE_PER_TRANSACTION_LIMIT",
+	}
+	LimitType_value = map[string]int32{
+		"LIMIT_TYPE_UNSPECIFIED":           0,
+		"LIMIT_TYPE_ACCOUNT_LIMIT":         1,
+		"LIMIT_TYPE_PER_TRANSACTION_LIMIT": 2,
+	}
+)
+
+func (x LimitType) Enum() *LimitType {
+	p := new(LimitType)
+	*p = x
+	return p
+}
+
+func (x LimitType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LimitType) Descriptor() protoreflect.EnumDescriptor {
+	return file_limitoffer_v1_limitoffer_proto_enumTypes[0].Descriptor()
+}
+
+func (LimitType) Type() protoreflect.EnumType {
+	return &file_limitoffer_v1_limitoffer_proto_enumTypes[0]
+}
+
+func (x LimitType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LimitType.Descriptor instead.
+func (LimitType) EnumDescriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{0}
+}
+
+type OfferStatus int32
+
+const (
+	OfferStatus_OFFER_STATUS_UNSPECIFIED       OfferStatus = 0
+	OfferStatus_OFFER_STATUS_PENDING           OfferStatus = 1
+	OfferStatus_OFFER_STATUS_ACCEPTED          OfferStatus = 2
+	OfferStatus_OFFER_STATUS_REJECTED          OfferStatus = 3
+	OfferStatus_OFFER_STATUS_SUPERSEDED        OfferStatus = 4
+	OfferStatus_OFFER_STATUS_CANCELLED         OfferStatus = 5
+	OfferStatus_OFFER_STATUS_AWAITING_APPROVAL OfferStatus = 6
+	OfferStatus_OFFER_STATUS_DECLINED          OfferStatus = 7
+	OfferStatus_OFFER_STATUS_EXPIRED           OfferStatus = 8
+)
+
+// Enum value maps for OfferStatus.
+var (
+	OfferStatus_name = map[int32]string{
+		0: "OFFER_STATUS_UNSPECIFIED",
+		1: "OFFER_STATUS_PENDING",
+		2: "OFFER_STATUS_ACCEPTED",
+		3: "OFFER_STATUS_REJECTED",
+		4: "OFFER_STATUS_SUPERSEDED",
+		5: "OFFER_STATUS_CANCELLED",
+		6: "OFFER_STATUS_AWAITING_APPROVAL",
+		7: "OFFER_STATUS_DECLINED",
+		8: "OFFER_STATUS_EXPIRED",
+	}
+	OfferStatus_value = map[string]int32{
+		"OFFER_STATUS_UNSPECIFIED":       0,
+		"OFFER_STATUS_PENDING":           1,
+		"OFFER_STATUS_ACCEPTED":          2,
+		"OFFER_STATUS_REJECTED":          3,
+		"OFFER_STATUS_SUPERSEDED":        4,
+		"OFFER_STATUS_CANCELLED":         5,
+		"OFFER_STATUS_AWAITING_APPROVAL": 6,
+		"OFFER_STATUS_DECLINED":          7,
+		"OFFER_STATUS_EXPIRED":           8,
+	}
+)
+
+func (x OfferStatus) Enum() *OfferStatus {
+	p := new(OfferStatus)
+	*p = x
+	return p
+}
+
+func (x OfferStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OfferStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_limitoffer_v1_limitoffer_proto_enumTypes[1].Descriptor()
+}
+
+func (OfferStatus) Type() protoreflect.EnumType {
+	return &file_limitoffer_v1_limitoffer_proto_enumTypes[1]
+}
+
+func (x OfferStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OfferStatus.Descriptor instead.
+func (OfferStatus) EnumDescriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{1}
+}
+
+type DecisionChannel int32
+
+const (
+	DecisionChannel_DECISION_CHANNEL_UNSPECIFIED DecisionChannel = 0
+	DecisionChannel_DECISION_CHANNEL_MOBILE      DecisionChannel = 1
+	DecisionChannel_DECISION_CHANNEL_WEB         DecisionChannel = 2
+	DecisionChannel_DECISION_CHANNEL_AGENT       DecisionChannel = 3
+	DecisionChannel_DECISION_CHANNEL_API         DecisionChannel = 4
+)
+
+// Enum value maps for DecisionChannel.
+var (
+	DecisionChannel_name = map[int32]string{
+		0: "DECISION_CHANNEL_UNSPECIFIED",
+		1: "DECISION_CHANNEL_MOBILE",
+		2: "DECISION_CHANNEL_WEB",
+		3: "DECISION_CHANNEL_AGENT",
+		4: "DECISION_CHANNEL_API",
+	}
+	DecisionChannel_value = map[string]int32{
+		"DECISION_CHANNEL_UNSPECIFIED": 0,
+		"DECISION_CHANNEL_MOBILE":      1,
+		"DECISION_CHANNEL_WEB":         2,
+		"DECISION_CHANNEL_AGENT":       3,
+		"DECISION_CHANNEL_API":         4,
+	}
+)
+
+func (x DecisionChannel) Enum() *DecisionChannel {
+	p := new(DecisionChannel)
+	*p = x
+	return p
+}
+
+func (x DecisionChannel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DecisionChannel) Descriptor() protoreflect.EnumDescriptor {
+	return file_limitoffer_v1_limitoffer_proto_enumTypes[2].Descriptor()
+}
+
+func (DecisionChannel) Type() protoreflect.EnumType {
+	return &file_limitoffer_v1_limitoffer_proto_enumTypes[2]
+}
+
+func (x DecisionChannel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DecisionChannel.Descriptor instead.
+func (DecisionChannel) EnumDescriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{2}
+}
+
+type Decision int32
+
+const (
+	Decision_DECISION_UNSPECIFIED Decision = 0
+	Decision_DECISION_ACCEPTED    Decision = 1
+	Decision_DECISION_REJECTED    Decision = 2
+)
+
+// Enum value maps for Decision.
+var (
+	Decision_name = map[int32]string{
+		0: "DECISION_UNSPECIFIED",
+		1: "DECISION_ACCEPTED",
+		2: "DECISION_REJECTED",
+	}
+	Decision_value = map[string]int32{
+		"DECISION_UNSPECIFIED": 0,
+		"DECISION_ACCEPTED":    1,
+		"DECISION_REJECTED":    2,
+	}
+)
+
+func (x Decision) Enum() *Decision {
+	p := new(Decision)
+	*p = x
+	return p
+}
+
+func (x Decision) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Decision) Descriptor() protoreflect.EnumDescriptor {
+	return file_limitoffer_v1_limitoffer_proto_enumTypes[3].Descriptor()
+}
+
+func (Decision) Type() protoreflect.EnumType {
+	return &file_limitoffer_v1_limitoffer_proto_enumTypes[3]
+}
+
+func (x Decision) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Decision.Descriptor instead.
+func (Decision) EnumDescriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{3}
+}
+
+type Account struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId                     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	CustomerId                    string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	AccountLimit                  int64                  `protobuf:"varint,3,opt,name=account_limit,json=accountLimit,proto3" json:"account_limit,omitempty"`
+	PerTransactionLimit           int64                  `protobuf:"varint,4,opt,name=per_transaction_limit,json=perTransactionLimit,proto3" json:"per_transaction_limit,omitempty"`
+	LastAccountLimit              int64                  `protobuf:"varint,5,opt,name=last_account_limit,json=lastAccountLimit,proto3" json:"last_account_limit,omitempty"`
+	LastPerTransactionLimit       int64                  `protobuf:"varint,6,opt,name=last_per_transaction_limit,json=lastPerTransactionLimit,proto3" json:"last_per_transaction_limit,omitempty"`
+	AccountLimitUpdateTime        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=account_limit_update_time,json=accountLimitUpdateTime,proto3" json:"account_limit_update_time,omitempty"`
+	PerTransactionLimitUpdateTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=per_transaction_limit_update_time,json=perTransactionLimitUpdateTime,proto3" json:"per_transaction_limit_update_time,omitempty"`
+	Email                         *string                `protobuf:"bytes,9,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	Locale                        string                 `protobuf:"bytes,10,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Account) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Account) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Account) GetAccountLimit() int64 {
+	if x != nil {
+		return x.AccountLimit
+	}
+	return 0
+}
+
+func (x *Account) GetPerTransactionLimit() int64 {
+	if x != nil {
+		return x.PerTransactionLimit
+	}
+	return 0
+}
+
+func (x *Account) GetLastAccountLimit() int64 {
+	if x != nil {
+		return x.LastAccountLimit
+	}
+	return 0
+}
+
+func (x *Account) GetLastPerTransactionLimit() int64 {
+	if x != nil {
+		return x.LastPerTransactionLimit
+	}
+	return 0
+}
+
+func (x *Account) GetAccountLimitUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccountLimitUpdateTime
+	}
+	return nil
+}
+
+func (x *Account) GetPerTransactionLimitUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PerTransactionLimitUpdateTime
+	}
+	return nil
+}
+
+func (x *Account) GetEmail() string {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return ""
+}
+
+func (x *Account) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type CreateAccountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// opens the account for an existing customer, a new customer id is generated when empty
+	CustomerId              string  `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	AccountLimit            *int64  `protobuf:"varint,2,opt,name=account_limit,json=accountLimit,proto3,oneof" json:"account_limit,omitempty"`
+	PerTransactionLimit     *int64  `protobuf:"varint,3,opt,name=per_transaction_limit,json=perTransactionLimit,proto3,oneof" json:"per_transaction_limit,omitempty"`
+	LastAccountLimit        *int64  `protobuf:"varint,4,opt,name=last_account_limit,json=lastAccountLimit,proto3,oneof" json:"last_account_limit,omitempty"`
+	LastPerTransactionLimit *int64  `protobuf:"varint,5,opt,name=last_per_transaction_limit,json=lastPerTransactionLimit,proto3,oneof" json:"last_per_transaction_limit,omitempty"`
+	Email                   *string `protobuf:"bytes,6,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	Locale                  string  `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAccountRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetAccountLimit() int64 {
+	if x != nil && x.AccountLimit != nil {
+		return *x.AccountLimit
+	}
+	return 0
+}
+
+func (x *CreateAccountRequest) GetPerTransactionLimit() int64 {
+	if x != nil && x.PerTransactionLimit != nil {
+		return *x.PerTransactionLimit
+	}
+	return 0
+}
+
+func (x *CreateAccountRequest) GetLastAccountLimit() int64 {
+	if x != nil && x.LastAccountLimit != nil {
+		return *x.LastAccountLimit
+	}
+	return 0
+}
+
+func (x *CreateAccountRequest) GetLastPerTransactionLimit() int64 {
+	if x != nil && x.LastPerTransactionLimit != nil {
+		return *x.LastPerTransactionLimit
+	}
+	return 0
+}
+
+func (x *CreateAccountRequest) GetEmail() string {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetAccountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+}
+
+func (x *GetAccountRequest) Reset() {
+	*x = GetAccountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountRequest) ProtoMessage() {}
+
+func (x *GetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type LimitOffer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId           string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	LimitType           LimitType              `protobuf:"varint,3,opt,name=limit_type,json=limitType,proto3,enum=limitoffer.v1.LimitType" json:"limit_type,omitempty"`
+	NewLimit            int64                  `protobuf:"varint,4,opt,name=new_limit,json=newLimit,proto3" json:"new_limit,omitempty"`
+	OfferActivationTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=offer_activation_time,json=offerActivationTime,proto3" json:"offer_activation_time,omitempty"`
+	OfferExpiryTime     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=offer_expiry_time,json=offerExpiryTime,proto3" json:"offer_expiry_time,omitempty"`
+	Status              OfferStatus            `protobuf:"varint,7,opt,name=status,proto3,enum=limitoffer.v1.OfferStatus" json:"status,omitempty"`
+	CreatedAt           *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt           *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DecidedAt           *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=decided_at,json=decidedAt,proto3" json:"decided_at,omitempty"`
+	DecidedBy           *string                `protobuf:"bytes,11,opt,name=decided_by,json=decidedBy,proto3,oneof" json:"decided_by,omitempty"`
+	DecisionChannel     DecisionChannel        `protobuf:"varint,12,opt,name=decision_channel,json=decisionChannel,proto3,enum=limitoffer.v1.DecisionChannel" json:"decision_channel,omitempty"`
+	SupersededBy        *string                `protobuf:"bytes,13,opt,name=superseded_by,json=supersededBy,proto3,oneof" json:"superseded_by,omitempty"`
+	CreatedBy           *string                `protobuf:"bytes,14,opt,name=created_by,json=createdBy,proto3,oneof" json:"created_by,omitempty"`
+	ReviewedAt          *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
+	ReviewedBy          *string                `protobuf:"bytes,16,opt,name=reviewed_by,json=reviewedBy,proto3,oneof" json:"reviewed_by,omitempty"`
+}
+
+func (x *LimitOffer) Reset() {
+	*x = LimitOffer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LimitOffer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitOffer) ProtoMessage() {}
+
+func (x *LimitOffer) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitOffer.ProtoReflect.Descriptor instead.
+func (*LimitOffer) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LimitOffer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LimitOffer) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *LimitOffer) GetLimitType() LimitType {
+	if x != nil {
+		return x.LimitType
+	}
+	return LimitType_LIMIT_TYPE_UNSPECIFIED
+}
+
+func (x *LimitOffer) GetNewLimit() int64 {
+	if x != nil {
+		return x.NewLimit
+	}
+	return 0
+}
+
+func (x *LimitOffer) GetOfferActivationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OfferActivationTime
+	}
+	return nil
+}
+
+func (x *LimitOffer) GetOfferExpiryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OfferExpiryTime
+	}
+	return nil
+}
+
+func (x *LimitOffer) GetStatus() OfferStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OfferStatus_OFFER_STATUS_UNSPECIFIED
+}
+
+func (x *LimitOffer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *LimitOffer) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *LimitOffer) GetDecidedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DecidedAt
+	}
+	return nil
+}
+
+func (x *LimitOffer) GetDecidedBy() string {
+	if x != nil && x.DecidedBy != nil {
+		return *x.DecidedBy
+	}
+	return ""
+}
+
+func (x *LimitOffer) GetDecisionChannel() DecisionChannel {
+	if x != nil {
+		return x.DecisionChannel
+	}
+	return DecisionChannel_DECISION_CHANNEL_UNSPECIFIED
+}
+
+func (x *LimitOffer) GetSupersededBy() string {
+	if x != nil && x.SupersededBy != nil {
+		return *x.SupersededBy
+	}
+	return ""
+}
+
+func (x *LimitOffer) GetCreatedBy() string {
+	if x != nil && x.CreatedBy != nil {
+		return *x.CreatedBy
+	}
+	return ""
+}
+
+func (x *LimitOffer) GetReviewedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReviewedAt
+	}
+	return nil
+}
+
+func (x *LimitOffer) GetReviewedBy() string {
+	if x != nil && x.ReviewedBy != nil {
+		return *x.ReviewedBy
+	}
+	return ""
+}
+
+type CreateLimitOfferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId           string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	LimitType           LimitType              `protobuf:"varint,2,opt,name=limit_type,json=limitType,proto3,enum=limitoffer.v1.LimitType" json:"limit_type,omitempty"`
+	NewLimit            *int64                 `protobuf:"varint,3,opt,name=new_limit,json=newLimit,proto3,oneof" json:"new_limit,omitempty"`
+	OfferActivationTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=offer_activation_time,json=offerActivationTime,proto3" json:"offer_activation_time,omitempty"`
+	OfferExpiryTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=offer_expiry_time,json=offerExpiryTime,proto3" json:"offer_expiry_time,omitempty"`
+}
+
+func (x *CreateLimitOfferRequest) Reset() {
+	*x = CreateLimitOfferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLimitOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLimitOfferRequest) ProtoMessage() {}
+
+func (x *CreateLimitOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLimitOfferRequest.ProtoReflect.Descriptor instead.
+func (*CreateLimitOfferRequest) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateLimitOfferRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CreateLimitOfferRequest) GetLimitType() LimitType {
+	if x != nil {
+		return x.LimitType
+	}
+	return LimitType_LIMIT_TYPE_UNSPECIFIED
+}
+
+func (x *CreateLimitOfferRequest) GetNewLimit() int64 {
+	if x != nil && x.NewLimit != nil {
+		return *x.NewLimit
+	}
+	return 0
+}
+
+func (x *CreateLimitOfferRequest) GetOfferActivationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OfferActivationTime
+	}
+	return nil
+}
+
+func (x *CreateLimitOfferRequest) GetOfferExpiryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OfferExpiryTime
+	}
+	return nil
+}
+
+type ListLimitOffersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Status         []OfferStatus          `protobuf:"varint,2,rep,packed,name=status,proto3,enum=limitoffer.v1.OfferStatus" json:"status,omitempty"`
+	LimitType      []LimitType            `protobuf:"varint,3,rep,packed,name=limit_type,json=limitType,proto3,enum=limitoffer.v1.LimitType" json:"limit_type,omitempty"`
+	CreatedFrom    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	ActivationFrom *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=activation_from,json=activationFrom,proto3" json:"activation_from,omitempty"`
+	ActivationTo   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=activation_to,json=activationTo,proto3" json:"activation_to,omitempty"`
+	ExpiryFrom     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expiry_from,json=expiryFrom,proto3" json:"expiry_from,omitempty"`
+	ExpiryTo       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expiry_to,json=expiryTo,proto3" json:"expiry_to,omitempty"`
+	// created_at, offer_activation_time, offer_expiry_time or new_limit, created_at by default
+	SortBy string `protobuf:"bytes,10,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// asc or desc, desc by default
+	SortOrder string `protobuf:"bytes,11,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	PageSize  int32  `protobuf:"varint,12,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_cursor of the previous page
+	Cursor string `protobuf:"bytes,13,opt,name=cursor,proto3" json:"cursor,omitempty"`
+}
+
+func (x *ListLimitOffersRequest) Reset() {
+	*x = ListLimitOffersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLimitOffersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLimitOffersRequest) ProtoMessage() {}
+
+func (x *ListLimitOffersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLimitOffersRequest.ProtoReflect.Descriptor instead.
+func (*ListLimitOffersRequest) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLimitOffersRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListLimitOffersRequest) GetStatus() []OfferStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetLimitType() []LimitType {
+	if x != nil {
+		return x.LimitType
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetActivationFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActivationFrom
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetActivationTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActivationTo
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetExpiryFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiryFrom
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetExpiryTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiryTo
+	}
+	return nil
+}
+
+func (x *ListLimitOffersRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListLimitOffersRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *ListLimitOffersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLimitOffersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListLimitOffersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LimitOffers []*LimitOffer `protobuf:"bytes,1,rep,name=limit_offers,json=limitOffers,proto3" json:"limit_offers,omitempty"`
+	NextCursor  string        `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	TotalCount  int32         `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+}
+
+func (x *ListLimitOffersResponse) Reset() {
+	*x = ListLimitOffersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLimitOffersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLimitOffersResponse) ProtoMessage() {}
+
+func (x *ListLimitOffersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLimitOffersResponse.ProtoReflect.Descriptor instead.
+func (*ListLimitOffersResponse) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLimitOffersResponse) GetLimitOffers() []*LimitOffer {
+	if x != nil {
+		return x.LimitOffers
+	}
+	return nil
+}
+
+func (x *ListLimitOffersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListLimitOffersResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetLimitOfferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LimitOfferId string `protobuf:"bytes,1,opt,name=limit_offer_id,json=limitOfferId,proto3" json:"limit_offer_id,omitempty"`
+}
+
+func (x *GetLimitOfferRequest) Reset() {
+	*x = GetLimitOfferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLimitOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLimitOfferRequest) ProtoMessage() {}
+
+func (x *GetLimitOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLimitOfferRequest.ProtoReflect.Descriptor instead.
+func (*GetLimitOfferRequest) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLimitOfferRequest) GetLimitOfferId() string {
+	if x != nil {
+		return x.LimitOfferId
+	}
+	return ""
+}
+
+type DecideLimitOfferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LimitOfferId    string          `protobuf:"bytes,1,opt,name=limit_offer_id,json=limitOfferId,proto3" json:"limit_offer_id,omitempty"`
+	Decision        Decision        `protobuf:"varint,2,opt,name=decision,proto3,enum=limitoffer.v1.Decision" json:"decision,omitempty"`
+	DecisionChannel DecisionChannel `protobuf:"varint,3,opt,name=decision_channel,json=decisionChannel,proto3,enum=limitoffer.v1.DecisionChannel" json:"decision_channel,omitempty"`
+}
+
+func (x *DecideLimitOfferRequest) Reset() {
+	*x = DecideLimitOfferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecideLimitOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecideLimitOfferRequest) ProtoMessage() {}
+
+func (x *DecideLimitOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_limitoffer_v1_limitoffer_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecideLimitOfferRequest.ProtoReflect.Descriptor instead.
+func (*DecideLimitOfferRequest) Descriptor() ([]byte, []int) {
+	return file_limitoffer_v1_limitoffer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DecideLimitOfferRequest) GetLimitOfferId() string {
+	if x != nil {
+		return x.LimitOfferId
+	}
+	return ""
+}
+
+func (x *DecideLimitOfferRequest) GetDecision() Decision {
+	if x != nil {
+		return x.Decision
+	}
+	return Decision_DECISION_UNSPECIFIED
+}
+
+func (x *DecideLimitOfferRequest) GetDecisionChannel() DecisionChannel {
+	if x != nil {
+		return x.DecisionChannel
+	}
+	return DecisionChannel_DECISION_CHANNEL_UNSPECIFIED
+}
+
+var File_limitoffer_v1_limitoffer_proto protoreflect.FileDescriptor
+
+var file_limitoffer_v1_limitoffer_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0d, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x87, 0x04, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0c, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x32, 0x0a, 0x15, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x13, 0x70, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x10, 0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x3b, 0x0a, 0x1a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x17, 0x6c, 0x61, 0x73, 0x74, 0x50, 0x65, 0x72,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x55, 0x0a, 0x19, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x16, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x64, 0x0a, 0x21, 0x70, 0x65, 0x72, 0x5f, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x1d,
+	0x70, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a,
+	0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x22, 0xae, 0x03, 0x0a, 0x14, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x0d, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0c, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x37,
+	0x0a, 0x15, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52,
+	0x13, 0x70, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x10, 0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x1a, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03,
+	0x52, 0x17, 0x6c, 0x61, 0x73, 0x74, 0x50, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x05, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x42, 0x15, 0x0a, 0x13, 0x5f,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x42, 0x1d, 0x0a, 0x1b, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x22, 0x32, 0x0a, 0x11, 0x47,
+	0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x22,
+	0xee, 0x06, 0x0a, 0x0a, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x37, 0x0a,
+	0x0a, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x18, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x65, 0x77, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x4e, 0x0a, 0x15, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x13,
+	0x6f, 0x66, 0x66, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x46, 0x0a, 0x11, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x6f, 0x66, 0x66, 0x65,
+	0x72, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x66, 0x66, 0x65,
+	0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x22, 0x0a, 0x0a, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64, 0x42,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x10, 0x64, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e,
+	0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x0f,
+	0x64, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12,
+	0x28, 0x0a, 0x0d, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73, 0x65, 0x64, 0x65, 0x64, 0x5f, 0x62, 0x79,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x64, 0x65, 0x64, 0x42, 0x79, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a,
+	0x0b, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x41, 0x74, 0x12, 0x24, 0x0a, 0x0b, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x03, 0x52, 0x0a, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x42, 0x79, 0x88, 0x01, 0x01,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64, 0x65, 0x63, 0x69, 0x64, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73, 0x65, 0x64, 0x65, 0x64, 0x5f, 0x62,
+	0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x5f, 0x62, 0x79,
+	0x22, 0xb9, 0x02, 0x0a, 0x17, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x4f, 0x66, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x0a, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x18, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x08, 0x6e, 0x65, 0x77, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x4e, 0x0a, 0x15, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x13, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x46, 0x0a, 0x11, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x5f,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x6f,
+	0x66, 0x66, 0x65, 0x72, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0c,
+	0x0a, 0x0a, 0x5f, 0x6e, 0x65, 0x77, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x87, 0x05, 0x0a,
+	0x16, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66,
+	0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x37, 0x0a, 0x0a, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x18,
+	0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x54, 0x6f, 0x12, 0x43, 0x0a,
+	0x0f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x3f, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x74, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x6f, 0x12, 0x3b, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x66, 0x72,
+	0x6f, 0x6d, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x46, 0x72, 0x6f, 0x6d,
+	0x12, 0x37, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x74, 0x6f, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x54, 0x6f, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x6f, 0x72,
+	0x74, 0x5f, 0x62, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x72, 0x74,
+	0x42, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x6f, 0x72, 0x74, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x6f, 0x72, 0x74, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x99, 0x01, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66,
+	0x66, 0x65, 0x72, 0x52, 0x0b, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f,
+	0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66,
+	0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x49, 0x64,
+	0x22, 0xbf, 0x01, 0x0a, 0x17, 0x44, 0x65, 0x63, 0x69, 0x64, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x4f, 0x66, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x33, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64,
+	0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a, 0x10, 0x64, 0x65, 0x63, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1e, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x0f, 0x64, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x2a, 0x6b, 0x0a, 0x09, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1a, 0x0a, 0x16, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x4c,
+	0x49, 0x4d, 0x49, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x43, 0x43, 0x4f, 0x55, 0x4e,
+	0x54, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x01, 0x12, 0x24, 0x0a, 0x20, 0x4c, 0x49, 0x4d,
+	0x49, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x41, 0x4e,
+	0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x02, 0x2a,
+	0x8d, 0x02, 0x0a, 0x0b, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x1c, 0x0a, 0x18, 0x4f, 0x46, 0x46, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a,
+	0x14, 0x4f, 0x46, 0x46, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45,
+	0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x4f, 0x46, 0x46, 0x45, 0x52,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x45, 0x44,
+	0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x4f, 0x46, 0x46, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1b, 0x0a,
+	0x17, 0x4f, 0x46, 0x46, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x55,
+	0x50, 0x45, 0x52, 0x53, 0x45, 0x44, 0x45, 0x44, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x46,
+	0x46, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45,
+	0x4c, 0x4c, 0x45, 0x44, 0x10, 0x05, 0x12, 0x22, 0x0a, 0x1e, 0x4f, 0x46, 0x46, 0x45, 0x52, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f,
+	0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x41, 0x4c, 0x10, 0x06, 0x12, 0x19, 0x0a, 0x15, 0x4f, 0x46,
+	0x46, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x45, 0x43, 0x4c, 0x49,
+	0x4e, 0x45, 0x44, 0x10, 0x07, 0x12, 0x18, 0x0a, 0x14, 0x4f, 0x46, 0x46, 0x45, 0x52, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x08, 0x2a,
+	0xa0, 0x01, 0x0a, 0x0f, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x20, 0x0a, 0x1c, 0x44, 0x45, 0x43, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f,
+	0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x44, 0x45, 0x43, 0x49, 0x53, 0x49, 0x4f,
+	0x4e, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4d, 0x4f, 0x42, 0x49, 0x4c, 0x45,
+	0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x44, 0x45, 0x43, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x57, 0x45, 0x42, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16,
+	0x44, 0x45, 0x43, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c,
+	0x5f, 0x41, 0x47, 0x45, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x18, 0x0a, 0x14, 0x44, 0x45, 0x43, 0x49,
+	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x41, 0x50, 0x49,
+	0x10, 0x04, 0x2a, 0x52, 0x0a, 0x08, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18,
+	0x0a, 0x14, 0x44, 0x45, 0x43, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x44, 0x45, 0x43, 0x49,
+	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12,
+	0x15, 0x0a, 0x11, 0x44, 0x45, 0x43, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x4a, 0x45,
+	0x43, 0x54, 0x45, 0x44, 0x10, 0x02, 0x32, 0x8a, 0x04, 0x0a, 0x11, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x4f, 0x66, 0x66, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4c, 0x0a, 0x0d,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x23, 0x2e,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x46, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x55, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x26, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66,
+	0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x60, 0x0a, 0x0f, 0x4c, 0x69, 0x73,
+	0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x73, 0x12, 0x25, 0x2e, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0d, 0x47,
+	0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x23, 0x2e, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x55, 0x0a, 0x10,
+	0x44, 0x65, 0x63, 0x69, 0x64, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65, 0x72,
+	0x12, 0x26, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x63, 0x69, 0x64, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66, 0x66, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x6f, 0x66, 0x66, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4f, 0x66,
+	0x66, 0x65, 0x72, 0x42, 0x59, 0x5a, 0x57, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x61, 0x6e, 0x6b, 0x69, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f,
+	0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x2d, 0x63, 0x61, 0x72, 0x64, 0x2d, 0x6f, 0x66, 0x66, 0x65,
+	0x72, 0x2d, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x70,
+	0x62, 0x3b, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_limitoffer_v1_limitoffer_proto_rawDescOnce sync.Once
+	file_limitoffer_v1_limitoffer_proto_rawDescData = file_limitoffer_v1_limitoffer_proto_rawDesc
+)
+
+func file_limitoffer_v1_limitoffer_proto_rawDescGZIP() []byte {
+	file_limitoffer_v1_limitoffer_proto_rawDescOnce.Do(func() {
+		file_limitoffer_v1_limitoffer_proto_rawDescData = protoimpl.X.CompressGZIP(file_limitoffer_v1_limitoffer_proto_rawDescData)
+	})
+	return file_limitoffer_v1_limitoffer_proto_rawDescData
+}
+
+var file_limitoffer_v1_limitoffer_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_limitoffer_v1_limitoffer_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_limitoffer_v1_limitoffer_proto_goTypes = []interface{}{
+	(LimitType)(0),                  // 0: limitoffer.v1.LimitType
+	(OfferStatus)(0),                // 1: limitoffer.v1.OfferStatus
+	(DecisionChannel)(0),            // 2: limitoffer.v1.DecisionChannel
+	(Decision)(0),                   // 3: limitoffer.v1.Decision
+	(*Account)(nil),                 // 4: limitoffer.v1.Account
+	(*CreateAccountRequest)(nil),    // 5: limitoffer.v1.CreateAccountRequest
+	(*GetAccountRequest)(nil),       // 6: limitoffer.v1.GetAccountRequest
+	(*LimitOffer)(nil),              // 7: limitoffer.v1.LimitOffer
+	(*CreateLimitOfferRequest)(nil), // 8: limitoffer.v1.CreateLimitOfferRequest
+	(*ListLimitOffersRequest)(nil),  // 9: limitoffer.v1.ListLimitOffersRequest
+	(*ListLimitOffersResponse)(nil), // 10: limitoffer.v1.ListLimitOffersResponse
+	(*GetLimitOfferRequest)(nil),    // 11: limitoffer.v1.GetLimitOfferRequest
+	(*DecideLimitOfferRequest)(nil), // 12: limitoffer.v1.DecideLimitOfferRequest
+	(*timestamppb.Timestamp)(nil),   // 13: google.protobuf.Timestamp
+}
+var file_limitoffer_v1_limitoffer_proto_depIdxs = []int32{
+	13, // 0: limitoffer.v1.Account.account_limit_update_time:type_name -> google.protobuf.Timestamp
+	13, // 1: limitoffer.v1.Account.per_transaction_limit_update_time:type_name -> google.protobuf.Timestamp
+	0,  // 2: limitoffer.v1.LimitOffer.limit_type:type_name -> limitoffer.v1.LimitType
+	13, // 3: limitoffer.v1.LimitOffer.offer_activation_time:type_name -> google.protobuf.Timestamp
+	13, // 4: limitoffer.v1.LimitOffer.offer_expiry_time:type_name -> google.protobuf.Timestamp
+	1,  // 5: limitoffer.v1.LimitOffer.status:type_name -> limitoffer.v1.OfferStatus
+	13, // 6: limitoffer.v1.LimitOffer.created_at:type_name -> google.protobuf.Timestamp
+	13, // 7: limitoffer.v1.LimitOffer.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 8: limitoffer.v1.LimitOffer.decided_at:type_name -> google.protobuf.Timestamp
+	2,  // 9: limitoffer.v1.LimitOffer.decision_channel:type_name -> limitoffer.v1.DecisionChannel
+	13, // 10: limitoffer.v1.LimitOffer.reviewed_at:type_name -> google.protobuf.Timestamp
+	0,  // 11: limitoffer.v1.CreateLimitOfferRequest.limit_type:type_name -> limitoffer.v1.LimitType
+	13, // 12: limitoffer.v1.CreateLimitOfferRequest.offer_activation_time:type_name -> google.protobuf.Timestamp
+	13, // 13: limitoffer.v1.CreateLimitOfferRequest.offer_expiry_time:type_name -> google.protobuf.Timestamp
+	1,  // 14: limitoffer.v1.ListLimitOffersRequest.status:type_name -> limitoffer.v1.OfferStatus
+	0,  // 15: limitoffer.v1.ListLimitOffersRequest.limit_type:type_name -> limitoffer.v1.LimitType
+	13, // 16: limitoffer.v1.ListLimitOffersRequest.created_from:type_name -> google.protobuf.Timestamp
+	13, // 17: limitoffer.v1.ListLimitOffersRequest.created_to:type_name -> google.protobuf.Timestamp
+	13, // 18: limitoffer.v1.ListLimitOffersRequest.activation_from:type_name -> google.protobuf.Timestamp
+	13, // 19: limitoffer.v1.ListLimitOffersRequest.activation_to:type_name -> google.protobuf.Timestamp
+	13, // 20: limitoffer.v1.ListLimitOffersRequest.expiry_from:type_name -> google.protobuf.Timestamp
+	13, // 21: limitoffer.v1.ListLimitOffersRequest.expiry_to:type_name -> google.protobuf.Timestamp
+	7,  // 22: limitoffer.v1.ListLimitOffersResponse.limit_offers:type_name -> limitoffer.v1.LimitOffer
+	3,  // 23: limitoffer.v1.DecideLimitOfferRequest.decision:type_name -> limitoffer.v1.Decision
+	2,  // 24: limitoffer.v1.DecideLimitOfferRequest.decision_channel:type_name -> limitoffer.v1.DecisionChannel
+	5,  // 25: limitoffer.v1.LimitOfferService.CreateAccount:input_type -> limitoffer.v1.CreateAccountRequest
+	6,  // 26: limitoffer.v1.LimitOfferService.GetAccount:input_type -> limitoffer.v1.GetAccountRequest
+	8,  // 27: limitoffer.v1.LimitOfferService.CreateLimitOffer:input_type -> limitoffer.v1.CreateLimitOfferRequest
+	9,  // 28: limitoffer.v1.LimitOfferService.ListLimitOffers:input_type -> limitoffer.v1.ListLimitOffersRequest
+	11, // 29: limitoffer.v1.LimitOfferService.GetLimitOffer:input_type -> limitoffer.v1.GetLimitOfferRequest
+	12, // 30: limitoffer.v1.LimitOfferService.DecideLimitOffer:input_type -> limitoffer.v1.DecideLimitOfferRequest
+	4,  // 31: limitoffer.v1.LimitOfferService.CreateAccount:output_type -> limitoffer.v1.Account
+	4,  // 32: limitoffer.v1.LimitOfferService.GetAccount:output_type -> limitoffer.v1.Account
+	7,  // 33: limitoffer.v1.LimitOfferService.CreateLimitOffer:output_type -> limitoffer.v1.LimitOffer
+	10, // 34: limitoffer.v1.LimitOfferService.ListLimitOffers:output_type -> limitoffer.v1.ListLimitOffersResponse
+	7,  // 35: limitoffer.v1.LimitOfferService.GetLimitOffer:output_type -> limitoffer.v1.LimitOffer
+	7,  // 36: limitoffer.v1.LimitOfferService.DecideLimitOffer:output_type -> limitoffer.v1.LimitOffer
+	31, // [31:37] is the sub-list for method output_type
+	25, // [25:31] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_limitoffer_v1_limitoffer_proto_init() }
+func file_limitoffer_v1_limitoffer_proto_init() {
+	if File_limitoffer_v1_limitoffer_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_limitoffer_v1_limitoffer_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Account); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateAccountRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAccountRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LimitOffer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateLimitOfferRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLimitOffersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLimitOffersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLimitOfferRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_limitoffer_v1_limitoffer_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecideLimitOfferRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_limitoffer_v1_limitoffer_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_limitoffer_v1_limitoffer_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_limitoffer_v1_limitoffer_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_limitoffer_v1_limitoffer_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_limitoffer_v1_limitoffer_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_limitoffer_v1_limitoffer_proto_goTypes,
+		DependencyIndexes: file_limitoffer_v1_limitoffer_proto_depIdxs,
+		EnumInfos:         file_limitoffer_v1_limitoffer_proto_enumTypes,
+		MessageInfos:      file_limitoffer_v1_limitoffer_proto_msgTypes,
+	}.Build()
+	File_limitoffer_v1_limitoffer_proto = out.File
+	file_limitoffer_v1_limitoffer_proto_rawDesc = nil
+	file_limitoffer_v1_limitoffer_proto_goTypes = nil
+	file_limitoffer_v1_limitoffer_proto_depIdxs = nil
+}
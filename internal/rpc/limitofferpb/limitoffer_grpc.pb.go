@@ -0,0 +1,306 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: limitoffer/v1/limitoffer.proto
+
+package limitofferpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	LimitOfferService_CreateAccount_FullMethodName    = "/limitoffer.v1.LimitOfferService/CreateAccount"
+	LimitOfferService_GetAccount_FullMethodName       = "/limitoffer.v1.LimitOfferService/GetAccount"
+	LimitOfferService_CreateLimitOffer_FullMethodName = "/limitoffer.v1.LimitOfferService/CreateLimitOffer"
+	LimitOfferService_ListLimitOffers_FullMethodName  = "/limitoffer.v1.LimitOfferService/ListLimitOffers"
+	LimitOfferService_GetLimitOffer_FullMethodName    = "/limitoffer.v1.LimitOfferService/GetLimitOffer"
+	LimitOfferService_DecideLimitOffer_FullMethodName = "/limitoffer.v1.LimitOfferService/DecideLimitOffer"
+)
+
+// LimitOfferServiceClient is the client API for LimitOfferService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LimitOfferServiceClient interface {
+	// requires the accounts:write scope
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// requires the accounts:read scope
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// requires the offers:write scope
+	CreateLimitOffer(ctx context.Context, in *CreateLimitOfferRequest, opts ...grpc.CallOption) (*LimitOffer, error)
+	// requires the offers:read scope
+	ListLimitOffers(ctx context.Context, in *ListLimitOffersRequest, opts ...grpc.CallOption) (*ListLimitOffersResponse, error)
+	// requires the offers:read scope
+	GetLimitOffer(ctx context.Context, in *GetLimitOfferRequest, opts ...grpc.CallOption) (*LimitOffer, error)
+	// accepts or rejects a pending offer, requires the offers:decide scope
+	DecideLimitOffer(ctx context.Context, in *DecideLimitOfferRequest, opts ...grpc.CallOption) (*LimitOffer, error)
+}
+
+type limitOfferServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLimitOfferServiceClient(cc grpc.ClientConnInterface) LimitOfferServiceClient {
+	return &limitOfferServiceClient{cc}
+}
+
+func (c *limitOfferServiceClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := c.cc.Invoke(ctx, LimitOfferService_CreateAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *limitOfferServiceClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := c.cc.Invoke(ctx, LimitOfferService_GetAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *limitOfferServiceClient) CreateLimitOffer(ctx context.Context, in *CreateLimitOfferRequest, opts ...grpc.CallOption) (*LimitOffer, error) {
+	out := new(LimitOffer)
+	err := c.cc.Invoke(ctx, LimitOfferService_CreateLimitOffer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *limitOfferServiceClient) ListLimitOffers(ctx context.Context, in *ListLimitOffersRequest, opts ...grpc.CallOption) (*ListLimitOffersResponse, error) {
+	out := new(ListLimitOffersResponse)
+	err := c.cc.Invoke(ctx, LimitOfferService_ListLimitOffers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *limitOfferServiceClient) GetLimitOffer(ctx context.Context, in *GetLimitOfferRequest, opts ...grpc.CallOption) (*LimitOffer, error) {
+	out := new(LimitOffer)
+	err := c.cc.Invoke(ctx, LimitOfferService_GetLimitOffer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *limitOfferServiceClient) DecideLimitOffer(ctx context.Context, in *DecideLimitOfferRequest, opts ...grpc.CallOption) (*LimitOffer, error) {
+	out := new(LimitOffer)
+	err := c.cc.Invoke(ctx, LimitOfferService_DecideLimitOffer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LimitOfferServiceServer is the server API for LimitOfferService service.
+// All implementations must embed UnimplementedLimitOfferServiceServer
+// for forward compatibility
+type LimitOfferServiceServer interface {
+	// requires the accounts:write scope
+	CreateAccount(context.Context, *CreateAccountRequest) (*Account, error)
+	// requires the accounts:read scope
+	GetAccount(context.Context, *GetAccountRequest) (*Account, error)
+	// requires the offers:write scope
+	CreateLimitOffer(context.Context, *CreateLimitOfferRequest) (*LimitOffer, error)
+	// requires the offers:read scope
+	ListLimitOffers(context.Context, *ListLimitOffersRequest) (*ListLimitOffersResponse, error)
+	// requires the offers:read scope
+	GetLimitOffer(context.Context, *GetLimitOfferRequest) (*LimitOffer, error)
+	// accepts or rejects a pending offer, requires the offers:decide scope
+	DecideLimitOffer(context.Context, *DecideLimitOfferRequest) (*LimitOffer, error)
+	mustEmbedUnimplementedLimitOfferServiceServer()
+}
+
+// UnimplementedLimitOfferServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLimitOfferServiceServer struct {
+}
+
+func (UnimplementedLimitOfferServiceServer) CreateAccount(context.Context, *CreateAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedLimitOfferServiceServer) GetAccount(context.Context, *GetAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedLimitOfferServiceServer) CreateLimitOffer(context.Context, *CreateLimitOfferRequest) (*LimitOffer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLimitOffer not implemented")
+}
+func (UnimplementedLimitOfferServiceServer) ListLimitOffers(context.Context, *ListLimitOffersRequest) (*ListLimitOffersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLimitOffers not implemented")
+}
+func (UnimplementedLimitOfferServiceServer) GetLimitOffer(context.Context, *GetLimitOfferRequest) (*LimitOffer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLimitOffer not implemented")
+}
+func (UnimplementedLimitOfferServiceServer) DecideLimitOffer(context.Context, *DecideLimitOfferRequest) (*LimitOffer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideLimitOffer not implemented")
+}
+func (UnimplementedLimitOfferServiceServer) mustEmbedUnimplementedLimitOfferServiceServer() {}
+
+// UnsafeLimitOfferServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LimitOfferServiceServer will
+// result in compilation errors.
+type UnsafeLimitOfferServiceServer interface {
+	mustEmbedUnimplementedLimitOfferServiceServer()
+}
+
+func RegisterLimitOfferServiceServer(s grpc.ServiceRegistrar, srv LimitOfferServiceServer) {
+	s.RegisterService(&LimitOfferService_ServiceDesc, srv)
+}
+
+func _LimitOfferService_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LimitOfferServiceServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LimitOfferService_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LimitOfferServiceServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LimitOfferService_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LimitOfferServiceServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LimitOfferService_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LimitOfferServiceServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LimitOfferService_CreateLimitOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLimitOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LimitOfferServiceServer).CreateLimitOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LimitOfferService_CreateLimitOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LimitOfferServiceServer).CreateLimitOffer(ctx, req.(*CreateLimitOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LimitOfferService_ListLimitOffers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLimitOffersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LimitOfferServiceServer).ListLimitOffers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LimitOfferService_ListLimitOffers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LimitOfferServiceServer).ListLimitOffers(ctx, req.(*ListLimitOffersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LimitOfferService_GetLimitOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLimitOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LimitOfferServiceServer).GetLimitOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LimitOfferService_GetLimitOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LimitOfferServiceServer).GetLimitOffer(ctx, req.(*GetLimitOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LimitOfferService_DecideLimitOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideLimitOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LimitOfferServiceServer).DecideLimitOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LimitOfferService_DecideLimitOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LimitOfferServiceServer).DecideLimitOffer(ctx, req.(*DecideLimitOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LimitOfferService_ServiceDesc is the grpc.ServiceDesc for LimitOfferService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LimitOfferService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "limitoffer.v1.LimitOfferService",
+	HandlerType: (*LimitOfferServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAccount",
+			Handler:    _LimitOfferService_CreateAccount_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _LimitOfferService_GetAccount_Handler,
+		},
+		{
+			MethodName: "CreateLimitOffer",
+			Handler:    _LimitOfferService_CreateLimitOffer_Handler,
+		},
+		{
+			MethodName: "ListLimitOffers",
+			Handler:    _LimitOfferService_ListLimitOffers_Handler,
+		},
+		{
+			MethodName: "GetLimitOffer",
+			Handler:    _LimitOfferService_GetLimitOffer_Handler,
+		},
+		{
+			MethodName: "DecideLimitOffer",
+			Handler:    _LimitOfferService_DecideLimitOffer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "limitoffer/v1/limitoffer.proto",
+}
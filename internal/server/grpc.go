@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/auth"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/middleware"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/rpc/limitofferpb"
+	"github.com/ankit/project/credit-card-offer-limit/internal/service"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcScopes are the scopes required by the methods of the grpc api, the same as their http endpoints
+var grpcScopes = map[string]string{
+	limitofferpb.LimitOfferService_CreateAccount_FullMethodName:    constants.ScopeAccountsWrite,
+	limitofferpb.LimitOfferService_GetAccount_FullMethodName:       constants.ScopeAccountsRead,
+	limitofferpb.LimitOfferService_CreateLimitOffer_FullMethodName: constants.ScopeOffersWrite,
+	limitofferpb.LimitOfferService_ListLimitOffers_FullMethodName:  constants.ScopeOffersRead,
+	limitofferpb.LimitOfferService_GetLimitOffer_FullMethodName:    constants.ScopeOffersRead,
+	limitofferpb.LimitOfferService_DecideLimitOffer_FullMethodName: constants.ScopeOffersDecide,
+}
+
+// grpcRoutes are the routes of the http api whose rate limits apply to the methods of the grpc api
+var grpcRoutes = map[string]string{
+	limitofferpb.LimitOfferService_CreateAccount_FullMethodName:    constants.CreateAccount,
+	limitofferpb.LimitOfferService_GetAccount_FullMethodName:       constants.GetAccount,
+	limitofferpb.LimitOfferService_CreateLimitOffer_FullMethodName: constants.CreateLimitOffer,
+	limitofferpb.LimitOfferService_ListLimitOffers_FullMethodName:  constants.ListLimitOffers,
+	limitofferpb.LimitOfferService_GetLimitOffer_FullMethodName:    constants.GetLimitOffer,
+	limitofferpb.LimitOfferService_DecideLimitOffer_FullMethodName: constants.UpdateLimitOfferStatus,
+}
+
+// startGRPC serves the grpc api on address until the returned server is stopped, the calls are limited with the
+// buckets of the store along with the http requests
+func startGRPC(address string, authenticator auth.Authenticator, rateLimitStore ratelimit.Store) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(unaryInterceptor(authenticator, rateLimitStore)))
+	limitofferpb.RegisterLimitOfferServiceServer(grpcServer, service.NewLimitOfferGRPCService())
+
+	go func() {
+		utils.Logger.Info(fmt.Sprintf("Starting gRPC Server on %v", address))
+		if err := grpcServer.Serve(listener); err != nil {
+			utils.Logger.Error("gRPC server stopped", zap.Error(err))
+		}
+	}()
+	return grpcServer, nil
+}
+
+// stopGRPC stops the server gracefully, the calls in flight are waited for until ctx is done and cancelled after
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		utils.Logger.Info("Cancelling the gRPC calls in flight")
+		grpcServer.Stop()
+		<-stopped
+	}
+}
+
+// unaryInterceptor does for the grpc calls what the middlewares do for the http requests. The metadata of the call
+// becomes the headers of a gin context, so the transaction id and the credentials are read the same way, the
+// transaction id is generated when missing and returned in the response header, the span of the call is continued
+// from the traceparent of the caller, the ip of the caller is rate limited, the caller is authenticated, its client
+// and account are rate limited and the scope of the method is checked. The gin context is passed to the method under
+// gin.ContextKey.
+func unaryInterceptor(authenticator auth.Authenticator, rateLimitStore ratelimit.Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+		header := http.Header{}
+		md, _ := metadata.FromIncomingContext(ctx)
+		for key, values := range md {
+			for _, value := range values {
+				header.Add(key, value)
+			}
+		}
+
+		txid := header.Get(constants.TransactionID)
+		if _, err := uuid.Parse(txid); err != nil {
+			txid = uuid.New().String()
+			header.Set(constants.TransactionID, txid)
+		}
+
+		spanCtx, span := tracing.StartRPC(ctx, info.FullMethod, header, txid)
+		defer func() {
+			tracing.EndRPC(span, err)
+		}()
+
+		request, err := http.NewRequestWithContext(spanCtx, http.MethodPost, info.FullMethod, nil)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		request.Header = header
+		ginCtx := &gin.Context{Request: request}
+		ginCtx.Set(constants.LogRoute, info.FullMethod)
+
+		responseHeader := metadata.Pairs(constants.TransactionID, txid)
+		for key, values := range tracing.Header(spanCtx) {
+			responseHeader.Append(key, values...)
+		}
+		if err := grpc.SetHeader(ctx, responseHeader); err != nil {
+			utils.RequestLogger(ginCtx).Info("unable to set the transaction id header", zap.Error(err))
+		}
+
+		route := grpcRoutes[info.FullMethod]
+		if err := rateLimit(ctx, ginCtx, rateLimitStore, route, req, constants.IPRateLimitKey); err != nil {
+			return nil, err
+		}
+
+		principal, err := authenticator.Authenticate(ginCtx)
+		var creditCardErr *limitoffererror.CreditCardError
+		if errors.As(err, &creditCardErr) {
+			utils.RequestLogger(ginCtx).Error("unable to verify the credentials", zap.Error(err))
+			return nil, service.GRPCError(creditCardErr)
+		}
+		if err != nil || principal == nil {
+			utils.RequestLogger(ginCtx).Info("grpc call is not authenticated", zap.Error(err))
+			return nil, status.Error(codes.Unauthenticated, constants.Unauthorized)
+		}
+		ginCtx.Set(constants.Principal, *principal)
+		if err := rateLimit(ctx, ginCtx, rateLimitStore, route, req, constants.ClientRateLimitKey, constants.AccountRateLimitKey); err != nil {
+			return nil, err
+		}
+
+		scope, ok := grpcScopes[info.FullMethod]
+		if !ok || !principal.HasScope(scope) {
+			utils.RequestLogger(ginCtx).Info(fmt.Sprintf("client %v is missing the %v scope", principal.ClientID, scope))
+			return nil, status.Error(codes.PermissionDenied, constants.Forbidden)
+		}
+
+		return handler(context.WithValue(spanCtx, gin.ContextKey, ginCtx), req)
+	}
+}
+
+// rateLimit checks the limits of the keys of the route for the call, the seconds to wait before a retry are sent in
+// the retry-after header of a call exceeding a limit
+func rateLimit(ctx context.Context, ginCtx *gin.Context, store ratelimit.Store, route string, req interface{}, keys ...string) error {
+	exceeded, retryAfter := middleware.TakeRateLimits(ginCtx, store, route, keys, func(key string) string {
+		return grpcRateLimitKey(ctx, ginCtx, req, key)
+	})
+	if !exceeded {
+		return nil
+	}
+	retryAfterHeader := metadata.Pairs(strings.ToLower(constants.RetryAfter), strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	if err := grpc.SetHeader(ctx, retryAfterHeader); err != nil {
+		utils.RequestLogger(ginCtx).Info("unable to set the retry-after header", zap.Error(err))
+	}
+	return service.GRPCError(&limitoffererror.CreditCardError{
+		Code:    http.StatusTooManyRequests,
+		Message: constants.TooManyRequests,
+		Trace:   ginCtx.Request.Header.Get(constants.TransactionID),
+	})
+}
+
+// grpcRateLimitKey returns the client, the ip of the peer or the account of the request the call is limited by,
+// the account is empty for the requests without one
+func grpcRateLimitKey(ctx context.Context, ginCtx *gin.Context, req interface{}, key string) string {
+	switch key {
+	case constants.ClientRateLimitKey:
+		if principal, ok := utils.GetPrincipal(ginCtx); ok {
+			return principal.ClientID + constants.ForwardSlash + principal.Subject
+		}
+	case constants.IPRateLimitKey:
+		if caller, ok := peer.FromContext(ctx); ok {
+			host, _, err := net.SplitHostPort(caller.Addr.String())
+			if err != nil {
+				return caller.Addr.String()
+			}
+			return host
+		}
+	case constants.AccountRateLimitKey:
+		if request, ok := req.(interface{ GetAccountId() string }); ok {
+			return request.GetAccountId()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/auth"
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/rpc/limitofferpb"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyAuthenticator authenticates the calls having the "secret" api key with the scopes
+type apiKeyAuthenticator []string
+
+func (scopes apiKeyAuthenticator) Authenticate(ctx *gin.Context) (*models.Principal, error) {
+	if ctx.Request.Header.Get(constants.APIKeyHeader) != "secret" {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &models.Principal{ClientID: "internal", Scopes: scopes}, nil
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	utils.InitLogClient()
+	interceptor := unaryInterceptor(apiKeyAuthenticator{constants.ScopeOffersRead}, ratelimit.NewMemoryStore())
+	info := &grpc.UnaryServerInfo{FullMethod: limitofferpb.LimitOfferService_GetLimitOffer_FullMethodName}
+	txid := "0b9c3a3e-5b5b-4f4e-9c55-5d7c2d6c1f0e"
+
+	var handled *gin.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled, _ = ctx.Value(gin.ContextKey).(*gin.Context)
+		return nil, nil
+	}
+	call := func(info *grpc.UnaryServerInfo, pairs ...string) error {
+		handled = nil
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+		_, err := interceptor(ctx, nil, info, handler)
+		return err
+	}
+
+	// case 1 : the caller is authenticated and the transaction id is passed along with the principal
+	assert.NoError(t, call(info, "x-api-key", "secret", constants.TransactionID, txid))
+	assert.NotNil(t, handled)
+	assert.Equal(t, txid, handled.Request.Header.Get(constants.TransactionID))
+	principal, ok := utils.GetPrincipal(handled)
+	assert.True(t, ok)
+	assert.Equal(t, "internal", principal.ClientID)
+
+	// case 2 : a transaction id is generated when missing
+	assert.NoError(t, call(info, "x-api-key", "secret"))
+	assert.Len(t, handled.Request.Header.Get(constants.TransactionID), 36)
+
+	// case 3 : invalid credentials
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(info, "x-api-key", "wrong")))
+	assert.Nil(t, handled)
+
+	// case 4 : missing scope of the method
+	decide := &grpc.UnaryServerInfo{FullMethod: limitofferpb.LimitOfferService_DecideLimitOffer_FullMethodName}
+	assert.Equal(t, codes.PermissionDenied, status.Code(call(decide, "x-api-key", "secret")))
+	assert.Nil(t, handled)
+
+	// case 5 : the trace of the caller goes on through the call
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	assert.NoError(t, call(info, "x-api-key", "secret", "traceparent", traceParent))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(handled.Request.Context()).TraceID().String())
+}
+
+func TestUnaryInterceptorRateLimit(t *testing.T) {
+	utils.InitLogClient()
+	config.SetConfig(config.GlobalConfig{RateLimit: config.RateLimit{Enabled: true, Limits: []config.RouteLimit{
+		{Route: constants.AllRoutes, Key: constants.IPRateLimitKey, Rate: 0.1, Burst: 2},
+		{Route: constants.CreateLimitOffer, Key: constants.AccountRateLimitKey, Rate: 0.1, Burst: 1},
+	}}})
+	t.Cleanup(func() { config.SetConfig(config.GlobalConfig{}) })
+
+	interceptor := unaryInterceptor(apiKeyAuthenticator{constants.ScopeOffersRead, constants.ScopeOffersWrite}, ratelimit.NewMemoryStore())
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	call := func(method string, req interface{}, apiKey string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", apiKey))
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 52114}})
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+	createLimitOffer := func(accountID string) error {
+		return call(limitofferpb.LimitOfferService_CreateLimitOffer_FullMethodName, &limitofferpb.CreateLimitOfferRequest{AccountId: accountID}, "secret")
+	}
+
+	// case 1 : the account of the request is limited by the limits of the http route of the method
+	assert.NoError(t, createLimitOffer("a1"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(createLimitOffer("a1")))
+	assert.NoError(t, createLimitOffer("a2"))
+
+	// case 2 : the ip of the caller is limited ahead of the authentication
+	getLimitOffer := limitofferpb.LimitOfferService_GetLimitOffer_FullMethodName
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(getLimitOffer, &limitofferpb.GetLimitOfferRequest{}, "wrong")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(getLimitOffer, &limitofferpb.GetLimitOfferRequest{}, "wrong")))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call(getLimitOffer, &limitofferpb.GetLimitOfferRequest{}, "secret")))
+}
+
+// blockingService answers GetLimitOffer once the call is cancelled
+type blockingService struct {
+	limitofferpb.UnimplementedLimitOfferServiceServer
+	called chan struct{}
+}
+
+func (s blockingService) GetLimitOffer(ctx context.Context, request *limitofferpb.GetLimitOfferRequest) (*limitofferpb.LimitOffer, error) {
+	close(s.called)
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func TestStopGRPC(t *testing.T) {
+	utils.InitLogClient()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	service := blockingService{called: make(chan struct{})}
+	limitofferpb.RegisterLimitOfferServiceServer(grpcServer, service)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	callErr := make(chan error, 1)
+	go func() {
+		_, err := limitofferpb.NewLimitOfferServiceClient(conn).GetLimitOffer(context.Background(), &limitofferpb.GetLimitOfferRequest{LimitOfferId: "f3a1"})
+		callErr <- err
+	}()
+	<-service.called
+
+	// the call in flight does not hold the stop past the deadline, it is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	stopGRPC(ctx, grpcServer)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Error(t, <-callErr)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/health"
+	"github.com/ankit/project/credit-card-offer-limit/internal/openapi"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+// gin path params, e.g. :account_id, are written {account_id} in the OpenAPI document
+var pathParam = regexp.MustCompile(`:([a-z_]+)`)
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(openapi.Spec(), &spec))
+
+	documented := []string{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	registered := []string{}
+	for _, route := range newRouter(apiKeyAuthenticator{}, ratelimit.NewMemoryStore(), health.NewProbe(nil, 0)).Routes() {
+		if route.Path == constants.ForwardSlash+constants.OpenAPIDocument || route.Path == constants.ForwardSlash+constants.APIDocs {
+			continue
+		}
+		registered = append(registered, route.Method+" "+pathParam.ReplaceAllString(route.Path, "{$1}"))
+	}
+
+	sort.Strings(documented)
+	sort.Strings(registered)
+	assert.Equal(t, registered, documented, "the routes registered in server.go and the paths of openapi.json differ")
+}
+
+func TestServeOpenAPIDocument(t *testing.T) {
+	router := newRouter(apiKeyAuthenticator{}, ratelimit.NewMemoryStore(), health.NewProbe(nil, 0))
+
+	// the document and the docs page are served without credentials
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, constants.ApplicationJSON, recorder.Header().Get(constants.ContentType))
+	assert.Equal(t, openapi.Spec(), recorder.Body.Bytes())
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "/openapi.json")
+}
@@ -2,7 +2,7 @@ package server
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,69 +10,229 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ankit/project/credit-card-offer-limit/internal/auth"
 	"github.com/ankit/project/credit-card-offer-limit/internal/config"
 	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/health"
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
 	"github.com/ankit/project/credit-card-offer-limit/internal/middleware"
+	"github.com/ankit/project/credit-card-offer-limit/internal/openapi"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
 	"github.com/ankit/project/credit-card-offer-limit/internal/service"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 // Registering the CreateAccount EndPoint
 func registerCreateAccountEndPoints(handler gin.IRoutes) {
-	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.CreateAccount}, constants.ForwardSlash), service.CreateAccount())
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.CreateAccount}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeAccountsWrite), service.CreateAccount())
 }
 
 // Registering the GetAccount EndPoint
 func registerGetAccountEndPoints(handler gin.IRoutes) {
-	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.GetAccount, constants.Colon + constants.AccountID}, constants.ForwardSlash), service.GetAccount())
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.GetAccount, constants.Colon + constants.AccountID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeAccountsRead), service.GetAccount())
 }
 
 // Registering the CreateLimitOffer EndPoint
 func registerCreateLimitOfferEndpoints(handler gin.IRoutes) {
-	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.CreateLimitOffer}, constants.ForwardSlash), service.CreateLimitOffer())
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.CreateLimitOffer}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersWrite), service.CreateLimitOffer())
 }
 
 // Registering the GetAccount EndPoint
 func registerListActiveLimitOffersEndpoints(handler gin.IRoutes) {
-	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ListActiveLimitOffers}, constants.ForwardSlash), service.ListActiveLimitOffers())
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ListActiveLimitOffers}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersRead), service.ListActiveLimitOffers())
 }
 
 // Registering the UpdateLimitOfferStatus EndPoint
 func registerUpdateLimitOfferStatusEndpoints(handler gin.IRoutes) {
-	handler.PATCH(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.UpdateLimitOfferStatus}, constants.ForwardSlash), service.UpdateLimitOfferStatus())
+	handler.PATCH(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.UpdateLimitOfferStatus}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersDecide), service.UpdateLimitOfferStatus())
 }
 
-func Start() {
+// Registering the bulk import EndPoints
+func registerImportEndpoints(handler gin.IRoutes) {
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ImportAccounts}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeAccountsWrite), service.ImportAccounts())
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ImportLimitOffers}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersWrite), service.ImportLimitOffers())
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ImportLimitOfferStatus}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersDecide), service.ImportLimitOfferStatuses())
+}
+
+// Registering the ExportLimitOffers EndPoint
+func registerExportLimitOffersEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ExportLimitOffers}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersRead), service.ExportLimitOffers())
+}
+
+// Registering the GetLimitOffer EndPoint
+func registerGetLimitOfferEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.GetLimitOffer, constants.Colon + constants.LimitOfferID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersRead), service.GetLimitOffer())
+}
+
+// Registering the ListLimitOffers EndPoint
+func registerListLimitOffersEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ListLimitOffers, constants.Colon + constants.AccountID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersRead), service.ListLimitOffers())
+}
+
+// Registering the CancelLimitOffer EndPoint
+func registerCancelLimitOfferEndpoints(handler gin.IRoutes) {
+	handler.PATCH(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.CancelLimitOffer, constants.Colon + constants.LimitOfferID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersWrite), service.CancelLimitOffer())
+}
+
+// Registering the RevertAccountLimit EndPoint
+func registerRevertAccountLimitEndpoints(handler gin.IRoutes) {
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.RevertAccountLimit, constants.Colon + constants.AccountID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeAccountsWrite), service.RevertAccountLimit())
+}
+
+// Registering the ApproveLimitOffer and DeclineLimitOffer EndPoints
+func registerReviewLimitOfferEndpoints(handler gin.IRoutes) {
+	handler.PATCH(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ApproveLimitOffer, constants.Colon + constants.LimitOfferID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersApprove), service.ApproveLimitOffer())
+	handler.PATCH(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.DeclineLimitOffer, constants.Colon + constants.LimitOfferID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersApprove), service.DeclineLimitOffer())
+}
+
+// Registering the ListAuditEvents EndPoint
+func registerListAuditEventsEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ListAuditEvents}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeAuditRead), service.ListAuditEvents())
+}
+
+// Registering the StreamLimitOfferEvents EndPoint
+func registerStreamLimitOfferEventsEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.StreamLimitOfferEvents, constants.Colon + constants.AccountID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeOffersRead), service.StreamLimitOfferEvents())
+}
+
+// Registering the webhook subscription EndPoints
+func registerWebhookEndpoints(handler gin.IRoutes) {
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.CreateWebhookSub}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeWebhooksManage), service.CreateWebhookSubscription())
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ListWebhookSubs}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeWebhooksManage), service.ListWebhookSubscriptions())
+	handler.GET(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.ListWebhookDeliveries, constants.Colon + constants.SubscriptionID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeWebhooksManage), service.ListWebhookDeliveries())
+	handler.POST(constants.ForwardSlash+strings.Join([]string{constants.ForwardSlash, constants.RedeliverWebhook, constants.Colon + constants.DeliveryID}, constants.ForwardSlash), middleware.RequireScopes(constants.ScopeWebhooksManage), service.RedeliverWebhookDelivery())
+}
+
+// Registering the OpenAPI document and the docs page, they do not need authentication
+func registerOpenAPIEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+constants.OpenAPIDocument, openapi.ServeSpec())
+	handler.GET(constants.ForwardSlash+constants.APIDocs, openapi.ServeDocs())
+}
+
+// Registering the prometheus metrics EndPoint, it does not need authentication
+func registerMetricsEndpoints(handler gin.IRoutes) {
+	handler.GET(constants.ForwardSlash+constants.Metrics, metrics.ServeMetrics())
+}
+
+// Registering the liveness and readiness EndPoints, they do not need authentication
+func registerHealthEndpoints(handler gin.IRoutes, probe *health.Probe) {
+	handler.GET(constants.ForwardSlash+constants.Healthz, probe.Live())
+	handler.GET(constants.ForwardSlash+constants.Readyz, probe.Ready())
+}
+
+// newRouter registers every endpoint of the http api, the requests of the /v1 routes are limited with the buckets of the store,
+// by ip ahead of the authentication so that the unauthenticated requests are limited too
+func newRouter(authenticator auth.Authenticator, rateLimitStore ratelimit.Store, probe *health.Probe) *gin.Engine {
 	plainHandler := gin.New()
+	// the ip of the client is read from X-Forwarded-For only when the request comes through a trusted proxy,
+	// the proxies are validated along with the config
+	if err := plainHandler.SetTrustedProxies(config.GetConfig().Server.TrustedProxies); err != nil {
+		utils.Logger.Fatal("invalid trusted proxies", zap.Error(err))
+	}
+	// the probes are registered ahead of the middlewares so that they are not logged, traced nor counted
+	registerHealthEndpoints(plainHandler, probe)
+	if config.GetConfig().Logging.AccessLog {
+		plainHandler.Use(middleware.AccessLog())
+	}
+	plainHandler.Use(tracing.Middleware(), metrics.Middleware())
+	registerOpenAPIEndpoints(plainHandler)
 
 	creditCardHandler := plainHandler.Group(constants.ForwardSlash + constants.Version).Use(gin.Recovery()).
-		Use(middleware.ValidateInputRequest())
+		Use(middleware.RateLimit(rateLimitStore, constants.IPRateLimitKey)).Use(middleware.Authenticate(authenticator)).
+		Use(middleware.RateLimit(rateLimitStore, constants.ClientRateLimitKey, constants.AccountRateLimitKey)).Use(middleware.ValidateInputRequest())
 	registerCreateAccountEndPoints(creditCardHandler)
 	registerGetAccountEndPoints(creditCardHandler)
 	registerCreateLimitOfferEndpoints(creditCardHandler)
 	registerListActiveLimitOffersEndpoints(creditCardHandler)
 	registerUpdateLimitOfferStatusEndpoints(creditCardHandler)
+	registerImportEndpoints(creditCardHandler)
+	registerExportLimitOffersEndpoints(creditCardHandler)
+	registerGetLimitOfferEndpoints(creditCardHandler)
+	registerListLimitOffersEndpoints(creditCardHandler)
+	registerCancelLimitOfferEndpoints(creditCardHandler)
+	registerRevertAccountLimitEndpoints(creditCardHandler)
+	registerReviewLimitOfferEndpoints(creditCardHandler)
+	registerListAuditEventsEndpoints(creditCardHandler)
+	registerWebhookEndpoints(creditCardHandler)
+	registerStreamLimitOfferEventsEndpoints(creditCardHandler)
+	return plainHandler
+}
 
+// Start serves the api until the process is interrupted, stopWorkers is called on the way out to stop
+// the background workers, which also ends the open event streams, before the server is shut down, and
+// flushSpans once the server is shut down, so that the spans of the last requests are exported too
+func Start(authenticator auth.Authenticator, rateLimitStore ratelimit.Store, probe *health.Probe, stopWorkers func(), flushSpans func()) {
 	cfg := config.GetConfig()
+	router := newRouter(authenticator, rateLimitStore, probe)
 	srv := &http.Server{
-		Handler:      plainHandler,
+		Handler:      router,
 		Addr:         cfg.Server.Address,
 		ReadTimeout:  time.Duration(time.Duration(cfg.Server.ReadTimeOut).Seconds()),
 		WriteTimeout: time.Duration(time.Duration(cfg.Server.WriteTimeOut).Seconds()),
 	}
 
+	// the metrics are served along with the api unless they have a listener of their own,
+	// which keeps them reachable by the scraper only
+	var metricsSrv *http.Server
+	if cfg.Server.MetricsAddress == "" {
+		registerMetricsEndpoints(router)
+	} else {
+		metricsHandler := gin.New()
+		registerMetricsEndpoints(metricsHandler)
+		metricsSrv = &http.Server{Handler: metricsHandler, Addr: cfg.Server.MetricsAddress, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			utils.Logger.Info(fmt.Sprintf("Starting Metrics Server on %v", cfg.Server.MetricsAddress))
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				utils.Logger.Fatal("metrics server stopped", zap.Error(err))
+			}
+		}()
+	}
+
+	// the certificate is served from the files last read, which are watched as long as the process runs
+	if cfg.Server.TLS.Enabled {
+		tlsConfig, reloader, err := newTLSConfig(cfg.Server.TLS)
+		if err != nil {
+			utils.Logger.Fatal("unable to initialize tls", zap.Error(err))
+		}
+		srv.TLSConfig = tlsConfig
+		if cfg.Server.TLS.ReloadInterval > 0 {
+			go reloader.watch(context.Background(), time.Duration(cfg.Server.TLS.ReloadInterval)*time.Second)
+		}
+	}
+
 	// Start Server
 	go func() {
-		log.Println("Starting Server")
-		if err := srv.ListenAndServe(); err != nil {
-			log.Fatal(err)
+		utils.Logger.Info(fmt.Sprintf("Starting Server on %v", cfg.Server.Address), zap.Bool("tls", cfg.Server.TLS.Enabled))
+		// the server is closed by the shutdown, which waits for the open requests
+		var err error
+		if cfg.Server.TLS.Enabled {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			utils.Logger.Fatal("server stopped", zap.Error(err))
 		}
 	}()
 
-	waitForShutdown(srv)
+	// Start the gRPC Server on its own port
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCAddress != "" {
+		var err error
+		grpcServer, err = startGRPC(cfg.Server.GRPCAddress, authenticator, rateLimitStore)
+		if err != nil {
+			utils.Logger.Fatal("unable to start the gRPC server", zap.Error(err))
+		}
+	}
+
+	waitForShutdown(srv, metricsSrv, grpcServer, probe, stopWorkers, flushSpans)
 }
 
-func waitForShutdown(srv *http.Server) {
+func waitForShutdown(srv *http.Server, metricsSrv *http.Server, grpcServer *grpc.Server, probe *health.Probe, stopWorkers func(), flushSpans func()) {
 
 	/*
 		if somewhere you are listening for output from a channel but in the meanwhile that channel not being given any input,
@@ -92,6 +252,12 @@ func waitForShutdown(srv *http.Server) {
 	// Block until we receive our signal.
 	<-interruptChan
 
+	// the readiness probe fails from now on, the requests routed to the server meanwhile are still served
+	// until the orchestrator notices it
+	probe.Drain()
+	utils.Logger.Info("Draining")
+	time.Sleep(time.Duration(config.GetConfig().Server.DrainDelay) * time.Second)
+
 	// Create a deadline to wait for.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 
@@ -108,8 +274,20 @@ func waitForShutdown(srv *http.Server) {
 		inside the Shutdown it check if the timer context Done channel is closed and will not run indefinitely.
 	*/
 
+	// the event streams never end by themselves, they are closed along with the workers
+	// so that the shutdown does not wait for them until the deadline
+	stopWorkers()
 	srv.Shutdown(ctx)
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
+	}
+	if metricsSrv != nil {
+		metricsSrv.Shutdown(ctx)
+	}
+	// the requests and the calls served until now have ended their spans
+	flushSpans()
 
-	log.Println("Shutting down")
+	utils.Logger.Info("Shutting down")
+	utils.Logger.Sync()
 	os.Exit(0)
 }
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/health"
+	"github.com/ankit/project/credit-card-offer-limit/internal/ratelimit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterRateLimitsTheIP(t *testing.T) {
+	utils.InitLogClient()
+	cfg := config.GlobalConfig{RateLimit: config.RateLimit{Enabled: true, Limits: []config.RouteLimit{
+		{Route: constants.AllRoutes, Key: constants.IPRateLimitKey, Rate: 0.1, Burst: 1},
+	}}}
+	config.SetConfig(cfg)
+	t.Cleanup(func() { config.SetConfig(config.GlobalConfig{}) })
+
+	serve := func(router http.Handler, forwardedFor string) int {
+		request := httptest.NewRequest(http.MethodGet, "/v1/get_account/2b4e1e64-624f-4a4e-9911-e0b13f526e10", nil)
+		request.RemoteAddr = "192.0.2.1:52114"
+		request.Header.Set(constants.APIKeyHeader, "wrong")
+		if forwardedFor != "" {
+			request.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// case 1 : the requests are limited ahead of the authentication, and X-Forwarded-For is not trusted by default
+	router := newRouter(apiKeyAuthenticator{}, ratelimit.NewMemoryStore(), health.NewProbe(nil, 0))
+	assert.Equal(t, http.StatusUnauthorized, serve(router, ""))
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, "203.0.113.9"))
+
+	// case 2 : the ip forwarded by a trusted proxy is limited
+	cfg.Server.TrustedProxies = []string{"192.0.2.0/24"}
+	config.SetConfig(cfg)
+	router = newRouter(apiKeyAuthenticator{}, ratelimit.NewMemoryStore(), health.NewProbe(nil, 0))
+	assert.Equal(t, http.StatusUnauthorized, serve(router, "203.0.113.9"))
+	assert.Equal(t, http.StatusUnauthorized, serve(router, "203.0.113.10"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, "203.0.113.9"))
+}
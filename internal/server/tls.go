@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"go.uber.org/zap"
+)
+
+var tlsVersions = map[string]uint16{"1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	constants.NoClientAuth:       tls.NoClientCert,
+	constants.OptionalClientAuth: tls.VerifyClientCertIfGiven,
+	constants.RequireClientAuth:  tls.RequireAndVerifyClientCert,
+}
+
+// certificateReloader holds the certificate of the server and the CAs of the clients read from the files,
+// they are read again once the modification time of one of the files changes
+type certificateReloader struct {
+	cfg config.TLS
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// newTLSConfig returns the tls config of the http api and the reloader of its files
+func newTLSConfig(cfg config.TLS) (*tls.Config, *certificateReloader, error) {
+	reloader := &certificateReloader{cfg: cfg}
+	if err := reloader.load(); err != nil {
+		return nil, nil, err
+	}
+
+	var cipherSuites []uint16
+	for _, name := range cfg.CipherSuites {
+		for _, suite := range tls.CipherSuites() {
+			if suite.Name == name {
+				cipherSuites = append(cipherSuites, suite.ID)
+			}
+		}
+	}
+	// the protocols are set here since the handshake config below is cloned from this one and not from
+	// the one http.Server adds h2 to
+	tlsConfig := &tls.Config{
+		MinVersion:   tlsVersions[cfg.MinVersion],
+		CipherSuites: cipherSuites,
+		ClientAuth:   clientAuthTypes[cfg.ClientAuth],
+		NextProtos:   []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			reloader.mu.RLock()
+			defer reloader.mu.RUnlock()
+			return reloader.certificate, nil
+		},
+	}
+	// every handshake verifies the client against the CAs last read
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		handshakeConfig := tlsConfig.Clone()
+		handshakeConfig.GetConfigForClient = nil
+		reloader.mu.RLock()
+		handshakeConfig.ClientCAs = reloader.clientCAs
+		reloader.mu.RUnlock()
+		return handshakeConfig, nil
+	}
+	return tlsConfig, reloader, nil
+}
+
+// files returns the files the tls is read from
+func (r *certificateReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// load reads the files, the certificate and the CAs in use are kept when one of them is invalid
+func (r *certificateReloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("unable to read %v : %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load the tls certificate : %w", err)
+	}
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		bundle, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read %v : %w", r.cfg.ClientCAFile, err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(bundle) {
+			return errors.New("client_ca_file holds no pem certificate")
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.certificate, r.clientCAs, r.modTimes = &certificate, clientCAs, modTimes
+	return nil
+}
+
+// changed tells whether one of the files was modified since it was last read
+func (r *certificateReloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// watch reads the files again every interval they changed until ctx is cancelled
+func (r *certificateReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.load(); err != nil {
+				// the files may be replaced one at a time, they are read again at the next tick
+				utils.Logger.Error("unable to reload the tls certificate, the previous one is still used", zap.Error(err))
+				continue
+			}
+			utils.Logger.Info("reloaded the tls certificate")
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+	keyPEM      []byte
+}
+
+// issue creates a certificate for the common name signed by the parent, or self-signed when parent is nil
+func issue(t *testing.T, commonName string, parent *testCertificate, usage x509.ExtKeyUsage) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"bank"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+	} else {
+		signer, signerKey = parent.certificate, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCertificate) keyPair(t *testing.T) tls.Certificate {
+	pair, err := tls.X509KeyPair(c.pem, c.keyPEM)
+	assert.NoError(t, err)
+	return pair
+}
+
+func writeCertificate(t *testing.T, cfg config.TLS, certificate *testCertificate, modTime time.Time) {
+	assert.NoError(t, os.WriteFile(cfg.CertFile, certificate.pem, 0o600))
+	assert.NoError(t, os.WriteFile(cfg.KeyFile, certificate.keyPEM, 0o600))
+	assert.NoError(t, os.Chtimes(cfg.CertFile, modTime, modTime))
+	assert.NoError(t, os.Chtimes(cfg.KeyFile, modTime, modTime))
+}
+
+func TestMutualTLS(t *testing.T) {
+	utils.InitLogClient()
+	dir := t.TempDir()
+	ca := issue(t, "offers-ca", nil, x509.ExtKeyUsageAny)
+	cfg := config.TLS{
+		Enabled:      true,
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		MinVersion:   "1.2",
+		ClientAuth:   constants.RequireClientAuth,
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+	}
+	assert.NoError(t, os.WriteFile(cfg.ClientCAFile, ca.pem, 0o600))
+	writeCertificate(t, cfg, issue(t, "offers-api", ca, x509.ExtKeyUsageServerAuth), time.Now().Add(-time.Minute))
+
+	tlsConfig, reloader, err := newTLSConfig(cfg)
+	assert.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := &http.Server{TLSConfig: tlsConfig, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.TLS.VerifiedChains[0][0].Subject.CommonName)
+	})}
+	go srv.ServeTLS(listener, "", "")
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.certificate)
+	client := func(certificates ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certificates},
+			ForceAttemptHTTP2: true,
+		}}
+	}
+	url := "https://" + listener.Addr().String()
+
+	// the client without a certificate is rejected by the handshake
+	_, err = client().Get(url)
+	assert.Error(t, err)
+
+	// the subject of the verified certificate reaches the handler
+	response, err := client(issue(t, "offers-batch", ca, x509.ExtKeyUsageClientAuth).keyPair(t)).Get(url)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	assert.Equal(t, "offers-batch", string(body))
+	assert.Equal(t, "offers-api", response.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// http/2 is negotiated by the handshake config
+	assert.Equal(t, 2, response.ProtoMajor)
+	assert.Equal(t, "h2", response.TLS.NegotiatedProtocol)
+
+	// the renewed certificate is served once the files changed, without a restart
+	assert.False(t, reloader.changed())
+	writeCertificate(t, cfg, issue(t, "offers-api-renewed", ca, x509.ExtKeyUsageServerAuth), time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.watch(ctx, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return !reloader.changed() }, time.Second, 10*time.Millisecond)
+
+	renewedClient := client(issue(t, "offers-batch", ca, x509.ExtKeyUsageClientAuth).keyPair(t))
+	response, err = renewedClient.Get(url)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, "offers-api-renewed", response.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// an invalid file does not replace the certificate in use
+	cancel()
+	assert.NoError(t, os.WriteFile(cfg.KeyFile, []byte("not a key"), 0o600))
+	assert.Error(t, reloader.load())
+	certificate, err := tlsConfig.GetCertificate(nil)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "offers-api-renewed", leaf.Subject.CommonName)
+}
@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
 	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
 	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
 	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -17,11 +19,10 @@ import (
 // This function is responsible for account creation
 func CreateAccount() func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
-		txid := ctx.Request.Header.Get(constants.TransactionID)
-		utils.Logger.Info(fmt.Sprintf("received request for account creation, txid : %v", txid))
+		utils.RequestLogger(ctx).Info("received request for account creation")
 		var accountInfo models.Account
 		if err := ctx.ShouldBindBodyWith(&accountInfo, binding.JSON); err == nil {
-			utils.Logger.Info(fmt.Sprintf("user request for account creation is unmarshalled successfully, txid : %v", txid))
+			utils.RequestLogger(ctx).Info("user request for account creation is unmarshalled successfully")
 
 			createdAccount, err := creditCardLimitOfferClient.createAccount(ctx, accountInfo)
 			if err != nil {
@@ -42,11 +43,17 @@ func CreateAccount() func(ctx *gin.Context) {
 }
 
 func (service *CreditCardLimitOfferService) createAccount(ctx *gin.Context, accountInfo models.Account) (models.Account, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.createAccount")()
+
 	txid := ctx.Request.Header.Get(constants.TransactionID)
 
+	if err := authorize(ctx, operationCreateAccount, nil); err != nil {
+		return models.Account{}, err
+	}
+
 	// check if per transaction limit is greater than account limit
 	if *accountInfo.PerTransactionLimit > *accountInfo.AccountLimit {
-		utils.Logger.Info(fmt.Sprintf("per transaction limit can not be greater than account limit, txid : %v", txid))
+		utils.RequestLogger(ctx).Info("per transaction limit can not be greater than account limit")
 		return models.Account{}, &limitoffererror.CreditCardError{
 			Code:    http.StatusBadRequest,
 			Message: "per transaction limit can not be greater than account limit",
@@ -54,21 +61,26 @@ func (service *CreditCardLimitOfferService) createAccount(ctx *gin.Context, acco
 		}
 	}
 
-	// generate the accountID and customerID from uuid package and set in the the accountInfo
-	accountID := uuid.New().String()
-	customerID := uuid.New().String()
-	accountInfo.AccountID = accountID
-	accountInfo.CustomerID = customerID
+	// generate the accountID and, unless the account is opened for an existing customer, the customerID
+	// from uuid package and set in the the accountInfo
+	accountInfo.AccountID = uuid.New().String()
+	if accountInfo.CustomerID == constants.EmptyString {
+		accountInfo.CustomerID = uuid.New().String()
+	}
+
+	if accountInfo.Locale == constants.EmptyString {
+		accountInfo.Locale = config.GetConfig().Notifications.DefaultLocale
+	}
 
 	// set the current time as accountCreationTime and AccountLimitUpdateTime in the accountInfo
 	accountCreationTime := time.Now().UTC()
 	accountInfo.AccountLimitUpdateTime = accountCreationTime
 	accountInfo.PerTransactionLimitUpdateTime = accountCreationTime
 
-	utils.Logger.Info(fmt.Sprintf("calling db layer for account creation, txid : %v", txid))
+	utils.RequestLogger(ctx).Info("calling db layer for account creation")
 	err := service.repo.CreateAccount(ctx, accountInfo)
 	if err != nil {
-		utils.Logger.Info(fmt.Sprintf("received error from db layer during account creation, txid : %v", txid))
+		utils.RequestLogger(ctx).Info("received error from db layer during account creation")
 		return models.Account{}, err
 	}
 
@@ -78,10 +90,9 @@ func (service *CreditCardLimitOfferService) createAccount(ctx *gin.Context, acco
 // This function is responsible to get a specific account based on accountid
 func GetAccount() func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
-		txid := ctx.Request.Header.Get(constants.TransactionID)
 		accountID := ctx.Param(constants.AccountID)
-		utils.Logger.Info(fmt.Sprintf("request received for get %v account, txid : %v", accountID, txid))
-		utils.Logger.Info(fmt.Sprintf("calling service layer for getting %v accountID, txid : %v", accountID, txid))
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("request received for get %v account", accountID))
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("calling service layer for getting %v accountID", accountID))
 		fetchedAccount, err := creditCardLimitOfferClient.getAccount(ctx, accountID)
 		if err != nil {
 			utils.RespondWithError(ctx, err.Code, err.Message)
@@ -94,14 +105,53 @@ func GetAccount() func(ctx *gin.Context) {
 }
 
 func (service *CreditCardLimitOfferService) getAccount(ctx *gin.Context, accountID string) (models.Account, *limitoffererror.CreditCardError) {
-	txid := ctx.Request.Header.Get(constants.TransactionID)
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.getAccount")()
 
-	utils.Logger.Info(fmt.Sprintf("calling db layer for getting %v account, txid : %v", accountID, txid))
-	fetchedAccount, err := service.repo.GetAccount(ctx, accountID)
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer for getting %v account", accountID))
+	fetchedAccount, err := service.authorizeAccount(ctx, operationViewAccount, accountID)
 	if err != nil {
-		utils.Logger.Info(fmt.Sprintf("received error from db layer during getting %v account, txid : %v", accountID, txid))
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer during getting %v account", accountID))
 		return models.Account{}, err
 	}
 
 	return fetchedAccount, nil
 }
+
+// This function is responsible to revert a limit of an account to its previous value
+func RevertAccountLimit() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		accountID := ctx.Param(constants.AccountID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to revert the limit of %v account", accountID))
+		var revertAccountLimit models.RevertAccountLimit
+		if err := ctx.ShouldBindBodyWith(&revertAccountLimit, binding.JSON); err == nil {
+			revertAccountLimit.AccountID = accountID
+
+			revertedAccount, err := creditCardLimitOfferClient.revertAccountLimit(ctx, revertAccountLimit)
+			if err != nil {
+				utils.RespondWithError(ctx, err.Code, err.Message)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, revertedAccount)
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Unable to marshal the request body": err.Error()})
+		}
+	}
+}
+
+func (service *CreditCardLimitOfferService) revertAccountLimit(ctx *gin.Context, revertAccountLimit models.RevertAccountLimit) (models.Account, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.revertAccountLimit")()
+
+	if err := authorize(ctx, operationRevertAccountLimit, nil); err != nil {
+		return models.Account{}, err
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to revert the %v of %v account", revertAccountLimit.LimitType, revertAccountLimit.AccountID))
+	revertedAccount, err := service.repo.RevertAccountLimit(ctx, revertAccountLimit)
+	if err != nil {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while reverting the limit of %v account", revertAccountLimit.AccountID))
+		return models.Account{}, err
+	}
+
+	return revertedAccount, nil
+}
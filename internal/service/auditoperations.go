@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/audit"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// This function is responsible to list the audit events page by page
+func ListAuditEvents() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request to list audit events")
+		var filter models.AuditEventFilter
+		if err := ctx.ShouldBindQuery(&filter); err == nil {
+			auditEventPage, err := creditCardLimitOfferClient.listAuditEvents(ctx, filter)
+			if err != nil {
+				utils.RespondWithError(ctx, err.Code, err.Message)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, auditEventPage)
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Unable to bind the query params": err.Error()})
+		}
+	}
+}
+
+func (service *CreditCardLimitOfferService) listAuditEvents(ctx *gin.Context, filter models.AuditEventFilter) (models.AuditEventPage, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.listAuditEvents")()
+
+	if err := authorize(ctx, operationViewAuditLog, nil); err != nil {
+		return models.AuditEventPage{}, err
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = constants.DefaultPageSize
+	}
+
+	utils.RequestLogger(ctx).Info("calling db layer to list audit events")
+	auditEventPage, err := service.repo.ListAuditEvents(ctx, filter)
+	if err != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while listing audit events")
+		return models.AuditEventPage{}, err
+	}
+
+	return auditEventPage, nil
+}
+
+// VerifyAuditLog walks the whole audit log and checks its hash chain. It returns the number of events which
+// were verified and, when the chain is broken, the error telling which event breaks it.
+func (service *CreditCardLimitOfferService) VerifyAuditLog(ctx *gin.Context) (int, error) {
+	if err := authorize(ctx, operationViewAuditLog, nil); err != nil {
+		return 0, err
+	}
+
+	var verifier audit.Verifier
+	var chainErr error
+	err := service.repo.StreamAuditEvents(ctx, func(event models.AuditEvent) error {
+		// the first broken link is reported, the events after it can not be trusted anyway
+		if chainErr == nil {
+			chainErr = verifier.Verify(event)
+		}
+		return nil
+	})
+	if err != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while reading the audit log")
+		return verifier.Verified(), err
+	}
+	if chainErr != nil {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("audit log verification failed after %v events", verifier.Verified()), zap.Error(chainErr))
+		return verifier.Verified(), chainErr
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("verified %v audit events", verifier.Verified()))
+	return verifier.Verified(), nil
+}
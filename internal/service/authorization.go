@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// operations which are authorized by role, the names are used in the error message
+const (
+	operationCreateAccount      = "create accounts"
+	operationViewAccount        = "view the account"
+	operationRevertAccountLimit = "revert account limits"
+	operationCreateLimitOffer   = "create limit offers"
+	operationCancelLimitOffer   = "cancel limit offers"
+	operationReviewLimitOffer   = "review limit offers"
+	operationViewLimitOffers    = "view the limit offers"
+	operationDecideLimitOffer   = "decide the limit offer"
+	operationImportDecisions    = "import the limit offer decisions"
+	operationExportLimitOffers  = "export limit offers"
+	operationViewAuditLog       = "view the audit log"
+	operationManageWebhooks     = "manage webhook subscriptions"
+)
+
+// permittedRoles lists the roles allowed to perform every operation, a CUSTOMER is only allowed
+// to perform it on the accounts having the same customer id as the principal.
+var permittedRoles = map[string][]string{
+	operationCreateAccount:      {constants.RoleAdmin},
+	operationViewAccount:        {constants.RoleCustomer, constants.RoleRiskOfficer, constants.RoleAdmin},
+	operationRevertAccountLimit: {constants.RoleAdmin},
+	operationCreateLimitOffer:   {constants.RoleRiskOfficer},
+	operationCancelLimitOffer:   {constants.RoleRiskOfficer},
+	operationReviewLimitOffer:   {constants.RoleRiskOfficer, constants.RoleAdmin},
+	operationViewLimitOffers:    {constants.RoleCustomer, constants.RoleRiskOfficer, constants.RoleAdmin},
+	operationDecideLimitOffer:   {constants.RoleCustomer},
+	operationImportDecisions:    {constants.RoleCustomer, constants.RoleAdmin},
+	operationExportLimitOffers:  {constants.RoleRiskOfficer, constants.RoleAdmin},
+	operationViewAuditLog:       {constants.RoleAdmin},
+	operationManageWebhooks:     {constants.RoleAdmin},
+}
+
+// authorize makes sure the principal of the request may perform the operation, account is the account the
+// operation is performed on and is only needed for the operations which a CUSTOMER may perform.
+func authorize(ctx *gin.Context, operation string, account *models.Account) *limitoffererror.CreditCardError {
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	forbidden := func(message string) *limitoffererror.CreditCardError {
+		return &limitoffererror.CreditCardError{Code: http.StatusForbidden, Message: message, Trace: txid}
+	}
+
+	principal, ok := utils.GetPrincipal(ctx)
+	if !ok {
+		utils.RequestLogger(ctx).Error(fmt.Sprintf("no principal found to %v", operation))
+		return forbidden(fmt.Sprintf(constants.RoleNotPermitted, operation))
+	}
+
+	if isTrusted(principal) {
+		return nil
+	}
+
+	customerPermitted := false
+	for _, role := range permittedRoles[operation] {
+		if role == constants.RoleCustomer {
+			customerPermitted = true
+			continue
+		}
+		if principal.HasRole(role) {
+			return nil
+		}
+	}
+
+	if customerPermitted && principal.HasRole(constants.RoleCustomer) {
+		if account != nil && principal.CustomerID != constants.EmptyString && principal.CustomerID == account.CustomerID {
+			return nil
+		}
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("customer is not allowed to %v of another customer", operation), zap.String("customer_id", principal.CustomerID))
+		return forbidden(constants.AccountNotOwned)
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("client %v with roles %v is not allowed to %v", principal.ClientID, principal.Roles, operation))
+	return forbidden(fmt.Sprintf(constants.RoleNotPermitted, operation))
+}
+
+// authorizeAccount fetches the account the operation is performed on and makes sure the principal of the request may perform it
+func (service *CreditCardLimitOfferService) authorizeAccount(ctx *gin.Context, operation string, accountID string) (models.Account, *limitoffererror.CreditCardError) {
+	utils.SetLogAccountID(ctx, accountID)
+	account, err := service.repo.GetAccount(ctx, accountID)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if err := authorize(ctx, operation, &account); err != nil {
+		return models.Account{}, err
+	}
+	return account, nil
+}
+
+// isTrusted tells whether the principal is the system principal of the background work, it is trusted with every
+// operation on every account. The anonymous principal of a deployment without authentication goes through the
+// checks of its roles as any other principal.
+func isTrusted(principal models.Principal) bool {
+	return principal.AuthMethod == constants.AuthMethodSystem
+}
+
+// principalName names the principal in created_by and reviewed_by, the subjects are only unique within their client
+func principalName(principal models.Principal) string {
+	return principal.ClientID + constants.ForwardSlash + principal.Subject
+}
+
+// seesUnapprovedOffers tells whether the principal of the request is a back-office user, customers
+// only see the offers once they were approved
+func seesUnapprovedOffers(ctx *gin.Context) bool {
+	principal, ok := utils.GetPrincipal(ctx)
+	return ok && (isTrusted(principal) || principal.HasRole(constants.RoleRiskOfficer) || principal.HasRole(constants.RoleAdmin))
+}
+
+// isVisible tells whether the offer may be shown to the principal of the request
+func isVisible(ctx *gin.Context, limitOffer models.LimitOffer) bool {
+	return seesUnapprovedOffers(ctx) || (limitOffer.Status != models.AwaitingApproval && limitOffer.Status != models.Declined)
+}
+
+// customerVisibleStatuses narrows down the requested statuses to the ones a customer may see
+func customerVisibleStatuses(requested []models.OfferStatus) []models.OfferStatus {
+	if len(requested) == 0 {
+		return []models.OfferStatus{models.Pending, models.Accepted, models.Rejected, models.Superseded, models.Cancelled, models.Expired}
+	}
+	visible := []models.OfferStatus{}
+	for _, status := range requested {
+		if status != models.AwaitingApproval && status != models.Declined {
+			visible = append(visible, status)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func principalContext(principal *models.Principal) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if principal != nil {
+		ctx.Set(constants.Principal, *principal)
+	}
+	return ctx
+}
+
+func TestAuthorize(t *testing.T) {
+	utils.InitLogClient()
+	account := &models.Account{AccountID: "account-1", CustomerID: "customer-1"}
+	customer := &models.Principal{ClientID: "mobile", CustomerID: "customer-1", Roles: []string{constants.RoleCustomer}}
+	otherCustomer := &models.Principal{ClientID: "mobile", CustomerID: "customer-2", Roles: []string{constants.RoleCustomer}}
+	riskOfficer := &models.Principal{ClientID: "risk-console", Roles: []string{constants.RoleRiskOfficer}}
+	admin := &models.Principal{ClientID: "back-office", Roles: []string{constants.RoleAdmin}}
+
+	// case 1 : customer acting on own account
+	assert.Nil(t, authorize(principalContext(customer), operationViewAccount, account))
+	assert.Nil(t, authorize(principalContext(customer), operationDecideLimitOffer, account))
+
+	// case 2 : customer acting on the account of another customer
+	err := authorize(principalContext(otherCustomer), operationDecideLimitOffer, account)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	assert.Equal(t, constants.AccountNotOwned, err.Message)
+
+	// case 3 : customer performing a back-office operation
+	err = authorize(principalContext(customer), operationCreateLimitOffer, nil)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+
+	// case 4 : back-office roles are not bound to the customer
+	assert.Nil(t, authorize(principalContext(riskOfficer), operationCreateLimitOffer, nil))
+	assert.Nil(t, authorize(principalContext(riskOfficer), operationCancelLimitOffer, nil))
+	assert.Nil(t, authorize(principalContext(riskOfficer), operationViewLimitOffers, account))
+	assert.Nil(t, authorize(principalContext(admin), operationRevertAccountLimit, nil))
+
+	// case 5 : roles are not interchangeable
+	assert.NotNil(t, authorize(principalContext(riskOfficer), operationRevertAccountLimit, nil))
+	assert.NotNil(t, authorize(principalContext(admin), operationCreateLimitOffer, nil))
+	assert.NotNil(t, authorize(principalContext(riskOfficer), operationDecideLimitOffer, account))
+
+	// case 6 : customer principal without a customer id
+	assert.NotNil(t, authorize(principalContext(&models.Principal{Roles: []string{constants.RoleCustomer}}), operationViewAccount, &models.Account{}))
+
+	// case 7 : no principal
+	assert.NotNil(t, authorize(principalContext(nil), operationViewAccount, account))
+
+	// case 8 : background work runs as the system principal
+	assert.Nil(t, authorize(utils.NewBackgroundContext(), operationCreateAccount, nil))
+	assert.Nil(t, authorize(utils.NewBackgroundContext(), operationDecideLimitOffer, account))
+
+	// case 9 : the anonymous principal is checked by its roles, it is no customer
+	anonymous := &models.Principal{ClientID: constants.AnonymousClientID, AuthMethod: constants.AuthMethodAnonymous,
+		Roles: []string{constants.RoleCustomer, constants.RoleRiskOfficer, constants.RoleAdmin}}
+	assert.Nil(t, authorize(principalContext(anonymous), operationCreateAccount, nil))
+	err = authorize(principalContext(anonymous), operationDecideLimitOffer, account)
+	assert.Equal(t, constants.AccountNotOwned, err.Message)
+
+	// case 10 : the back office imports the decisions, customers import their own
+	assert.Nil(t, authorize(principalContext(admin), operationImportDecisions, account))
+	assert.Nil(t, authorize(principalContext(customer), operationImportDecisions, account))
+	assert.NotNil(t, authorize(principalContext(otherCustomer), operationImportDecisions, account))
+	assert.NotNil(t, authorize(principalContext(riskOfficer), operationImportDecisions, account))
+}
+
+func TestCustomerVisibleStatuses(t *testing.T) {
+	// case 1 : no status requested, every approved status is listed
+	visible := customerVisibleStatuses(nil)
+	assert.NotContains(t, visible, models.AwaitingApproval)
+	assert.NotContains(t, visible, models.Declined)
+	assert.Contains(t, visible, models.Pending)
+
+	// case 2 : unapproved statuses are dropped from the requested ones
+	assert.Equal(t, []models.OfferStatus{models.Accepted}, customerVisibleStatuses([]models.OfferStatus{models.AwaitingApproval, models.Accepted}))
+	assert.Empty(t, customerVisibleStatuses([]models.OfferStatus{models.Declined}))
+
+	// case 3 : visibility of a single offer
+	customer := &models.Principal{CustomerID: "customer-1", AuthMethod: constants.AuthMethodJWT, Roles: []string{constants.RoleCustomer}}
+	riskOfficer := &models.Principal{AuthMethod: constants.AuthMethodJWT, Roles: []string{constants.RoleRiskOfficer}}
+	assert.False(t, isVisible(principalContext(customer), models.LimitOffer{Status: models.AwaitingApproval}))
+	assert.True(t, isVisible(principalContext(customer), models.LimitOffer{Status: models.Pending}))
+	assert.True(t, isVisible(principalContext(riskOfficer), models.LimitOffer{Status: models.AwaitingApproval}))
+}
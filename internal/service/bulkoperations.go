@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/bulk"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/middleware"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// number of exported offers after which the csv is flushed to the client
+const exportFlushInterval = 100
+
+// This function is responsible for bulk account creation from a csv file
+func ImportAccounts() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request for bulk account creation")
+		handleImport(ctx, creditCardLimitOfferClient.ImportAccountsCSV)
+	}
+}
+
+// This function is responsible for bulk limit offer creation from a csv file
+func ImportLimitOffers() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request for bulk limit offer creation")
+		handleImport(ctx, creditCardLimitOfferClient.ImportLimitOffersCSV)
+	}
+}
+
+// This function is responsible for bulk limit offer status updates from a csv file
+func ImportLimitOfferStatuses() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request for bulk limit offer status update")
+		handleImport(ctx, creditCardLimitOfferClient.ImportLimitOfferStatusesCSV)
+	}
+}
+
+// This function is responsible to stream the limit offers as csv
+func ExportLimitOffers() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request to export limit offers")
+		var filter models.LimitOfferFilter
+		if err := ctx.ShouldBindQuery(&filter); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Unable to bind the query params": err.Error()})
+			return
+		}
+
+		ctx.Header(constants.ContentType, constants.TextCSV)
+		ctx.Header(constants.ContentDisposition, `attachment; filename="limit_offers.csv"`)
+		err := creditCardLimitOfferClient.ExportLimitOffersCSV(ctx, filter, ctx.Writer)
+		if err != nil {
+			// once the streaming has started the status code can not be changed anymore
+			if ctx.Writer.Written() {
+				utils.RequestLogger(ctx).Error("export of limit offers failed after streaming started")
+				return
+			}
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+	}
+}
+
+// handleImport reads the csv from the request body, runs the import and responds with the report
+// as csv when the client accepts text/csv and as json otherwise.
+func handleImport(ctx *gin.Context, importCSV func(*gin.Context, io.Reader) (models.BulkImportReport, *limitoffererror.CreditCardError)) {
+	body, err := csvBody(ctx)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("unable to read the csv request body", zap.Error(err))
+		utils.RespondWithError(ctx, http.StatusBadRequest, constants.InvalidCSVBody)
+		return
+	}
+	defer body.Close()
+
+	report, creditCardErr := importCSV(ctx, body)
+	if creditCardErr != nil {
+		utils.RespondWithError(ctx, creditCardErr.Code, creditCardErr.Message)
+		return
+	}
+
+	if strings.Contains(ctx.GetHeader(constants.Accept), constants.TextCSV) {
+		ctx.Header(constants.ContentType, constants.TextCSV)
+		ctx.Status(http.StatusOK)
+		if err := bulk.WriteReport(ctx.Writer, report); err != nil {
+			utils.RequestLogger(ctx).Error("unable to write the import report", zap.Error(err))
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// csvBody returns the uploaded csv, either sent as a multipart "file" field or as the raw request body
+func csvBody(ctx *gin.Context) (io.ReadCloser, error) {
+	if strings.HasPrefix(ctx.ContentType(), gin.MIMEMultipartPOSTForm) {
+		fileHeader, err := ctx.FormFile(constants.CSVFormField)
+		if err != nil {
+			return nil, err
+		}
+		return fileHeader.Open()
+	}
+	return ctx.Request.Body, nil
+}
+
+func addBulkRowResult(report *models.BulkImportReport, row int, id string, rowErr string) {
+	report.Total++
+	result := models.BulkRowResult{Row: row, ID: id, Status: models.RowSucceeded}
+	if rowErr != constants.EmptyString {
+		result.Status = models.RowFailed
+		result.Error = rowErr
+		report.Failed++
+	} else {
+		report.Succeeded++
+	}
+	report.Results = append(report.Results, result)
+}
+
+func invalidCSVError(txid string, err error) *limitoffererror.CreditCardError {
+	return &limitoffererror.CreditCardError{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("%v : %v", constants.InvalidCSVBody, err),
+		Trace:   txid,
+	}
+}
+
+// ImportAccountsCSV creates an account for every row of the csv, rows are validated with the same
+// rules as the create account endpoint and a failing row does not stop the remaining rows.
+func (service *CreditCardLimitOfferService) ImportAccountsCSV(ctx *gin.Context, r io.Reader) (models.BulkImportReport, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.ImportAccountsCSV")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	report := models.BulkImportReport{Results: []models.BulkRowResult{}}
+
+	rows, err := bulk.ReadAccounts(r)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("unable to read accounts csv", zap.Error(err))
+		return report, invalidCSVError(txid, err)
+	}
+
+	for _, row := range rows {
+		rowErr := row.Err
+		if rowErr == nil {
+			rowErr = middleware.ValidateCreateAccountFields(row.Account)
+		}
+		if rowErr != nil {
+			addBulkRowResult(&report, row.Line, constants.EmptyString, rowErr.Error())
+			continue
+		}
+
+		createdAccount, creditCardErr := service.createAccount(ctx, row.Account)
+		if creditCardErr != nil {
+			addBulkRowResult(&report, row.Line, constants.EmptyString, creditCardErr.Message)
+			continue
+		}
+		addBulkRowResult(&report, row.Line, createdAccount.AccountID, constants.EmptyString)
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("bulk account creation finished with %v succeeded and %v failed rows", report.Succeeded, report.Failed))
+	return report, nil
+}
+
+// ImportLimitOffersCSV creates a limit offer for every row of the csv, rows are validated with the same
+// rules as the create limit offer endpoint and a failing row does not stop the remaining rows.
+func (service *CreditCardLimitOfferService) ImportLimitOffersCSV(ctx *gin.Context, r io.Reader) (models.BulkImportReport, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.ImportLimitOffersCSV")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	report := models.BulkImportReport{Results: []models.BulkRowResult{}}
+
+	rows, err := bulk.ReadLimitOffers(r)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("unable to read limit offers csv", zap.Error(err))
+		return report, invalidCSVError(txid, err)
+	}
+
+	for _, row := range rows {
+		rowErr := row.Err
+		if rowErr == nil {
+			rowErr = middleware.ValidateCreateLimitOfferFields(row.LimitOffer)
+		}
+		if rowErr != nil {
+			addBulkRowResult(&report, row.Line, constants.EmptyString, rowErr.Error())
+			continue
+		}
+
+		createdLimitOffer, creditCardErr := service.createLimitOffer(ctx, row.LimitOffer)
+		if creditCardErr != nil {
+			addBulkRowResult(&report, row.Line, constants.EmptyString, creditCardErr.Message)
+			continue
+		}
+		addBulkRowResult(&report, row.Line, createdLimitOffer.ID, constants.EmptyString)
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("bulk limit offer creation finished with %v succeeded and %v failed rows", report.Succeeded, report.Failed))
+	return report, nil
+}
+
+// ImportLimitOfferStatusesCSV accepts or rejects the limit offer of every row of the csv, rows are validated
+// with the same rules as the update limit offer status endpoint and a failing row does not stop the remaining rows.
+func (service *CreditCardLimitOfferService) ImportLimitOfferStatusesCSV(ctx *gin.Context, r io.Reader) (models.BulkImportReport, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.ImportLimitOfferStatusesCSV")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	report := models.BulkImportReport{Results: []models.BulkRowResult{}}
+
+	rows, err := bulk.ReadLimitOfferStatuses(r)
+	if err != nil {
+		utils.RequestLogger(ctx).Error("unable to read limit offer statuses csv", zap.Error(err))
+		return report, invalidCSVError(txid, err)
+	}
+
+	for _, row := range rows {
+		rowErr := middleware.ValidateUpdateLimitOfferStatusFields(row.UpdateLimitOfferStatus)
+		if rowErr != nil {
+			addBulkRowResult(&report, row.Line, row.UpdateLimitOfferStatus.LimitOfferID, rowErr.Error())
+			continue
+		}
+
+		creditCardErr := service.updateLimitOfferStatus(ctx, row.UpdateLimitOfferStatus, operationImportDecisions)
+		if creditCardErr != nil {
+			addBulkRowResult(&report, row.Line, row.UpdateLimitOfferStatus.LimitOfferID, creditCardErr.Message)
+			continue
+		}
+		addBulkRowResult(&report, row.Line, row.UpdateLimitOfferStatus.LimitOfferID, constants.EmptyString)
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("bulk limit offer status update finished with %v succeeded and %v failed rows", report.Succeeded, report.Failed))
+	return report, nil
+}
+
+// ExportLimitOffersCSV streams the limit offers matching the filter as csv, flushing periodically
+// so that large exports are not buffered in memory.
+func (service *CreditCardLimitOfferService) ExportLimitOffersCSV(ctx *gin.Context, filter models.LimitOfferFilter, w io.Writer) *limitoffererror.CreditCardError {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.ExportLimitOffersCSV")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	if err := authorize(ctx, operationExportLimitOffers, nil); err != nil {
+		return err
+	}
+	writer := bulk.NewLimitOfferWriter(w)
+
+	flush := func() error {
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	exported := 0
+	utils.RequestLogger(ctx).Info("calling db layer to export limit offers")
+	err := service.repo.ExportLimitOffers(ctx, filter, func(offer models.LimitOffer) error {
+		if err := writer.Write(offer); err != nil {
+			return err
+		}
+		exported++
+		if exported%exportFlushInterval == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while exporting limit offers")
+		return err
+	}
+
+	// the header has to be present even when no offer matched the filter
+	writeErr := writer.WriteHeader()
+	if writeErr == nil {
+		writeErr = flush()
+	}
+	if writeErr != nil {
+		utils.RequestLogger(ctx).Error("unable to flush exported limit offers", zap.Error(writeErr))
+		return &limitoffererror.CreditCardError{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to write exported limit offers",
+			Trace:   txid,
+		}
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("exported %v limit offers", exported))
+	return nil
+}
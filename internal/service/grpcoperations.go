@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/middleware"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/rpc/limitofferpb"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LimitOfferGRPCService serves the grpc api with the same service layer as the http handlers. Every call expects
+// the gin context of the request, carrying the transaction id and the authenticated principal, under
+// gin.ContextKey of its context, see server.unaryInterceptor.
+type LimitOfferGRPCService struct {
+	limitofferpb.UnimplementedLimitOfferServiceServer
+}
+
+func NewLimitOfferGRPCService() *LimitOfferGRPCService {
+	return &LimitOfferGRPCService{}
+}
+
+func (s *LimitOfferGRPCService) CreateAccount(ctx context.Context, request *limitofferpb.CreateAccountRequest) (*limitofferpb.Account, error) {
+	ginCtx, err := requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.RequestLogger(ginCtx).Info("received grpc request for account creation")
+
+	accountInfo := models.Account{
+		CustomerID:              request.GetCustomerId(),
+		AccountLimit:            intPointer(request.AccountLimit),
+		PerTransactionLimit:     intPointer(request.PerTransactionLimit),
+		LastAccountLimit:        intPointer(request.LastAccountLimit),
+		LastPerTransactionLimit: intPointer(request.LastPerTransactionLimit),
+		Email:                   request.Email,
+		Locale:                  request.GetLocale(),
+	}
+	if accountInfo.CustomerID != constants.EmptyString {
+		if _, err := uuid.Parse(accountInfo.CustomerID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "customer_id should be a uuid")
+		}
+	}
+	if err := middleware.ValidateCreateAccountFields(accountInfo); err != nil {
+		utils.RequestLogger(ginCtx).Error(fmt.Sprintf("%v while creating an account", err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	createdAccount, creditCardErr := creditCardLimitOfferClient.createAccount(ginCtx, accountInfo)
+	if creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+	return accountMessage(createdAccount), nil
+}
+
+func (s *LimitOfferGRPCService) GetAccount(ctx context.Context, request *limitofferpb.GetAccountRequest) (*limitofferpb.Account, error) {
+	ginCtx, err := requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetLogAccountID(ginCtx, request.GetAccountId())
+	utils.RequestLogger(ginCtx).Info(fmt.Sprintf("received grpc request for get %v account", request.GetAccountId()))
+
+	if _, err := uuid.Parse(request.GetAccountId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, constants.InvalidAccountID)
+	}
+
+	fetchedAccount, creditCardErr := creditCardLimitOfferClient.getAccount(ginCtx, request.GetAccountId())
+	if creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+	return accountMessage(fetchedAccount), nil
+}
+
+func (s *LimitOfferGRPCService) CreateLimitOffer(ctx context.Context, request *limitofferpb.CreateLimitOfferRequest) (*limitofferpb.LimitOffer, error) {
+	ginCtx, err := requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.RequestLogger(ginCtx).Info("received grpc request for limit offer creation")
+
+	limitOffer := models.LimitOffer{
+		NewLimit:            intPointer(request.NewLimit),
+		OfferActivationTime: timePointer(request.GetOfferActivationTime()),
+		OfferExpiryTime:     timePointer(request.GetOfferExpiryTime()),
+	}
+	if request.GetAccountId() != constants.EmptyString {
+		accountID := request.GetAccountId()
+		if _, err := uuid.Parse(accountID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, constants.InvalidAccountID)
+		}
+		limitOffer.AccountID = &accountID
+	}
+	if request.GetLimitType() != limitofferpb.LimitType_LIMIT_TYPE_UNSPECIFIED {
+		limitType := limitTypeModel(request.GetLimitType())
+		limitOffer.LimitType = &limitType
+	}
+	if err := middleware.ValidateCreateLimitOfferFields(limitOffer); err != nil {
+		utils.RequestLogger(ginCtx).Error(fmt.Sprintf("%v while creating limit offer", err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	createdLimitOffer, creditCardErr := creditCardLimitOfferClient.createLimitOffer(ginCtx, limitOffer)
+	if creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+	return limitOfferMessage(createdLimitOffer), nil
+}
+
+func (s *LimitOfferGRPCService) ListLimitOffers(ctx context.Context, request *limitofferpb.ListLimitOffersRequest) (*limitofferpb.ListLimitOffersResponse, error) {
+	ginCtx, err := requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetLogAccountID(ginCtx, request.GetAccountId())
+	utils.RequestLogger(ginCtx).Info(fmt.Sprintf("received grpc request to list limit offers of %v account", request.GetAccountId()))
+
+	if _, err := uuid.Parse(request.GetAccountId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, constants.InvalidAccountID)
+	}
+
+	listLimitOffers := models.ListLimitOffers{
+		LimitOfferFilter: models.LimitOfferFilter{
+			CreatedFrom:    timePointer(request.GetCreatedFrom()),
+			CreatedTo:      timePointer(request.GetCreatedTo()),
+			ActivationFrom: timePointer(request.GetActivationFrom()),
+			ActivationTo:   timePointer(request.GetActivationTo()),
+			ExpiryFrom:     timePointer(request.GetExpiryFrom()),
+			ExpiryTo:       timePointer(request.GetExpiryTo()),
+		},
+		SortBy:    request.GetSortBy(),
+		SortOrder: request.GetSortOrder(),
+		PageSize:  int(request.GetPageSize()),
+		Cursor:    request.GetCursor(),
+	}
+	listLimitOffers.AccountID = request.GetAccountId()
+	for _, offerStatus := range request.GetStatus() {
+		listLimitOffers.Status = append(listLimitOffers.Status, offerStatusModel(offerStatus))
+	}
+	for _, limitType := range request.GetLimitType() {
+		listLimitOffers.LimitType = append(listLimitOffers.LimitType, limitTypeModel(limitType))
+	}
+	if err := middleware.ValidateListLimitOffersFields(listLimitOffers); err != nil {
+		utils.RequestLogger(ginCtx).Error(fmt.Sprintf("%v while listing limit offers", err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	limitOfferPage, creditCardErr := creditCardLimitOfferClient.listLimitOffers(ginCtx, listLimitOffers)
+	if creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+
+	response := &limitofferpb.ListLimitOffersResponse{
+		LimitOffers: make([]*limitofferpb.LimitOffer, 0, len(limitOfferPage.LimitOffers)),
+		NextCursor:  limitOfferPage.NextCursor,
+		TotalCount:  int32(limitOfferPage.TotalCount),
+	}
+	for _, limitOffer := range limitOfferPage.LimitOffers {
+		response.LimitOffers = append(response.LimitOffers, limitOfferMessage(limitOffer))
+	}
+	return response, nil
+}
+
+func (s *LimitOfferGRPCService) GetLimitOffer(ctx context.Context, request *limitofferpb.GetLimitOfferRequest) (*limitofferpb.LimitOffer, error) {
+	ginCtx, err := requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.RequestLogger(ginCtx).Info(fmt.Sprintf("received grpc request for get %v limit offer", request.GetLimitOfferId()))
+
+	if _, err := uuid.Parse(request.GetLimitOfferId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, constants.InvalidOfferLimitID)
+	}
+
+	fetchedLimitOffer, creditCardErr := creditCardLimitOfferClient.getLimitOffer(ginCtx, request.GetLimitOfferId())
+	if creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+	return limitOfferMessage(fetchedLimitOffer), nil
+}
+
+// DecideLimitOffer accepts or rejects a pending offer like the update limit offer status endpoint and returns
+// the decided offer
+func (s *LimitOfferGRPCService) DecideLimitOffer(ctx context.Context, request *limitofferpb.DecideLimitOfferRequest) (*limitofferpb.LimitOffer, error) {
+	ginCtx, err := requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.RequestLogger(ginCtx).Info(fmt.Sprintf("received grpc request to decide %v limit offer", request.GetLimitOfferId()))
+
+	updateLimitOfferStatus := models.UpdateLimitOfferStatus{LimitOfferID: request.GetLimitOfferId()}
+	if request.GetDecision() != limitofferpb.Decision_DECISION_UNSPECIFIED {
+		updateLimitOfferStatus.Status = strings.TrimPrefix(request.GetDecision().String(), "DECISION_")
+	}
+	if request.GetDecisionChannel() != limitofferpb.DecisionChannel_DECISION_CHANNEL_UNSPECIFIED {
+		updateLimitOfferStatus.DecisionChannel = models.DecisionChannel(strings.TrimPrefix(request.GetDecisionChannel().String(), "DECISION_CHANNEL_"))
+	}
+	if err := middleware.ValidateUpdateLimitOfferStatusFields(updateLimitOfferStatus); err != nil {
+		utils.RequestLogger(ginCtx).Error(fmt.Sprintf("%v while updating limit offer status", err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if creditCardErr := creditCardLimitOfferClient.updateLimitOfferStatus(ginCtx, updateLimitOfferStatus, operationDecideLimitOffer); creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+	decidedLimitOffer, creditCardErr := creditCardLimitOfferClient.getLimitOffer(ginCtx, updateLimitOfferStatus.LimitOfferID)
+	if creditCardErr != nil {
+		return nil, GRPCError(creditCardErr)
+	}
+	return limitOfferMessage(decidedLimitOffer), nil
+}
+
+// requestContext returns the gin context the interceptor stored for the call
+func requestContext(ctx context.Context) (*gin.Context, error) {
+	ginCtx, ok := ctx.Value(gin.ContextKey).(*gin.Context)
+	if !ok || ginCtx.Request == nil {
+		return nil, status.Error(codes.Internal, "request context is missing")
+	}
+	return ginCtx, nil
+}
+
+// GRPCError maps the http status code of the error to the closest grpc status code
+func GRPCError(err *limitoffererror.CreditCardError) error {
+	code := codes.Internal
+	switch err.Code {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusUnprocessableEntity:
+		code = codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Message)
+}
+
+func accountMessage(account models.Account) *limitofferpb.Account {
+	return &limitofferpb.Account{
+		AccountId:                     account.AccountID,
+		CustomerId:                    account.CustomerID,
+		AccountLimit:                  int64Value(account.AccountLimit),
+		PerTransactionLimit:           int64Value(account.PerTransactionLimit),
+		LastAccountLimit:              int64Value(account.LastAccountLimit),
+		LastPerTransactionLimit:       int64Value(account.LastPerTransactionLimit),
+		AccountLimitUpdateTime:        timestamp(&account.AccountLimitUpdateTime),
+		PerTransactionLimitUpdateTime: timestamp(&account.PerTransactionLimitUpdateTime),
+		Email:                         account.Email,
+		Locale:                        account.Locale,
+	}
+}
+
+func limitOfferMessage(limitOffer models.LimitOffer) *limitofferpb.LimitOffer {
+	message := &limitofferpb.LimitOffer{
+		Id:                  limitOffer.ID,
+		NewLimit:            int64Value(limitOffer.NewLimit),
+		OfferActivationTime: timestamp(limitOffer.OfferActivationTime),
+		OfferExpiryTime:     timestamp(limitOffer.OfferExpiryTime),
+		Status:              limitofferpb.OfferStatus(limitofferpb.OfferStatus_value["OFFER_STATUS_"+string(limitOffer.Status)]),
+		CreatedAt:           timestamp(limitOffer.CreatedAt),
+		UpdatedAt:           timestamp(limitOffer.UpdatedAt),
+		DecidedAt:           timestamp(limitOffer.DecidedAt),
+		DecidedBy:           limitOffer.DecidedBy,
+		SupersededBy:        limitOffer.SupersededBy,
+		CreatedBy:           limitOffer.CreatedBy,
+		ReviewedAt:          timestamp(limitOffer.ReviewedAt),
+		ReviewedBy:          limitOffer.ReviewedBy,
+	}
+	if limitOffer.AccountID != nil {
+		message.AccountId = *limitOffer.AccountID
+	}
+	if limitOffer.LimitType != nil {
+		message.LimitType = limitofferpb.LimitType(limitofferpb.LimitType_value["LIMIT_TYPE_"+string(*limitOffer.LimitType)])
+	}
+	if limitOffer.DecisionChannel != nil {
+		message.DecisionChannel = limitofferpb.DecisionChannel(limitofferpb.DecisionChannel_value["DECISION_CHANNEL_"+string(*limitOffer.DecisionChannel)])
+	}
+	return message
+}
+
+// the enum values are the model values prefixed with the enum name, unknown values are left for the validation to reject
+func limitTypeModel(limitType limitofferpb.LimitType) models.LimitType {
+	return models.LimitType(strings.TrimPrefix(limitType.String(), "LIMIT_TYPE_"))
+}
+
+func offerStatusModel(offerStatus limitofferpb.OfferStatus) models.OfferStatus {
+	return models.OfferStatus(strings.TrimPrefix(offerStatus.String(), "OFFER_STATUS_"))
+}
+
+func intPointer(value *int64) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+func int64Value(value *int) int64 {
+	if value == nil {
+		return 0
+	}
+	return int64(*value)
+}
+
+func timePointer(value *timestamppb.Timestamp) *time.Time {
+	if value == nil {
+		return nil
+	}
+	converted := value.AsTime()
+	return &converted
+}
+
+func timestamp(value *time.Time) *timestamppb.Timestamp {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*value)
+}
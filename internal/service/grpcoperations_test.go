@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/rpc/limitofferpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCError(t *testing.T) {
+	tests := []struct {
+		code int
+		want codes.Code
+	}{
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusForbidden, codes.PermissionDenied},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusConflict, codes.AlreadyExists},
+		{http.StatusUnprocessableEntity, codes.FailedPrecondition},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+		{http.StatusInternalServerError, codes.Internal},
+	}
+	for _, test := range tests {
+		err := GRPCError(&limitoffererror.CreditCardError{Code: test.code, Message: "boom"})
+		assert.Equal(t, test.want, status.Code(err), http.StatusText(test.code))
+		assert.Equal(t, "boom", status.Convert(err).Message())
+	}
+}
+
+func TestLimitOfferMessage(t *testing.T) {
+	accountID := "2b4e1e64-624f-4a4e-9911-e0b13f526e10"
+	limitType := models.PerTransactionLimit
+	newLimit := 500
+	channel := models.AgentChannel
+	expiry := time.Date(2023, 9, 1, 10, 30, 0, 0, time.UTC)
+	message := limitOfferMessage(models.LimitOffer{
+		ID:              "c0a2b7a4-6e0b-4b53-9a9e-6d3b1f0e2a11",
+		AccountID:       &accountID,
+		LimitType:       &limitType,
+		NewLimit:        &newLimit,
+		OfferExpiryTime: &expiry,
+		Status:          models.AwaitingApproval,
+		DecisionChannel: &channel,
+	})
+
+	assert.Equal(t, accountID, message.AccountId)
+	assert.Equal(t, limitofferpb.LimitType_LIMIT_TYPE_PER_TRANSACTION_LIMIT, message.LimitType)
+	assert.Equal(t, int64(500), message.NewLimit)
+	assert.Equal(t, expiry, message.OfferExpiryTime.AsTime())
+	assert.Nil(t, message.OfferActivationTime)
+	assert.Equal(t, limitofferpb.OfferStatus_OFFER_STATUS_AWAITING_APPROVAL, message.Status)
+	assert.Equal(t, limitofferpb.DecisionChannel_DECISION_CHANNEL_AGENT, message.DecisionChannel)
+	assert.Nil(t, message.DecidedBy)
+
+	// the enums map back to the model values
+	assert.Equal(t, limitType, limitTypeModel(message.LimitType))
+	assert.Equal(t, models.AwaitingApproval, offerStatusModel(message.Status))
+}
+
+func TestRequestContextIsRequired(t *testing.T) {
+	_, err := NewLimitOfferGRPCService().GetAccount(context.Background(), &limitofferpb.GetAccountRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -1,36 +1,42 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
 	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
 	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
 	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // This function is responsible for account creation
 func CreateLimitOffer() func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
-		txid := ctx.Request.Header.Get(constants.TransactionID)
-		utils.Logger.Info(fmt.Sprintf("received request for account creation, txid : %v", txid))
+		utils.RequestLogger(ctx).Info("received request for account creation")
 		var limitOffer models.LimitOffer
 		if err := ctx.ShouldBindBodyWith(&limitOffer, binding.JSON); err == nil {
-			utils.Logger.Info(fmt.Sprintf("user request for account creation is unmarshalled successfully, txid : %v", txid))
+			utils.RequestLogger(ctx).Info("user request for account creation is unmarshalled successfully")
 
-			offerLimitID, err := creditCardLimitOfferClient.createLimitOffer(ctx, limitOffer)
+			createdLimitOffer, err := creditCardLimitOfferClient.createLimitOffer(ctx, limitOffer)
 			if err != nil {
 				utils.RespondWithError(ctx, err.Code, err.Message)
 				return
 			}
 
 			ctx.JSON(http.StatusOK, map[string]string{
-				"offer_limit_id": offerLimitID,
+				"offer_limit_id": createdLimitOffer.ID,
+				"status":         string(createdLimitOffer.Status),
 			})
 
 			ctx.Writer.WriteHeader(http.StatusOK)
@@ -40,62 +46,85 @@ func CreateLimitOffer() func(ctx *gin.Context) {
 	}
 }
 
-func (service *CreditCardLimitOfferService) createLimitOffer(ctx *gin.Context, limitOffer models.LimitOffer) (string, *limitoffererror.CreditCardError) {
+func (service *CreditCardLimitOfferService) createLimitOffer(ctx *gin.Context, limitOffer models.LimitOffer) (models.LimitOffer, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.createLimitOffer")()
+
 	txid := ctx.Request.Header.Get(constants.TransactionID)
-	utils.Logger.Info(fmt.Sprintf("calling db layer for fetching account %v info to get the existing limit, txid : %v", limitOffer.AccountID, txid))
+	if err := authorize(ctx, operationCreateLimitOffer, nil); err != nil {
+		return models.LimitOffer{}, err
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer for fetching account %v info to get the existing limit", limitOffer.AccountID))
 
 	fetchedAccount, err := service.repo.GetAccount(ctx, *limitOffer.AccountID)
 	if err != nil {
-		return constants.EmptyString, err
-	}
-	isLimitOfferExsits, offerLimitID, err := service.repo.IsLimitOfferExists(ctx, limitOffer)
-	if err != nil {
-		return constants.EmptyString, err
+		return models.LimitOffer{}, err
 	}
-	fmt.Println("isLimitOfferExsits : ", isLimitOfferExsits)
 	var currentLimit int
 	if *limitOffer.LimitType == models.AccountLimit {
 		currentLimit = *fetchedAccount.AccountLimit
 	} else if *limitOffer.LimitType == models.PerTransactionLimit {
 		currentLimit = *fetchedAccount.PerTransactionLimit
 	}
-	fmt.Println("*limitOffer.NewLimit <= currentLimit ", *limitOffer.NewLimit, ": ", currentLimit)
 	if *limitOffer.NewLimit <= currentLimit {
-		return constants.EmptyString, &limitoffererror.CreditCardError{
+		return models.LimitOffer{}, &limitoffererror.CreditCardError{
 			Code:    http.StatusBadRequest,
 			Message: "offer limit is less than or equal to existing limit",
 			Trace:   txid,
 		}
 	}
 
-	if !isLimitOfferExsits {
-		// create the offer id
-		limitOffer.ID = uuid.New().String()
-	} else {
-		limitOffer.ID = offerLimitID
-	}
-
+	// every offer is a new row, an existing pending offer is handled by the db layer as per the duplicate policy
+	limitOffer.ID = uuid.New().String()
 	limitOffer.Status = models.Pending
+	if requiresApproval(currentLimit, *limitOffer.NewLimit) {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("limit offer for %v account is above the approval threshold", *limitOffer.AccountID))
+		limitOffer.Status = models.AwaitingApproval
+	}
+	if principal, ok := utils.GetPrincipal(ctx); ok {
+		createdBy := principalName(principal)
+		limitOffer.CreatedBy = &createdBy
+	}
 
-	// if current limit is greater than existing limit for an account, then update the existing time with status as PENDING
-	utils.Logger.Info(fmt.Sprintf("calling db layer for creating limit offer for %v account, txid : %v", limitOffer.AccountID, txid))
-	err = service.repo.CreateLimitOffer(ctx, limitOffer, isLimitOfferExsits)
+	duplicatePolicy := config.GetConfig().LimitOffer.DuplicatePolicy
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer for creating limit offer for %v account with %v policy", *limitOffer.AccountID, duplicatePolicy))
+	err = service.repo.CreateLimitOffer(ctx, limitOffer, duplicatePolicy)
 	if err != nil {
-		utils.Logger.Info(fmt.Sprintf("received error from db layer while creating creating limit offer for %v account, txid : %v", limitOffer.AccountID, txid))
-		return constants.EmptyString, err
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while creating creating limit offer for %v account", *limitOffer.AccountID))
+		return models.LimitOffer{}, err
+	}
+	// the offers awaiting approval are not offered to the customer yet, they are counted once approved
+	if limitOffer.Status == models.Pending {
+		metrics.LimitOfferOutcome(metrics.OfferCreated, limitOffer)
 	}
 
-	return limitOffer.ID, nil
+	return limitOffer, nil
+}
+
+// requiresApproval tells whether an offer raising the current limit to the new limit is above the approval threshold
+func requiresApproval(currentLimit int, newLimit int) bool {
+	limitOfferConfig := config.GetConfig().LimitOffer
+	if limitOfferConfig.ApprovalThresholdAmount > 0 && newLimit > limitOfferConfig.ApprovalThresholdAmount {
+		return true
+	}
+	if limitOfferConfig.ApprovalThresholdPercentage > 0 {
+		// any increase over a zero limit is an infinite percentage
+		if currentLimit <= 0 {
+			return true
+		}
+		increase := float64(newLimit-currentLimit) * 100 / float64(currentLimit)
+		return increase > limitOfferConfig.ApprovalThresholdPercentage
+	}
+	return false
 }
 
 // This function is responsible to list all active limit offers
 func ListActiveLimitOffers() func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
-		txid := ctx.Request.Header.Get(constants.TransactionID)
-		utils.Logger.Info(fmt.Sprintf("received request for to list all active limit offer, txid : %v", txid))
+		utils.RequestLogger(ctx).Info("received request for to list all active limit offer")
 		var activeLimitOffer models.ActiveLimitOffer
 		if err := ctx.ShouldBindBodyWith(&activeLimitOffer, binding.JSON); err == nil {
-			utils.Logger.Info(fmt.Sprintf("received request for account creation is unmarshalled successfully, txid : %v", txid))
+			utils.RequestLogger(ctx).Info("received request for account creation is unmarshalled successfully")
 
 			activeLimitOffers, err := creditCardLimitOfferClient.listActiveLimitOffers(ctx, activeLimitOffer)
 			if err != nil {
@@ -113,17 +142,21 @@ func ListActiveLimitOffers() func(ctx *gin.Context) {
 }
 
 func (service *CreditCardLimitOfferService) listActiveLimitOffers(ctx *gin.Context, activeLimitOffer models.ActiveLimitOffer) ([]models.LimitOffer, *limitoffererror.CreditCardError) {
-	txid := ctx.Request.Header.Get(constants.TransactionID)
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.listActiveLimitOffers")()
 
 	if activeLimitOffer.ActiveDate == nil {
 		time := time.Now().UTC()
 		activeLimitOffer.ActiveDate = &time
 	}
 
-	utils.Logger.Info(fmt.Sprintf("calling db layer to list all active limit offer for %v account, txid : %v", activeLimitOffer.AccountID, txid))
+	if _, err := service.authorizeAccount(ctx, operationViewLimitOffers, activeLimitOffer.AccountID); err != nil {
+		return []models.LimitOffer{}, err
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to list all active limit offer for %v account", activeLimitOffer.AccountID))
 	fetchedAccount, err := service.repo.ListActiveLimitOffers(ctx, activeLimitOffer)
 	if err != nil {
-		utils.Logger.Info(fmt.Sprintf("received error from db layer while fetching all active limit offer for %v account, txid : %v", activeLimitOffer.AccountID, txid))
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while fetching all active limit offer for %v account", activeLimitOffer.AccountID))
 		return []models.LimitOffer{}, err
 	}
 
@@ -133,13 +166,12 @@ func (service *CreditCardLimitOfferService) listActiveLimitOffers(ctx *gin.Conte
 // This function is responsible to update limit offer status
 func UpdateLimitOfferStatus() func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
-		txid := ctx.Request.Header.Get(constants.TransactionID)
-		utils.Logger.Info(fmt.Sprintf("received request for to list all active limit offer, txid : %v", txid))
+		utils.RequestLogger(ctx).Info("received request for to list all active limit offer")
 		var updateLimitOfferStatus models.UpdateLimitOfferStatus
 		if err := ctx.ShouldBindBodyWith(&updateLimitOfferStatus, binding.JSON); err == nil {
-			utils.Logger.Info(fmt.Sprintf("received request for account creation is unmarshalled successfully, txid : %v", txid))
+			utils.RequestLogger(ctx).Info("received request for account creation is unmarshalled successfully")
 
-			err := creditCardLimitOfferClient.updateLimitOfferStatus(ctx, updateLimitOfferStatus)
+			err := creditCardLimitOfferClient.updateLimitOfferStatus(ctx, updateLimitOfferStatus, operationDecideLimitOffer)
 			if err != nil {
 				utils.RespondWithError(ctx, err.Code, err.Message)
 				return
@@ -153,34 +185,290 @@ func UpdateLimitOfferStatus() func(ctx *gin.Context) {
 	}
 }
 
-func (service *CreditCardLimitOfferService) updateLimitOfferStatus(ctx *gin.Context, updateLimitOfferStatus models.UpdateLimitOfferStatus) *limitoffererror.CreditCardError {
+// updateLimitOfferStatus decides the offer when the principal of the request may perform the operation on its account,
+// a customer decides the offers one by one and the back office imports the decisions collected by other channels
+func (service *CreditCardLimitOfferService) updateLimitOfferStatus(ctx *gin.Context, updateLimitOfferStatus models.UpdateLimitOfferStatus, operation string) *limitoffererror.CreditCardError {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.updateLimitOfferStatus")()
+
 	txid := ctx.Request.Header.Get(constants.TransactionID)
 
 	limitOfferInfo, err := service.repo.GetLimitOffer(ctx, updateLimitOfferStatus.LimitOfferID)
 	if err != nil {
 		return err
 	}
-	fmt.Println("limitOfferInfo.Status : ", limitOfferInfo.Status, limitOfferInfo)
-	if limitOfferInfo.Status == models.Accepted {
+	if _, err := service.authorizeAccount(ctx, operation, *limitOfferInfo.AccountID); err != nil {
+		return err
+	}
+	if !isVisible(ctx, limitOfferInfo) {
+		return limitOfferNotFound(txid)
+	}
+
+	if limitOfferInfo.Status != models.Pending {
 		return &limitoffererror.CreditCardError{
 			Code:    http.StatusUnprocessableEntity,
-			Message: "limit offer is already in accepted state",
+			Message: fmt.Sprintf("limit offer is already in %v state", strings.ToLower(string(limitOfferInfo.Status))),
 			Trace:   txid,
 		}
-	} else if limitOfferInfo.Status == models.Rejected {
+	}
+
+	// the authenticated caller is recorded as the decider instead of what the request claims
+	if principal, ok := utils.GetPrincipal(ctx); ok && principal.AuthMethod != constants.AuthMethodAnonymous {
+		updateLimitOfferStatus.DecidedBy = principal.Subject
+	}
+
+	utils.RequestLogger(ctx).Info("calling db layer to update limit offer status account")
+	err = service.repo.UpdateLimitOfferStatus(ctx, updateLimitOfferStatus)
+	if err != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while updating limit offer status")
+		return err
+	}
+	if models.OfferStatus(updateLimitOfferStatus.Status) == models.Accepted {
+		metrics.LimitOfferOutcome(metrics.OfferAccepted, limitOfferInfo)
+	} else {
+		metrics.LimitOfferOutcome(metrics.OfferRejected, limitOfferInfo)
+	}
+
+	return nil
+}
+
+// This function is responsible to get a specific limit offer based on limit offer id
+func GetLimitOffer() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		limitOfferID := ctx.Param(constants.LimitOfferID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("request received for get %v limit offer", limitOfferID))
+		fetchedLimitOffer, err := creditCardLimitOfferClient.getLimitOffer(ctx, limitOfferID)
+		if err != nil {
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, fetchedLimitOffer)
+	}
+}
+
+func (service *CreditCardLimitOfferService) getLimitOffer(ctx *gin.Context, limitOfferID string) (models.LimitOffer, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.getLimitOffer")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer for getting %v limit offer", limitOfferID))
+	fetchedLimitOffer, err := service.repo.GetLimitOffer(ctx, limitOfferID)
+	if err != nil {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer during getting %v limit offer", limitOfferID))
+		return models.LimitOffer{}, err
+	}
+
+	if _, err := service.authorizeAccount(ctx, operationViewLimitOffers, *fetchedLimitOffer.AccountID); err != nil {
+		return models.LimitOffer{}, err
+	}
+	if !isVisible(ctx, fetchedLimitOffer) {
+		return models.LimitOffer{}, limitOfferNotFound(txid)
+	}
+
+	return fetchedLimitOffer, nil
+}
+
+// This function is responsible to list the limit offers of an account page by page
+func ListLimitOffers() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		accountID := ctx.Param(constants.AccountID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to list limit offers of %v account", accountID))
+		var listLimitOffers models.ListLimitOffers
+		if err := ctx.ShouldBindQuery(&listLimitOffers); err == nil {
+			listLimitOffers.AccountID = accountID
+
+			limitOfferPage, err := creditCardLimitOfferClient.listLimitOffers(ctx, listLimitOffers)
+			if err != nil {
+				utils.RespondWithError(ctx, err.Code, err.Message)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, limitOfferPage)
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Unable to bind the query params": err.Error()})
+		}
+	}
+}
+
+func (service *CreditCardLimitOfferService) listLimitOffers(ctx *gin.Context, listLimitOffers models.ListLimitOffers) (models.LimitOfferPage, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.listLimitOffers")()
+
+	if listLimitOffers.SortBy == constants.EmptyString {
+		listLimitOffers.SortBy = constants.SortByCreatedAt
+	}
+	if listLimitOffers.SortOrder == constants.EmptyString {
+		listLimitOffers.SortOrder = constants.SortOrderDesc
+	}
+	if listLimitOffers.PageSize == 0 {
+		listLimitOffers.PageSize = constants.DefaultPageSize
+	}
+
+	// listing the offers of an unknown account is reported as not found rather than an empty page
+	_, err := service.authorizeAccount(ctx, operationViewLimitOffers, listLimitOffers.AccountID)
+	if err != nil {
+		return models.LimitOfferPage{}, err
+	}
+
+	if !seesUnapprovedOffers(ctx) {
+		listLimitOffers.Status = customerVisibleStatuses(listLimitOffers.Status)
+		if len(listLimitOffers.Status) == 0 {
+			return models.LimitOfferPage{LimitOffers: []models.LimitOffer{}}, nil
+		}
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to list limit offers for %v account", listLimitOffers.AccountID))
+	limitOfferPage, err := service.repo.ListLimitOffers(ctx, listLimitOffers)
+	if err != nil {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while listing limit offers for %v account", listLimitOffers.AccountID))
+		return models.LimitOfferPage{}, err
+	}
+
+	return limitOfferPage, nil
+}
+
+// This function is responsible to cancel a pending limit offer
+func CancelLimitOffer() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		limitOfferID := ctx.Param(constants.LimitOfferID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to cancel %v limit offer", limitOfferID))
+		err := creditCardLimitOfferClient.cancelLimitOffer(ctx, limitOfferID)
+		if err != nil {
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+func (service *CreditCardLimitOfferService) cancelLimitOffer(ctx *gin.Context, limitOfferID string) *limitoffererror.CreditCardError {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.cancelLimitOffer")()
+
+	if err := authorize(ctx, operationCancelLimitOffer, nil); err != nil {
+		return err
+	}
+
+	principal, _ := utils.GetPrincipal(ctx)
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to cancel %v limit offer", limitOfferID))
+	err := service.repo.CancelLimitOffer(ctx, limitOfferID, principal.Subject)
+	if err != nil {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while cancelling %v limit offer", limitOfferID))
+		return err
+	}
+
+	return nil
+}
+
+// This function is responsible to approve a limit offer awaiting approval
+func ApproveLimitOffer() func(ctx *gin.Context) {
+	return reviewLimitOfferHandler(models.Pending)
+}
+
+// This function is responsible to decline a limit offer awaiting approval
+func DeclineLimitOffer() func(ctx *gin.Context) {
+	return reviewLimitOfferHandler(models.Declined)
+}
+
+func reviewLimitOfferHandler(status models.OfferStatus) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		limitOfferID := ctx.Param(constants.LimitOfferID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to review %v limit offer", limitOfferID))
+		err := creditCardLimitOfferClient.reviewLimitOffer(ctx, models.ReviewLimitOffer{LimitOfferID: limitOfferID, Status: status})
+		if err != nil {
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, nil)
+	}
+}
+
+// reviewLimitOffer makes an offer awaiting approval PENDING or DECLINED, the reviewer has to be someone else than the creator.
+// A principal without a subject can not be told apart from the other users of its client, it may not review.
+func (service *CreditCardLimitOfferService) reviewLimitOffer(ctx *gin.Context, reviewLimitOffer models.ReviewLimitOffer) *limitoffererror.CreditCardError {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.reviewLimitOffer")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	if err := authorize(ctx, operationReviewLimitOffer, nil); err != nil {
+		return err
+	}
+
+	limitOfferInfo, err := service.repo.GetLimitOffer(ctx, reviewLimitOffer.LimitOfferID)
+	if err != nil {
+		return err
+	}
+	if limitOfferInfo.Status != models.AwaitingApproval {
 		return &limitoffererror.CreditCardError{
 			Code:    http.StatusUnprocessableEntity,
-			Message: "limit offer is already in rejected state",
+			Message: fmt.Sprintf("limit offer is already in %v state", strings.ToLower(string(limitOfferInfo.Status))),
 			Trace:   txid,
 		}
 	}
 
-	utils.Logger.Info(fmt.Sprintf("calling db layer to update limit offer status account, txid : %v", txid))
-	err = service.repo.UpdateLimitOfferStatus(ctx, updateLimitOfferStatus)
+	principal, _ := utils.GetPrincipal(ctx)
+	if principal.Subject == constants.EmptyString {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("principal without a subject tried to review %v limit offer", reviewLimitOffer.LimitOfferID))
+		return &limitoffererror.CreditCardError{
+			Code:    http.StatusForbidden,
+			Message: "limit offer can not be reviewed without a subject",
+			Trace:   txid,
+		}
+	}
+	reviewer := principalName(principal)
+	if limitOfferInfo.CreatedBy != nil && *limitOfferInfo.CreatedBy == reviewer {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("%v tried to review own limit offer %v", reviewer, reviewLimitOffer.LimitOfferID))
+		return &limitoffererror.CreditCardError{
+			Code:    http.StatusForbidden,
+			Message: "limit offer can not be reviewed by its creator",
+			Trace:   txid,
+		}
+	}
+	reviewLimitOffer.ReviewedBy = reviewer
+
+	duplicatePolicy := config.GetConfig().LimitOffer.DuplicatePolicy
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to review %v limit offer as %v", reviewLimitOffer.LimitOfferID, reviewLimitOffer.Status))
+	err = service.repo.ReviewLimitOffer(ctx, reviewLimitOffer, duplicatePolicy)
 	if err != nil {
-		utils.Logger.Info(fmt.Sprintf("received error from db layer while updating limit offer status, txid : %v", txid))
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while reviewing %v limit offer", reviewLimitOffer.LimitOfferID))
 		return err
 	}
+	if reviewLimitOffer.Status == models.Pending {
+		metrics.LimitOfferOutcome(metrics.OfferCreated, limitOfferInfo)
+	}
 
 	return nil
 }
+
+func limitOfferNotFound(txid string) *limitoffererror.CreditCardError {
+	return &limitoffererror.CreditCardError{
+		Code:    http.StatusNotFound,
+		Message: "limit offer not found",
+		Trace:   txid,
+	}
+}
+
+// RunExpirySweeper marks the offers past their expiry time EXPIRED every interval until ctx is cancelled
+func (service *CreditCardLimitOfferService) RunExpirySweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Logger.Info("stopped the limit offer expiry sweeper")
+			return
+		case <-ticker.C:
+		}
+
+		sweepCtx := utils.NewBackgroundContext()
+		sweepCtx.Request = sweepCtx.Request.WithContext(ctx)
+		expired, err := service.repo.ExpireLimitOffers(sweepCtx)
+		if err != nil {
+			utils.Logger.Error("unable to expire the limit offers", zap.String("transaction_id", err.Trace), zap.String("error", err.Message))
+			continue
+		}
+		for _, limitOffer := range expired {
+			metrics.LimitOfferOutcome(metrics.OfferExpired, limitOffer)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/db"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/metrics"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiresApproval(t *testing.T) {
+	// case 1 : thresholds disabled
+	config.SetConfig(config.GlobalConfig{})
+	assert.False(t, requiresApproval(1000, 1000000))
+
+	// case 2 : absolute amount of the new limit
+	config.SetConfig(config.GlobalConfig{LimitOffer: config.LimitOffer{ApprovalThresholdAmount: 50000}})
+	assert.False(t, requiresApproval(1000, 50000))
+	assert.True(t, requiresApproval(1000, 50001))
+
+	// case 3 : percentage increase over the current limit
+	config.SetConfig(config.GlobalConfig{LimitOffer: config.LimitOffer{ApprovalThresholdPercentage: 50}})
+	assert.False(t, requiresApproval(1000, 1500))
+	assert.True(t, requiresApproval(1000, 1501))
+	assert.True(t, requiresApproval(0, 1))
+}
+
+// reviewRepository serves the offer under review and records the reviews
+type reviewRepository struct {
+	db.CreditCardLimitOfferService
+	offer    models.LimitOffer
+	reviewed []models.ReviewLimitOffer
+}
+
+func (r *reviewRepository) GetLimitOffer(*gin.Context, string) (models.LimitOffer, *limitoffererror.CreditCardError) {
+	return r.offer, nil
+}
+
+func (r *reviewRepository) ReviewLimitOffer(_ *gin.Context, review models.ReviewLimitOffer, _ string) *limitoffererror.CreditCardError {
+	r.reviewed = append(r.reviewed, review)
+	return nil
+}
+
+func TestReviewLimitOfferByItsCreator(t *testing.T) {
+	utils.InitLogClient()
+	config.SetConfig(config.GlobalConfig{})
+	createdBy := "risk-console/jane"
+	repo := &reviewRepository{offer: models.LimitOffer{ID: "offer", Status: models.AwaitingApproval, CreatedBy: &createdBy}}
+	service := &CreditCardLimitOfferService{repo: repo}
+	review := func(principal models.Principal) *limitoffererror.CreditCardError {
+		principal.Roles = []string{constants.RoleRiskOfficer}
+		return service.reviewLimitOffer(principalContext(&principal), models.ReviewLimitOffer{LimitOfferID: "offer", Status: models.Pending})
+	}
+
+	// case 1 : the creator can not review the offer
+	err := review(models.Principal{ClientID: "risk-console", Subject: "jane"})
+	assert.Equal(t, http.StatusForbidden, err.Code)
+
+	// case 2 : a principal without a subject can not review, it can not be told apart from the creator
+	err = review(models.Principal{ClientID: "risk-console"})
+	assert.Equal(t, http.StatusForbidden, err.Code)
+
+	// case 3 : the same subject in another client is someone else
+	assert.Nil(t, review(models.Principal{ClientID: "back-office", Subject: "jane"}))
+	assert.Equal(t, "back-office/jane", repo.reviewed[0].ReviewedBy)
+
+	// case 4 : the system principal is not exempted from the check
+	createdBy = constants.SystemClientID + "/" + constants.SystemClientID
+	err = service.reviewLimitOffer(utils.NewBackgroundContext(), models.ReviewLimitOffer{LimitOfferID: "offer", Status: models.Pending})
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	assert.Len(t, repo.reviewed, 1)
+}
+
+func TestReviewLimitOfferCountsTheApprovedOffer(t *testing.T) {
+	utils.InitLogClient()
+	config.SetConfig(config.GlobalConfig{})
+	createdBy := "risk-console/jane"
+	limitType := models.LimitType("REVIEWED_LIMIT")
+	repo := &reviewRepository{offer: models.LimitOffer{ID: "offer", Status: models.AwaitingApproval, LimitType: &limitType, CreatedBy: &createdBy}}
+	service := &CreditCardLimitOfferService{repo: repo}
+	principal := models.Principal{ClientID: "risk-console", Subject: "john", Roles: []string{constants.RoleRiskOfficer}}
+	createdOffers := func() string {
+		recorder := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, _ := io.ReadAll(recorder.Body)
+		for _, line := range strings.Split(string(body), "\n") {
+			if strings.Contains(line, `limit_type="REVIEWED_LIMIT",outcome="created"`) {
+				return line[strings.LastIndex(line, " ")+1:]
+			}
+		}
+		return ""
+	}
+
+	// case 1 : the declined offer is never created
+	assert.Nil(t, service.reviewLimitOffer(principalContext(&principal), models.ReviewLimitOffer{LimitOfferID: "offer", Status: models.Declined}))
+	assert.Equal(t, "", createdOffers())
+
+	// case 2 : the approved offer is created
+	assert.Nil(t, service.reviewLimitOffer(principalContext(&principal), models.ReviewLimitOffer{LimitOfferID: "offer", Status: models.Pending}))
+	assert.Equal(t, "1", createdOffers())
+}
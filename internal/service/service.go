@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/ankit/project/credit-card-offer-limit/internal/db"
+	"github.com/ankit/project/credit-card-offer-limit/internal/stream"
 	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
 )
 
@@ -14,6 +15,8 @@ var (
 
 type CreditCardLimitOfferService struct {
 	repo db.CreditCardLimitOfferService
+	// broker of the limit offer event streams, nil when the streams are disabled
+	broker *stream.Broker
 }
 
 // creditCardLimitOfferClient should only be created once throughtout the application lifetime
@@ -30,3 +33,8 @@ func NewCreditCardLimitOfferService(conn db.CreditCardLimitOfferService) *Credit
 	}
 	return creditCardLimitOfferClient
 }
+
+// SetEventBroker enables the limit offer event streams served from the broker
+func (service *CreditCardLimitOfferService) SetEventBroker(broker *stream.Broker) {
+	service.broker = broker
+}
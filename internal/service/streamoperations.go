@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/stream"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// This function is responsible to stream the limit offer events of an account as server-sent events.
+// A client reconnecting with the Last-Event-ID header gets the events it missed first, or a resync event
+// when they are no longer retained and it has to reload the offers.
+func StreamLimitOfferEvents() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		accountID := ctx.Param(constants.AccountID)
+		lastEventID := ctx.GetHeader(constants.LastEventIDHeader)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to stream the limit offer events of %v account", accountID))
+
+		subscription, replay, found, err := creditCardLimitOfferClient.subscribeLimitOfferEvents(ctx, accountID, lastEventID)
+		if err != nil {
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+		defer creditCardLimitOfferClient.broker.Unsubscribe(subscription)
+
+		ctx.Header(constants.ContentType, constants.TextEventStream)
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("Connection", "keep-alive")
+		// proxies must not buffer the stream
+		ctx.Header("X-Accel-Buffering", "no")
+		ctx.Status(http.StatusOK)
+
+		if !found {
+			stream.WriteControl(ctx.Writer, constants.EventResync, `{"reason":"last event is no longer retained"}`)
+		}
+		for _, event := range replay {
+			if isVisibleEvent(ctx, event) {
+				stream.WriteEvent(ctx.Writer, event)
+			}
+		}
+		ctx.Writer.Flush()
+
+		heartbeat := time.NewTicker(time.Duration(config.GetConfig().Stream.HeartbeatInterval) * time.Second)
+		defer heartbeat.Stop()
+		for {
+			var writeErr error
+			select {
+			case <-ctx.Request.Context().Done():
+				utils.RequestLogger(ctx).Info(fmt.Sprintf("client closed the limit offer event stream of %v account", accountID))
+				return
+			case event, open := <-subscription.Events():
+				if !open {
+					utils.RequestLogger(ctx).Info(fmt.Sprintf("closed the limit offer event stream of %v account", accountID))
+					return
+				}
+				if !isVisibleEvent(ctx, event) {
+					continue
+				}
+				writeErr = stream.WriteEvent(ctx.Writer, event)
+			case <-heartbeat.C:
+				writeErr = stream.WriteHeartbeat(ctx.Writer)
+			}
+			if writeErr != nil {
+				utils.RequestLogger(ctx).Info(fmt.Sprintf("unable to write the limit offer event stream of %v account", accountID), zap.Error(writeErr))
+				return
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+func (service *CreditCardLimitOfferService) subscribeLimitOfferEvents(ctx *gin.Context, accountID string, lastEventID string) (*stream.Subscription, []models.OutboxEvent, bool, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.subscribeLimitOfferEvents")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+	unavailable := &limitoffererror.CreditCardError{
+		Code:    http.StatusServiceUnavailable,
+		Message: "limit offer event stream is not available",
+		Trace:   txid,
+	}
+
+	if service.broker == nil {
+		return nil, nil, false, unavailable
+	}
+	if _, err := service.authorizeAccount(ctx, operationViewLimitOffers, accountID); err != nil {
+		return nil, nil, false, err
+	}
+
+	subscription, replay, found, err := service.broker.Subscribe(accountID, lastEventID)
+	if err != nil {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("unable to subscribe to the limit offer events of %v account", accountID), zap.Error(err))
+		return nil, nil, false, unavailable
+	}
+	return subscription, replay, found, nil
+}
+
+// isVisibleEvent tells whether the offer of the event may be shown to the principal of the request
+func isVisibleEvent(ctx *gin.Context, event models.OutboxEvent) bool {
+	var limitOffer models.LimitOffer
+	if err := json.Unmarshal(event.Payload, &limitOffer); err != nil {
+		return false
+	}
+	return isVisible(ctx, limitOffer)
+}
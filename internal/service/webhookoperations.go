@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/ankit/project/credit-card-offer-limit/internal/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// This function is responsible to register a webhook subscription, the response carries the signing secret
+func CreateWebhookSubscription() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request to create a webhook subscription")
+		var subscription models.WebhookSubscription
+		if err := ctx.ShouldBindBodyWith(&subscription, binding.JSON); err == nil {
+			createdSubscription, err := creditCardLimitOfferClient.createWebhookSubscription(ctx, subscription)
+			if err != nil {
+				utils.RespondWithError(ctx, err.Code, err.Message)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, createdSubscription)
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Unable to marshal the request body": err.Error()})
+		}
+	}
+}
+
+func (service *CreditCardLimitOfferService) createWebhookSubscription(ctx *gin.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.createWebhookSubscription")()
+
+	txid := ctx.Request.Header.Get(constants.TransactionID)
+
+	if err := authorize(ctx, operationManageWebhooks, nil); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		utils.RequestLogger(ctx).Error("unable to generate the webhook secret", zap.Error(err))
+		return models.WebhookSubscription{}, &limitoffererror.CreditCardError{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to create the webhook subscription",
+			Trace:   txid,
+		}
+	}
+	principal, _ := utils.GetPrincipal(ctx)
+	subscription.ID = uuid.New().String()
+	subscription.Secret = secret
+	subscription.CreatedBy = principal.Subject
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to create the %v webhook subscription", subscription.ID))
+	createdSubscription, creditCardErr := service.repo.CreateWebhookSubscription(ctx, subscription)
+	if creditCardErr != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while creating a webhook subscription")
+		return models.WebhookSubscription{}, creditCardErr
+	}
+
+	return createdSubscription, nil
+}
+
+// This function is responsible to list the webhook subscriptions
+func ListWebhookSubscriptions() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		utils.RequestLogger(ctx).Info("received request to list the webhook subscriptions")
+		subscriptions, err := creditCardLimitOfferClient.listWebhookSubscriptions(ctx)
+		if err != nil {
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"webhook_subscriptions": subscriptions})
+	}
+}
+
+func (service *CreditCardLimitOfferService) listWebhookSubscriptions(ctx *gin.Context) ([]models.WebhookSubscription, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.listWebhookSubscriptions")()
+
+	if err := authorize(ctx, operationManageWebhooks, nil); err != nil {
+		return nil, err
+	}
+
+	utils.RequestLogger(ctx).Info("calling db layer to list the webhook subscriptions")
+	subscriptions, err := service.repo.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while listing the webhook subscriptions")
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// This function is responsible to list the latest deliveries of a webhook subscription along with their attempt log
+func ListWebhookDeliveries() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		subscriptionID := ctx.Param(constants.SubscriptionID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to list the deliveries of %v webhook subscription", subscriptionID))
+		var filter models.WebhookDeliveryFilter
+		if err := ctx.ShouldBindQuery(&filter); err == nil {
+			filter.SubscriptionID = subscriptionID
+			deliveries, err := creditCardLimitOfferClient.listWebhookDeliveries(ctx, filter)
+			if err != nil {
+				utils.RespondWithError(ctx, err.Code, err.Message)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, gin.H{"webhook_deliveries": deliveries})
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"Unable to bind the query params": err.Error()})
+		}
+	}
+}
+
+func (service *CreditCardLimitOfferService) listWebhookDeliveries(ctx *gin.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.listWebhookDeliveries")()
+
+	if err := authorize(ctx, operationManageWebhooks, nil); err != nil {
+		return nil, err
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = constants.DefaultPageSize
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to list the deliveries of %v webhook subscription", filter.SubscriptionID))
+	deliveries, err := service.repo.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		utils.RequestLogger(ctx).Info("received error from db layer while listing webhook deliveries")
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// This function is responsible to schedule a webhook delivery, typically a dead-lettered one, to be delivered again
+func RedeliverWebhookDelivery() func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		deliveryID := ctx.Param(constants.DeliveryID)
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received request to redeliver %v webhook delivery", deliveryID))
+		delivery, err := creditCardLimitOfferClient.redeliverWebhookDelivery(ctx, deliveryID)
+		if err != nil {
+			utils.RespondWithError(ctx, err.Code, err.Message)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, delivery)
+	}
+}
+
+func (service *CreditCardLimitOfferService) redeliverWebhookDelivery(ctx *gin.Context, deliveryID string) (models.WebhookDelivery, *limitoffererror.CreditCardError) {
+	defer tracing.StartSpan(ctx, "CreditCardLimitOfferService.redeliverWebhookDelivery")()
+
+	if err := authorize(ctx, operationManageWebhooks, nil); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	utils.RequestLogger(ctx).Info(fmt.Sprintf("calling db layer to redeliver %v webhook delivery", deliveryID))
+	delivery, err := service.repo.RedeliverWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		utils.RequestLogger(ctx).Info(fmt.Sprintf("received error from db layer while redelivering %v webhook delivery", deliveryID))
+		return models.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
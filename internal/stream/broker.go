@@ -0,0 +1,165 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/outbox"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"go.uber.org/zap"
+)
+
+// ErrClosed is returned when subscribing to a broker which was closed by the shutdown
+var ErrClosed = errors.New("event stream is closed")
+
+// Source is the feed of the committed outbox events the broker listens to
+type Source interface {
+	ListenOutboxEvents(ctx context.Context, fn func(models.OutboxEvent)) error
+}
+
+// Subscription receives the events of an account until it is unsubscribed or the broker is closed.
+// Events is closed when the subscriber fell too far behind or the broker is closed.
+type Subscription struct {
+	accountID string
+	events    chan models.OutboxEvent
+}
+
+func (s *Subscription) Events() <-chan models.OutboxEvent {
+	return s.events
+}
+
+// Broker fans the outbox events out to the subscriptions of their account and retains the recent events,
+// so that a stream can resume from the last event it received
+type Broker struct {
+	mu               sync.Mutex
+	retained         []models.OutboxEvent
+	retention        time.Duration
+	retentionSize    int
+	subscriberBuffer int
+	subscriptions    map[*Subscription]struct{}
+	closed           bool
+	now              func() time.Time
+}
+
+func NewBroker(cfg config.Stream) *Broker {
+	return &Broker{
+		retention:        time.Duration(cfg.Retention) * time.Second,
+		retentionSize:    cfg.RetentionSize,
+		subscriberBuffer: cfg.SubscriberBuffer,
+		subscriptions:    map[*Subscription]struct{}{},
+		now:              time.Now,
+	}
+}
+
+// Run feeds the broker from the source until ctx is cancelled, the source is listened again after a failure.
+// The broker is closed on the way out.
+func (b *Broker) Run(ctx context.Context, source Source) {
+	defer b.Close()
+	utils.Logger.Info("starting the limit offer event stream")
+	for attempt := 1; ; attempt++ {
+		err := source.ListenOutboxEvents(ctx, b.Publish)
+		if ctx.Err() != nil {
+			utils.Logger.Info("stopped the limit offer event stream")
+			return
+		}
+		utils.Logger.Error("lost the outbox events feed, listening again", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(outbox.Backoff(time.Second, 30*time.Second, attempt)):
+		}
+	}
+}
+
+// Publish retains the event and sends it to the subscriptions of its account. A subscription whose buffer
+// is full is closed rather than holding the other ones back.
+func (b *Broker) Publish(event models.OutboxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.retained = append(b.retained, event)
+	b.trim()
+
+	for subscription := range b.subscriptions {
+		if subscription.accountID != event.AccountID {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			utils.Logger.Info("closing a slow event stream", zap.String("account_id", subscription.accountID))
+			b.remove(subscription)
+		}
+	}
+}
+
+// Subscribe starts a subscription to the events of the account along with the retained events of the account
+// following lastEventID. found is false when lastEventID is no longer retained, the subscriber may have missed events.
+func (b *Broker) Subscribe(accountID string, lastEventID string) (subscription *Subscription, replay []models.OutboxEvent, found bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, false, ErrClosed
+	}
+
+	b.trim()
+	found = lastEventID == ""
+	for _, event := range b.retained {
+		if found && event.AccountID == accountID {
+			replay = append(replay, event)
+		}
+		if event.EventID == lastEventID {
+			found = true
+		}
+	}
+	if !found {
+		replay = nil
+	}
+
+	subscription = &Subscription{accountID: accountID, events: make(chan models.OutboxEvent, b.subscriberBuffer)}
+	b.subscriptions[subscription] = struct{}{}
+	return subscription, replay, found, nil
+}
+
+// Unsubscribe stops the subscription, it is safe to call after the broker closed it
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(subscription)
+}
+
+// Close ends every subscription and refuses the new ones, the open streams return
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for subscription := range b.subscriptions {
+		b.remove(subscription)
+	}
+}
+
+func (b *Broker) remove(subscription *Subscription) {
+	if _, ok := b.subscriptions[subscription]; ok {
+		delete(b.subscriptions, subscription)
+		close(subscription.events)
+	}
+}
+
+// trim drops the events older than the retention and the oldest events beyond the retention size
+func (b *Broker) trim() {
+	cutoff := b.now().Add(-b.retention)
+	drop := 0
+	for drop < len(b.retained) && (b.retained[drop].OccurredAt.Before(cutoff) || len(b.retained)-drop > b.retentionSize) {
+		drop++
+	}
+	if drop > 0 {
+		b.retained = append([]models.OutboxEvent(nil), b.retained[drop:]...)
+	}
+}
@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	accountID      = "2b4e1e64-624f-4a4e-9911-e0b13f526e10"
+	otherAccountID = "8f0c7a0e-1d55-4a7d-9a5e-0c6f3c6f8d11"
+)
+
+func newEvent(eventID string, accountID string, occurredAt time.Time) models.OutboxEvent {
+	return models.OutboxEvent{
+		EventID:    eventID,
+		EventType:  constants.EventLimitOfferCreated,
+		AccountID:  accountID,
+		Payload:    json.RawMessage(`{"status":"PENDING"}`),
+		OccurredAt: occurredAt,
+	}
+}
+
+func newBroker(now time.Time) *Broker {
+	broker := NewBroker(config.Stream{Retention: 60, RetentionSize: 3, SubscriberBuffer: 2})
+	broker.now = func() time.Time { return now }
+	return broker
+}
+
+func TestBrokerFanOut(t *testing.T) {
+	utils.InitLogClient()
+	now := time.Date(2023, 8, 24, 2, 17, 0, 0, time.UTC)
+	broker := newBroker(now)
+
+	subscription, replay, found, err := broker.Subscribe(accountID, "")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, replay)
+
+	// case 1 : only the events of the account reach the subscription
+	broker.Publish(newEvent("e1", otherAccountID, now))
+	broker.Publish(newEvent("e2", accountID, now))
+	assert.Equal(t, "e2", (<-subscription.Events()).EventID)
+	assert.Len(t, subscription.Events(), 0)
+
+	// case 2 : a subscription falling behind its buffer is closed
+	for _, eventID := range []string{"e3", "e4", "e5"} {
+		broker.Publish(newEvent(eventID, accountID, now))
+	}
+	received := []string{}
+	for event := range subscription.Events() {
+		received = append(received, event.EventID)
+	}
+	assert.Equal(t, []string{"e3", "e4"}, received)
+
+	// case 3 : closing the broker ends the subscriptions and refuses the new ones
+	subscription, _, _, _ = broker.Subscribe(accountID, "")
+	broker.Close()
+	_, open := <-subscription.Events()
+	assert.False(t, open)
+	broker.Unsubscribe(subscription)
+	_, _, _, err = broker.Subscribe(accountID, "")
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestBrokerResume(t *testing.T) {
+	utils.InitLogClient()
+	now := time.Date(2023, 8, 24, 2, 17, 0, 0, time.UTC)
+	broker := newBroker(now)
+	broker.Publish(newEvent("e1", accountID, now.Add(-2*time.Minute)))
+	broker.Publish(newEvent("e2", accountID, now))
+	broker.Publish(newEvent("e3", otherAccountID, now))
+	broker.Publish(newEvent("e4", accountID, now))
+
+	// case 1 : the events of the account after the last event id are replayed
+	_, replay, found, err := broker.Subscribe(accountID, "e2")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Len(t, replay, 1)
+	assert.Equal(t, "e4", replay[0].EventID)
+
+	// case 2 : an event past the retention is no longer found, the client has to resync
+	_, replay, found, _ = broker.Subscribe(accountID, "e1")
+	assert.False(t, found)
+	assert.Empty(t, replay)
+
+	// case 3 : the oldest events are dropped beyond the retention size
+	broker.Publish(newEvent("e5", accountID, now))
+	broker.Publish(newEvent("e6", accountID, now))
+	_, _, found, _ = broker.Subscribe(accountID, "e2")
+	assert.False(t, found)
+	_, replay, found, _ = broker.Subscribe(accountID, "e4")
+	assert.True(t, found)
+	assert.Len(t, replay, 2)
+}
+
+func TestWriteEvent(t *testing.T) {
+	var buffer bytes.Buffer
+	now := time.Date(2023, 8, 24, 2, 17, 0, 0, time.UTC)
+	assert.NoError(t, WriteEvent(&buffer, newEvent("e1", accountID, now)))
+	assert.NoError(t, WriteHeartbeat(&buffer))
+
+	frames := strings.Split(strings.TrimSuffix(buffer.String(), "\n\n"), "\n\n")
+	assert.Len(t, frames, 2)
+	lines := strings.Split(frames[0], "\n")
+	assert.Equal(t, "id: e1", lines[0])
+	assert.Equal(t, "event: "+constants.EventLimitOfferCreated, lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "data: {"))
+	assert.Equal(t, ": heartbeat", frames[1])
+}
+
+// feed is a source publishing its events once, then closing published and waiting for the broker to stop
+type feed struct {
+	events    []models.OutboxEvent
+	published chan struct{}
+}
+
+func (f feed) ListenOutboxEvents(ctx context.Context, fn func(models.OutboxEvent)) error {
+	for _, event := range f.events {
+		fn(event)
+	}
+	close(f.published)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestBrokerLogIsRedacted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	logger, err := utils.NewLogger(config.Logging{Level: "info", Format: constants.JSONLogFormat, File: file,
+		Redaction: []config.RedactionRule{{Field: "account_id", Action: constants.TruncateRedaction, Keep: 8}}})
+	assert.NoError(t, err)
+	previous := utils.Logger
+	utils.Logger = logger
+	t.Cleanup(func() { utils.Logger = previous })
+
+	// the slow stream is closed by the goroutine feeding the broker, its entry carries the account as a field
+	now := time.Now()
+	broker := newBroker(now)
+	_, _, _, err = broker.Subscribe(accountID, "")
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	source := feed{events: []models.OutboxEvent{newEvent("e1", accountID, now), newEvent("e2", accountID, now), newEvent("e3", accountID, now)},
+		published: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		broker.Run(ctx, source)
+	}()
+	<-source.published
+	cancel()
+	<-done
+	assert.NoError(t, logger.Sync())
+
+	written, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Contains(t, string(written), "closing a slow event stream")
+	assert.Contains(t, string(written), `"account_id":"2b4e1e64..."`)
+	assert.NotContains(t, string(written), accountID)
+}
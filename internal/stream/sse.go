@@ -0,0 +1,31 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+)
+
+// WriteEvent writes the event in the server-sent events format, the event id is the Last-Event-ID to resume from
+func WriteEvent(w io.Writer, event models.OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.EventID, event.EventType, data)
+	return err
+}
+
+// WriteControl writes an event without id, which does not move the Last-Event-ID of the client
+func WriteControl(w io.Writer, eventType string, data string) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+	return err
+}
+
+// WriteHeartbeat writes a comment, which keeps the connection and the proxies on the way from timing out
+func WriteHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": heartbeat\n\n")
+	return err
+}
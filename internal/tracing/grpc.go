@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+// StartRPC starts the span of a grpc call as the child of the traceparent in the metadata of the call, if any.
+// fullMethod is of the form /package.service/method.
+func StartRPC(ctx context.Context, fullMethod string, header http.Header, txid string) (context.Context, trace.Span) {
+	parent := propagator.Extract(WithTransactionID(ctx, txid), propagation.HeaderCarrier(header))
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return Start(parent, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)))
+}
+
+// EndRPC ends the span of a grpc call with the status code the call returned
+func EndRPC(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil {
+		span.SetStatus(codes.Error, status.Convert(err).Message())
+	}
+	span.End()
+}
+
+// Header returns the traceparent header of the span in ctx, to be passed on to the caller or to another service
+func Header(ctx context.Context) http.Header {
+	header := http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	return header
+}
@@ -0,0 +1,181 @@
+// Package tracing holds the OpenTelemetry spans of the application: a span per http request and grpc call,
+// continued from the W3C traceparent header of the caller, a span per service method and per sql statement.
+// The transaction id of the request is an attribute of the spans so that they can be found from the logs.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/ankit/project/credit-card-offer-limit"
+
+// TransactionIDKey is the attribute holding the transaction id of the request the span belongs to
+const TransactionIDKey = attribute.Key("transaction.id")
+
+type transactionIDKey struct{}
+
+// propagator reads and writes the traceparent and tracestate headers, it is used even when the spans are not
+// exported so that the trace of the caller goes on through the application
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+func init() {
+	otel.SetTextMapPropagator(propagator)
+}
+
+// Init installs the tracer provider exporting the spans as configured, the returned function flushes
+// the pending spans and has to be called before exiting
+func Init(cfg config.Tracing) (func(context.Context) error, error) {
+	if cfg.Exporter == constants.NoExporter || cfg.Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch cfg.Exporter {
+	case constants.OTLPExporter:
+		options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(context.Background(), options...)
+	case constants.StdoutExporter:
+		var writer io.Writer = os.Stdout
+		if cfg.File != "" {
+			file, openErr := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if openErr != nil {
+				return nil, fmt.Errorf("unable to open the spans file : %w", openErr)
+			}
+			writer, closer = file, file
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(writer))
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the %v span exporter : %w", cfg.Exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}, nil
+}
+
+// Start starts a span as the child of the span in ctx, the transaction id stored in ctx by the middlewares
+// is added to the span
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, options...)
+	if txid, ok := ctx.Value(transactionIDKey{}).(string); ok {
+		span.SetAttributes(TransactionIDKey.String(txid))
+	}
+	return ctx, span
+}
+
+// WithTransactionID returns a copy of ctx carrying the transaction id, the spans started from it are tagged with it
+func WithTransactionID(ctx context.Context, txid string) context.Context {
+	return context.WithValue(ctx, transactionIDKey{}, txid)
+}
+
+// StartSpan starts a span for the work done with the gin context, e.g. a service method, until the returned
+// function is called. The spans started with the context in between, e.g. of the sql statements, are its children.
+func StartSpan(ctx *gin.Context, name string) func() {
+	parent := ctx.Request.Context()
+	spanCtx, span := Start(WithTransactionID(parent, utils.TransactionID(ctx)), name)
+	ctx.Request = ctx.Request.WithContext(spanCtx)
+	return func() {
+		span.End()
+		ctx.Request = ctx.Request.WithContext(parent)
+	}
+}
+
+// Middleware starts the span of every http request as the child of the traceparent header of the request,
+// if any, and returns the traceparent header of the span in the response
+func Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		parent := propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		// the span is named after the route so that the requests of an endpoint are grouped together
+		name := ctx.Request.Method
+		if ctx.FullPath() != "" {
+			name += " " + ctx.FullPath()
+		}
+		spanCtx, span := Start(parent, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPMethod(ctx.Request.Method), semconv.HTTPRoute(ctx.FullPath())))
+		defer span.End()
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		propagator.Inject(spanCtx, propagation.HeaderCarrier(ctx.Writer.Header()))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if txid := utils.TransactionID(ctx); txid != "" {
+			span.SetAttributes(TransactionIDKey.String(txid))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// Transport starts a client span for every request sent through base and passes the traceparent header of
+// the span on to the receiver
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return transport{base: base}
+}
+
+type transport struct{ base http.RoundTripper }
+
+func (t transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx, span := Start(request.Context(), "HTTP "+request.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPMethod(request.Method), semconv.ServerAddress(request.URL.Host)))
+	defer span.End()
+
+	// the request must not be modified by a RoundTripper
+	request = request.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(request.Header))
+	response, err := t.base.RoundTrip(request)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCode(response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, response.Status)
+	}
+	return response, nil
+}
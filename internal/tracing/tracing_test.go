@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	txid        = "9c8b7a6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+)
+
+// recordSpans installs a tracer provider keeping the ended spans in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key string) string {
+	for _, attribute := range span.Attributes() {
+		if string(attribute.Key) == key {
+			return attribute.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/v1/accounts/:account_id", func(ctx *gin.Context) {
+		defer StartSpan(ctx, "CreditCardLimitOfferService.getAccount")()
+		_, span := Start(ctx.Request.Context(), "SELECT")
+		span.End()
+		ctx.Status(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/accounts/1", nil)
+	request.Header.Set("traceparent", traceParent)
+	request.Header.Set(constants.TransactionID, txid)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	query, method, server := spans[0], spans[1], spans[2]
+	assert.Equal(t, "GET /v1/accounts/:account_id", server.Name())
+	assert.Equal(t, "CreditCardLimitOfferService.getAccount", method.Name())
+
+	// the trace of the caller goes on through the spans of the request
+	for _, span := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, txid, attributeValue(span, string(TransactionIDKey)))
+	}
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), method.Parent().SpanID())
+	assert.Equal(t, method.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, "200", attributeValue(server, "http.status_code"))
+
+	// the caller gets the traceparent of the span of the request
+	returned := trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.HeaderCarrier(response.Header())))
+	assert.Equal(t, server.SpanContext().SpanID(), returned.SpanID())
+}
+
+func TestTransport(t *testing.T) {
+	recorder := recordSpans(t)
+	var received string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	ctx, parent := Start(context.Background(), "deliver")
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, receiver.URL, nil)
+	client := &http.Client{Transport: Transport(http.DefaultTransport)}
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "HTTP POST", spans[0].Name())
+	assert.Equal(t, "00-"+spans[0].SpanContext().TraceID().String()+"-"+spans[0].SpanContext().SpanID().String()+"-01", received)
+	assert.Equal(t, "Error", spans[0].Status().Code.String())
+	// the request of the caller is left as it was
+	assert.Empty(t, request.Header.Get("traceparent"))
+}
+
+func TestStdoutExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Init(config.Tracing{Exporter: constants.StdoutExporter, File: file, ServiceName: "credit-card-offer-limit", SampleRatio: 1})
+	assert.NoError(t, err)
+
+	_, span := Start(WithTransactionID(context.Background(), txid), "CreditCardLimitOfferService.createAccount")
+	span.End()
+	assert.NoError(t, shutdown(context.Background()))
+
+	written, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Contains(t, string(written), `"Name":"CreditCardLimitOfferService.createAccount"`)
+	assert.Contains(t, string(written), txid)
+
+	_, err = Init(config.Tracing{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevel is the level of the logger set by InitLogger, which SetLogLevel changes while the application runs
+var logLevel = zap.NewAtomicLevel()
+
+// InitLogger replaces the development logger the application starts with by the configured one, the logged
+// bodies are redacted with its rules from then on
+func InitLogger(cfg config.Logging) error {
+	r, err := NewRedactor(cfg.Redaction)
+	if err != nil {
+		return err
+	}
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	logger, err := newLogger(cfg, r, atomicLevel)
+	if err != nil {
+		return err
+	}
+	Logger, redactor, logLevel = logger, r, atomicLevel
+	return nil
+}
+
+// SetLogLevel changes the level of the logger set by InitLogger, e.g. once the configuration is reloaded
+func SetLogLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logLevel.SetLevel(parsed)
+	return nil
+}
+
+// NewLogger builds the logger writing the entries of the level and above in the format to the file,
+// or to the standard error, sampling the repeated entries and redacting the personal data
+func NewLogger(cfg config.Logging) (*zap.Logger, error) {
+	r, err := NewRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	return newLogger(cfg, r, level)
+}
+
+func newLogger(cfg config.Logging, r *Redactor, level zapcore.LevelEnabler) (*zap.Logger, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	switch cfg.Format {
+	case constants.ConsoleLogFormat:
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case constants.JSONLogFormat, "":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", cfg.Format)
+	}
+
+	output := zapcore.Lock(os.Stderr)
+	if cfg.File != "" {
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the log file : %w", err)
+		}
+		output = zapcore.Lock(file)
+	}
+
+	// the sampler wraps the redaction so that the sampled out entries are not redacted in vain
+	core := r.Wrap(zapcore.NewCore(encoder, output, level))
+	if cfg.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.SamplingInitial, cfg.SamplingThereafter)
+	}
+	return zap.New(core, zap.AddCaller(), zap.ErrorOutput(output)), nil
+}
+
+// TransactionID returns the transaction id of the request
+func TransactionID(ctx *gin.Context) string {
+	if txid := ctx.GetString(constants.TransactionID); txid != "" {
+		return txid
+	}
+	return ctx.Request.Header.Get(constants.TransactionID)
+}
+
+// RequestLogger returns the logger of the request, its entries carry the transaction id, the trace, the route,
+// the account and the principal of the request as far as they are known
+func RequestLogger(ctx *gin.Context) *zap.Logger {
+	fields := []zap.Field{zap.String("transaction_id", TransactionID(ctx))}
+	if spanContext := trace.SpanContextFromContext(ctx.Request.Context()); spanContext.IsValid() {
+		fields = append(fields, zap.String("trace_id", spanContext.TraceID().String()))
+	}
+	if route := ctx.FullPath(); route != "" {
+		fields = append(fields, zap.String("route", route))
+	} else if route := ctx.GetString(constants.LogRoute); route != "" {
+		fields = append(fields, zap.String("route", route))
+	}
+	if accountID := ctx.GetString(constants.LogAccountID); accountID != "" {
+		fields = append(fields, zap.String("account_id", accountID))
+	} else if accountID := ctx.Param(constants.AccountID); accountID != "" {
+		fields = append(fields, zap.String("account_id", accountID))
+	}
+	if principal, ok := GetPrincipal(ctx); ok {
+		fields = append(fields, zap.String("principal", principal.Subject), zap.String("client_id", principal.ClientID))
+	}
+	return Logger.With(fields...)
+}
+
+// SetLogAccountID adds the account the request works on to the entries of the logger of the request
+func SetLogAccountID(ctx *gin.Context, accountID string) {
+	ctx.Set(constants.LogAccountID, accountID)
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	logger, err := NewLogger(config.Logging{Level: "info", Format: constants.JSONLogFormat, File: file, SamplingInitial: 2})
+	assert.NoError(t, err)
+	Logger = logger
+
+	router := gin.New()
+	router.GET("/v1/accounts/:account_id", func(ctx *gin.Context) {
+		ctx.Set(constants.Principal, models.Principal{ClientID: "back-office", Subject: "jane"})
+		RequestLogger(ctx).Debug("not logged below the level")
+		for i := 0; i < 3; i++ {
+			RequestLogger(ctx).Info("fetched the account")
+		}
+	})
+	request := httptest.NewRequest(http.MethodGet, "/v1/accounts/0b9c3a3e-5b5b-4f4e-9c55-5d7c2d6c1f0e", nil)
+	request.Header.Set(constants.TransactionID, "9c8b7a6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	assert.NoError(t, Logger.Sync())
+
+	written, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	// the third entry is dropped by the sampling
+	assert.Len(t, lines, 2)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "fetched the account", entry["msg"])
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "9c8b7a6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", entry["transaction_id"])
+	assert.Equal(t, "/v1/accounts/:account_id", entry["route"])
+	assert.Equal(t, "0b9c3a3e-5b5b-4f4e-9c55-5d7c2d6c1f0e", entry["account_id"])
+	assert.Equal(t, "jane", entry["principal"])
+	assert.Equal(t, "back-office", entry["client_id"])
+
+	_, err = NewLogger(config.Logging{Level: "verbose"})
+	assert.Error(t, err)
+}
+
+func TestSetLogLevel(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, InitLogger(config.Logging{Level: "info", Format: constants.JSONLogFormat, File: file}))
+	defer InitLogClient()
+
+	Logger.Debug("not logged below the level")
+	assert.NoError(t, SetLogLevel("debug"))
+	Logger.Debug("logged once the level is lowered")
+	assert.Error(t, SetLogLevel("verbose"))
+	assert.NoError(t, Logger.Sync())
+
+	written, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "logged once the level is lowered")
+}
@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces the dropped values in the messages and in the text of the fields
+const Redacted = "[REDACTED]"
+
+// the values of the context fields shorter than this are not redacted from the messages, they would match
+// unrelated text
+const minRedactedValueLength = 4
+
+// redactor holds the rules the log and the logged bodies are redacted with
+var redactor = &Redactor{}
+
+// Redactor removes the personal data from the log entries and the logged bodies as the rules say
+type Redactor struct {
+	fields   map[string]config.RedactionRule
+	patterns []redactionPattern
+}
+
+type redactionPattern struct {
+	expression *regexp.Regexp
+	rule       config.RedactionRule
+}
+
+// redactedValue is a value of a redacted field, replaced in the messages by its redacted form
+type redactedValue struct {
+	value       string
+	replacement string
+}
+
+// NewRedactor compiles the rules, the field names are matched regardless of the case
+func NewRedactor(rules []config.RedactionRule) (*Redactor, error) {
+	r := &Redactor{fields: make(map[string]config.RedactionRule)}
+	for _, rule := range rules {
+		if rule.Field != "" {
+			r.fields[strings.ToLower(rule.Field)] = rule
+			continue
+		}
+		expression, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q : %w", rule.Pattern, err)
+		}
+		r.patterns = append(r.patterns, redactionPattern{expression: expression, rule: rule})
+	}
+	return r, nil
+}
+
+// Wrap returns the core writing the entries of core once they are redacted
+func (r *Redactor) Wrap(core zapcore.Core) zapcore.Core {
+	if len(r.fields) == 0 && len(r.patterns) == 0 {
+		return core
+	}
+	return &redactingCore{Core: core, redactor: r}
+}
+
+// RedactBody returns the json body with the keys named by the field rules redacted and the patterns redacted from
+// its strings, a body that is not json is left out as its content can not be redacted
+func RedactBody(body []byte) string {
+	return redactor.RedactBody(body)
+}
+
+func (r *Redactor) RedactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return fmt.Sprintf("[%v bytes omitted]", len(body))
+	}
+	redacted, err := json.Marshal(r.redactJSON(value))
+	if err != nil {
+		return fmt.Sprintf("[%v bytes omitted]", len(body))
+	}
+	return string(redacted)
+}
+
+func (r *Redactor) redactJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			rule, ok := r.fields[strings.ToLower(key)]
+			if !ok {
+				value[key] = r.redactJSON(nested)
+				continue
+			}
+			if rule.Action == constants.DropRedaction {
+				delete(value, key)
+				continue
+			}
+			value[key] = redact(rule, fmt.Sprint(nested))
+		}
+		return value
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = r.redactJSON(nested)
+		}
+		return value
+	case string:
+		return r.redactText(value)
+	default:
+		return value
+	}
+}
+
+// redactText redacts the text matching the patterns
+func (r *Redactor) redactText(text string) string {
+	for _, pattern := range r.patterns {
+		text = pattern.expression.ReplaceAllStringFunc(text, func(match string) string {
+			return redact(pattern.rule, match)
+		})
+	}
+	return text
+}
+
+// redactFields returns the fields with the ones named by the rules redacted, dropped ones removed, and the
+// values they held
+func (r *Redactor) redactFields(fields []zapcore.Field) ([]zapcore.Field, []redactedValue) {
+	var (
+		redacted = make([]zapcore.Field, 0, len(fields))
+		values   []redactedValue
+	)
+	for _, field := range fields {
+		if rule, ok := r.fields[strings.ToLower(field.Key)]; ok {
+			value := fieldValue(field)
+			replacement := redact(rule, value)
+			values = append(values, redactedValue{value: value, replacement: replacement})
+			if rule.Action != constants.DropRedaction {
+				redacted = append(redacted, zap.String(field.Key, replacement))
+			}
+			continue
+		}
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = r.redactText(field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok && err != nil {
+				field = zap.String(field.Key, r.redactText(err.Error()))
+			}
+		case zapcore.StringerType, zapcore.ByteStringType:
+			field = zap.String(field.Key, r.redactText(fieldValue(field)))
+		case zapcore.ReflectType:
+			// the objects, e.g. the events, are redacted as the json bodies are
+			if encoded, err := json.Marshal(field.Interface); err == nil {
+				field = zap.Reflect(field.Key, json.RawMessage(r.RedactBody(encoded)))
+			}
+		}
+		redacted = append(redacted, field)
+	}
+	return redacted, values
+}
+
+// redactMessage redacts the values of the redacted fields and the patterns from the message
+func (r *Redactor) redactMessage(message string, values []redactedValue) string {
+	for _, value := range values {
+		if len(value.value) >= minRedactedValueLength {
+			message = strings.ReplaceAll(message, value.value, value.replacement)
+		}
+	}
+	return r.redactText(message)
+}
+
+// fieldValue returns the text of the value of the field
+func fieldValue(field zapcore.Field) string {
+	encoder := zapcore.NewMapObjectEncoder()
+	field.AddTo(encoder)
+	switch value := encoder.Fields[field.Key].(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// redact applies the action of the rule to the value
+func redact(rule config.RedactionRule, value string) string {
+	characters := []rune(value)
+	switch rule.Action {
+	case constants.MaskRedaction:
+		if rule.Keep >= len(characters) {
+			return strings.Repeat("*", len(characters))
+		}
+		return strings.Repeat("*", len(characters)-rule.Keep) + string(characters[len(characters)-rule.Keep:])
+	case constants.TruncateRedaction:
+		if rule.Keep >= len(characters) {
+			return value
+		}
+		return string(characters[:rule.Keep]) + "..."
+	default:
+		return Redacted
+	}
+}
+
+// redactingCore redacts the entries and the context fields before they reach the core it wraps
+type redactingCore struct {
+	zapcore.Core
+	redactor *Redactor
+	// the values of the redacted context fields, they are redacted from the messages as well
+	values []redactedValue
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	fields, values := c.redactor.redactFields(fields)
+	return &redactingCore{
+		Core:     c.Core.With(fields),
+		redactor: c.redactor,
+		values:   append(values, c.values...),
+	}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	fields, values := c.redactor.redactFields(fields)
+	entry.Message = c.redactor.redactMessage(entry.Message, append(values, c.values...))
+	return c.Core.Write(entry, fields)
+}
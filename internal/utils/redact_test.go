@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+const (
+	accountID  = "0b9c3a3e-5b5b-4f4e-9c55-5d7c2d6c1f0e"
+	customerID = "7d1e2f3a-4b5c-4d6e-8f70-8192a3b4c5d6"
+	email      = "jane.doe@example.com"
+)
+
+var redactionRules = []config.RedactionRule{
+	{Field: "customer_id", Action: constants.MaskRedaction, Keep: 4},
+	{Field: "account_id", Action: constants.TruncateRedaction, Keep: 8},
+	{Field: "name", Action: constants.DropRedaction},
+	{Field: "email", Action: constants.DropRedaction},
+	{Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Action: constants.DropRedaction},
+}
+
+func TestRedaction(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	logger, err := NewLogger(config.Logging{Level: "info", Format: constants.JSONLogFormat, File: file, Redaction: redactionRules})
+	assert.NoError(t, err)
+
+	account := map[string]interface{}{"account_id": accountID, "customer_id": customerID, "email": email, "locale": "en"}
+	requestLogger := logger.With(zap.String("account_id", accountID), zap.String("transaction_id", "9c8b7a6d"))
+	requestLogger.Info(fmt.Sprintf("calling db layer for getting %v account", accountID),
+		zap.String("customer_id", customerID),
+		zap.String("name", "Jane Doe"),
+		zap.String("email", email),
+		zap.Error(errors.New("duplicate key value violates unique constraint, Key (email)=("+email+")")),
+		zap.Any("account", account))
+	requestLogger.Info("notification to " + email)
+	assert.NoError(t, logger.Sync())
+
+	written, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	// the sensitive values never reach the log
+	for _, sensitive := range []string{accountID, customerID, email, "Jane Doe"} {
+		assert.NotContains(t, string(written), sensitive)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	assert.Len(t, lines, 2)
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "calling db layer for getting 0b9c3a3e... account", entry["msg"])
+	assert.Equal(t, "0b9c3a3e...", entry["account_id"])
+	assert.Equal(t, strings.Repeat("*", 32)+"c5d6", entry["customer_id"])
+	assert.Equal(t, "9c8b7a6d", entry["transaction_id"])
+	assert.NotContains(t, entry, "name")
+	assert.NotContains(t, entry, "email")
+	assert.Equal(t, "duplicate key value violates unique constraint, Key (email)=([REDACTED])", entry["error"])
+	assert.Equal(t, map[string]interface{}{"account_id": "0b9c3a3e...", "customer_id": strings.Repeat("*", 32) + "c5d6", "locale": "en"}, entry["account"])
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "notification to [REDACTED]", entry["msg"])
+}
+
+func TestRedactBody(t *testing.T) {
+	r, err := NewRedactor(redactionRules)
+	assert.NoError(t, err)
+
+	body := `{"accounts":[{"account_id":"` + accountID + `","email":"` + email + `","account_limit":5000}],"note":"reach ` + email + `"}`
+	assert.Equal(t, `{"accounts":[{"account_id":"0b9c3a3e...","account_limit":5000}],"note":"reach [REDACTED]"}`, r.RedactBody([]byte(body)))
+	// the bodies that are not json are left out
+	assert.Equal(t, "[27 bytes omitted]", r.RedactBody([]byte("account_id,email\n1,a@b.com\n")))
+	assert.Empty(t, r.RedactBody(nil))
+
+	_, err = NewRedactor([]config.RedactionRule{{Pattern: "(", Action: constants.DropRedaction}})
+	assert.Error(t, err)
+}
@@ -1,9 +1,14 @@
 package utils
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
 	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -13,7 +18,10 @@ func InitLogClient() {
 	Logger, _ = zap.NewDevelopment()
 }
 
+// RespondWithError aborts the request with the error, the message is also added to the errors of the context
+// so that the middlewares can tell why the request was rejected
 func RespondWithError(c *gin.Context, statusCode int, message string) {
+	_ = c.Error(errors.New(message))
 
 	c.AbortWithStatusJSON(statusCode, limitoffererror.CreditCardError{
 		Trace:   c.Request.Header.Get(constants.TransactionID),
@@ -21,3 +29,29 @@ func RespondWithError(c *gin.Context, statusCode int, message string) {
 		Message: message,
 	})
 }
+
+// This function creates a gin context for the work which does not originate from an http request, e.g. cli commands.
+// A fresh transaction id is set so that the logs of the work can be correlated, and the work is done as the system
+// principal which holds every role.
+func NewBackgroundContext() *gin.Context {
+	request, _ := http.NewRequest(http.MethodGet, constants.ForwardSlash, nil)
+	request.Header.Set(constants.TransactionID, uuid.New().String())
+	ctx := &gin.Context{Request: request}
+	ctx.Set(constants.Principal, models.Principal{
+		ClientID:   constants.SystemClientID,
+		Subject:    constants.SystemClientID,
+		Roles:      []string{constants.RoleCustomer, constants.RoleRiskOfficer, constants.RoleAdmin},
+		AuthMethod: constants.AuthMethodSystem,
+	})
+	return ctx
+}
+
+// This function returns the authenticated caller of the request, ok is false when the request was not authenticated
+func GetPrincipal(c *gin.Context) (principal models.Principal, ok bool) {
+	value, exists := c.Get(constants.Principal)
+	if !exists {
+		return models.Principal{}, false
+	}
+	principal, ok = value.(models.Principal)
+	return principal, ok
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/limitoffererror"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/ankit/project/credit-card-offer-limit/internal/outbox"
+	"github.com/ankit/project/credit-card-offer-limit/internal/tracing"
+	"github.com/ankit/project/credit-card-offer-limit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Repository is the part of the db layer the webhook deliveries go through
+type Repository interface {
+	EnqueueWebhookDeliveries(*gin.Context, models.OutboxEvent, []byte) *limitoffererror.CreditCardError
+	DispatchWebhookDeliveries(*gin.Context, int, int, time.Duration, func(models.WebhookDelivery, models.WebhookSubscription) models.WebhookDeliveryAttempt, func(int) time.Duration) (int, *limitoffererror.CreditCardError)
+}
+
+// SubscriptionPublisher is the outbox publisher fanning the events out to the deliveries of the subscriptions
+type SubscriptionPublisher struct {
+	repo Repository
+}
+
+func NewSubscriptionPublisher(repo Repository) SubscriptionPublisher {
+	return SubscriptionPublisher{repo: repo}
+}
+
+func (p SubscriptionPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ginCtx := utils.NewBackgroundContext()
+	ginCtx.Request = ginCtx.Request.WithContext(ctx)
+	if err := p.repo.EnqueueWebhookDeliveries(ginCtx, event, body); err != nil {
+		return fmt.Errorf("unable to enqueue the webhook deliveries : %v", err.Message)
+	}
+	return nil
+}
+
+// Dispatcher posts the due deliveries to their subscriptions
+type Dispatcher struct {
+	repo         Repository
+	client       *http.Client
+	batchSize    int
+	maxAttempts  int
+	lease        time.Duration
+	pollInterval time.Duration
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	now          func() time.Time
+}
+
+func NewDispatcher(cfg config.Webhooks, repo Repository) *Dispatcher {
+	return &Dispatcher{
+		repo:         repo,
+		client:       &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second, Transport: tracing.Transport(http.DefaultTransport)},
+		batchSize:    cfg.BatchSize,
+		maxAttempts:  cfg.MaxAttempts,
+		lease:        time.Duration(cfg.LeaseDuration) * time.Second,
+		pollInterval: time.Duration(cfg.PollInterval) * time.Millisecond,
+		retryBackoff: time.Duration(cfg.RetryBackoff) * time.Second,
+		maxBackoff:   time.Duration(cfg.MaxRetryBackoff) * time.Second,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run dispatches the deliveries until ctx is cancelled. A full batch is followed by the next one right away,
+// otherwise the dispatcher waits for the poll interval.
+func (d *Dispatcher) Run(ctx context.Context) {
+	utils.Logger.Info("starting the webhook dispatcher")
+	for {
+		ginCtx := utils.NewBackgroundContext()
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+		deliver := func(delivery models.WebhookDelivery, subscription models.WebhookSubscription) models.WebhookDeliveryAttempt {
+			return d.deliver(ctx, delivery, subscription)
+		}
+		retryAfter := func(attempts int) time.Duration {
+			return outbox.Backoff(d.retryBackoff, d.maxBackoff, attempts)
+		}
+
+		attempted, err := d.repo.DispatchWebhookDeliveries(ginCtx, d.batchSize, d.maxAttempts, d.lease, deliver, retryAfter)
+		if err != nil {
+			utils.Logger.Error("unable to dispatch the webhook deliveries", zap.String("transaction_id", err.Trace), zap.String("error", err.Message))
+		}
+		if err == nil && attempted == d.batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			utils.Logger.Info("stopped the webhook dispatcher")
+			return
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// deliver posts the signed payload of the delivery to the subscription, any response other than 2xx is a failure
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery, subscription models.WebhookSubscription) models.WebhookDeliveryAttempt {
+	attempt := models.WebhookDeliveryAttempt{DeliveryID: delivery.ID, AttemptedAt: d.now()}
+	failed := func(err error) models.WebhookDeliveryAttempt {
+		message := err.Error()
+		attempt.Error = &message
+		attempt.DurationMS = d.now().Sub(attempt.AttemptedAt).Milliseconds()
+		return attempt
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return failed(err)
+	}
+	request.Header.Set(constants.ContentType, constants.ApplicationJSON)
+	request.Header.Set(constants.EventIDHeader, delivery.EventID)
+	request.Header.Set(constants.EventTypeHeader, delivery.EventType)
+	request.Header.Set(constants.DeliveryIDHeader, delivery.ID)
+	request.Header.Set(constants.SignatureHeader, Sign(subscription.Secret, attempt.AttemptedAt, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return failed(err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+
+	statusCode := response.StatusCode
+	attempt.StatusCode = &statusCode
+	if statusCode < 200 || statusCode > 299 {
+		return failed(fmt.Errorf("subscription responded with %v", response.Status))
+	}
+	attempt.DurationMS = d.now().Sub(attempt.AttemptedAt).Milliseconds()
+	return attempt
+}
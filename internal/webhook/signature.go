@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+)
+
+// ErrInvalidSignature is returned by Verify when the signature header does not match the body
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// NewSecret generates the secret signing the deliveries of a subscription
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return constants.EmptyString, err
+	}
+	return constants.WebhookSecretPrefix + hex.EncodeToString(secret), nil
+}
+
+// Sign returns the value of the signature header of a delivery: the unix timestamp of the attempt and the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret, e.g. t=1692843420,v1=5257a869...
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac(secret, unix, body))
+}
+
+// Verify checks the signature header of a delivery the way a receiver should, deliveries signed more than
+// tolerance away from now are refused so that a captured delivery can not be replayed later
+func Verify(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || signature == constants.EmptyString {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp is outside the tolerance", ErrInvalidSignature)
+	}
+
+	decoded, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, mac(secret, unix, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret string, unix string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(unix))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankit/project/credit-card-offer-limit/internal/config"
+	"github.com/ankit/project/credit-card-offer-limit/internal/constants"
+	"github.com/ankit/project/credit-card-offer-limit/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	secret, err := NewSecret()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, constants.WebhookSecretPrefix))
+
+	body := []byte(`{"event_type":"limit_offer.created"}`)
+	signedAt := time.Date(2023, 8, 24, 2, 17, 0, 0, time.UTC)
+	header := Sign(secret, signedAt, body)
+	assert.True(t, strings.HasPrefix(header, "t=1692843420,v1="))
+
+	// case 1 : the untouched body is accepted within the tolerance
+	assert.NoError(t, Verify(secret, header, body, signedAt.Add(time.Minute), 5*time.Minute))
+
+	// case 2 : a tampered body, another secret or a garbled header are refused
+	assert.ErrorIs(t, Verify(secret, header, []byte(`{"event_type":"limit_offer.accepted"}`), signedAt, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify(constants.WebhookSecretPrefix+"other", header, body, signedAt, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify(secret, "v1=abc", body, signedAt, 5*time.Minute), ErrInvalidSignature)
+
+	// case 3 : a replay outside the tolerance is refused
+	assert.ErrorIs(t, Verify(secret, header, body, signedAt.Add(time.Hour), 5*time.Minute), ErrInvalidSignature)
+}
+
+func TestDeliver(t *testing.T) {
+	secret, _ := NewSecret()
+	delivery := models.WebhookDelivery{
+		ID:        "5c0e7a86-06a4-4d8e-9f0e-4f8c1e9c2f41",
+		EventID:   "0b9c3a3e-5b5b-4f4e-9c55-5d7c2d6c1f0e",
+		EventType: constants.EventLimitOfferCreated,
+		Payload:   json.RawMessage(`{"event_type":"limit_offer.created","data":{"status":"PENDING"}}`),
+	}
+
+	status := http.StatusOK
+	var verifyErr error
+	var header http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		header = r.Header
+		verifyErr = Verify(secret, r.Header.Get(constants.SignatureHeader), body, time.Now(), time.Minute)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+	subscription := models.WebhookSubscription{URL: receiver.URL, Secret: secret}
+	dispatcher := NewDispatcher(config.Webhooks{Timeout: 1}, nil)
+
+	// case 1 : the receiver verifies the signature and acknowledges the delivery
+	attempt := dispatcher.deliver(context.Background(), delivery, subscription)
+	assert.Nil(t, attempt.Error)
+	assert.Equal(t, http.StatusOK, *attempt.StatusCode)
+	assert.NoError(t, verifyErr)
+	assert.Equal(t, delivery.ID, header.Get(constants.DeliveryIDHeader))
+	assert.Equal(t, delivery.EventID, header.Get(constants.EventIDHeader))
+	assert.Equal(t, constants.EventLimitOfferCreated, header.Get(constants.EventTypeHeader))
+
+	// case 2 : a non 2xx response is a failed attempt along with its status code
+	status = http.StatusInternalServerError
+	attempt = dispatcher.deliver(context.Background(), delivery, subscription)
+	assert.NotNil(t, attempt.Error)
+	assert.Equal(t, http.StatusInternalServerError, *attempt.StatusCode)
+
+	// case 3 : an unreachable subscription is a failed attempt without status code
+	receiver.Close()
+	attempt = dispatcher.deliver(context.Background(), delivery, subscription)
+	assert.NotNil(t, attempt.Error)
+	assert.Nil(t, attempt.StatusCode)
+}
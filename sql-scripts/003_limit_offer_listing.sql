@@ -0,0 +1,14 @@
+ALTER TABLE public.limit_offer
+    ADD COLUMN IF NOT EXISTS created_at timestamp with time zone NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS limit_offer_account_created_at_idx
+    ON public.limit_offer (account_id, created_at, id);
+
+CREATE INDEX IF NOT EXISTS limit_offer_account_activation_time_idx
+    ON public.limit_offer (account_id, offer_activation_time, id);
+
+CREATE INDEX IF NOT EXISTS limit_offer_account_expiry_time_idx
+    ON public.limit_offer (account_id, offer_expiry_time, id);
+
+CREATE INDEX IF NOT EXISTS limit_offer_account_limit_type_status_idx
+    ON public.limit_offer (account_id, limit_type, status);
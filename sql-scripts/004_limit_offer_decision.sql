@@ -0,0 +1,5 @@
+ALTER TABLE public.limit_offer
+    ADD COLUMN IF NOT EXISTS updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS decided_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS decided_by character varying COLLATE pg_catalog."default",
+    ADD COLUMN IF NOT EXISTS decision_channel character varying COLLATE pg_catalog."default";
@@ -0,0 +1,13 @@
+ALTER TABLE public.limit_offer
+    ADD COLUMN IF NOT EXISTS superseded_by character varying COLLATE pg_catalog."default";
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'limit_offer_superseded_by_fkey') THEN
+        ALTER TABLE public.limit_offer
+            ADD CONSTRAINT limit_offer_superseded_by_fkey FOREIGN KEY (superseded_by)
+            REFERENCES public.limit_offer (id) MATCH SIMPLE
+            ON UPDATE NO ACTION
+            ON DELETE NO ACTION;
+    END IF;
+END $$;
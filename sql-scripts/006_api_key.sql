@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS public.api_key
+(
+    key_sha256 character varying COLLATE pg_catalog."default" NOT NULL,
+    client_id character varying COLLATE pg_catalog."default" NOT NULL,
+    customer_id character varying COLLATE pg_catalog."default",
+    scopes character varying COLLATE pg_catalog."default" NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    revoked_at timestamp with time zone,
+    CONSTRAINT api_key_pkey PRIMARY KEY (key_sha256)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE public.api_key
+    ADD COLUMN IF NOT EXISTS roles character varying COLLATE pg_catalog."default" NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS account_customer_id_idx
+    ON public.account (customer_id);
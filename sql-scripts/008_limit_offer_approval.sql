@@ -0,0 +1,4 @@
+ALTER TABLE public.limit_offer
+    ADD COLUMN IF NOT EXISTS created_by character varying COLLATE pg_catalog."default",
+    ADD COLUMN IF NOT EXISTS reviewed_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS reviewed_by character varying COLLATE pg_catalog."default";
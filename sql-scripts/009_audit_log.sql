@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS public.audit_log
+(
+    seq bigserial NOT NULL,
+    transaction_id character varying COLLATE pg_catalog."default" NOT NULL,
+    client_id character varying COLLATE pg_catalog."default" NOT NULL,
+    subject character varying COLLATE pg_catalog."default" NOT NULL,
+    action character varying COLLATE pg_catalog."default" NOT NULL,
+    entity_type character varying COLLATE pg_catalog."default" NOT NULL,
+    entity_id character varying COLLATE pg_catalog."default" NOT NULL,
+    -- json rather than jsonb keeps the snapshots byte for byte as they were hashed
+    before json,
+    after json,
+    created_at timestamp with time zone NOT NULL,
+    prev_hash character varying COLLATE pg_catalog."default" NOT NULL,
+    hash character varying COLLATE pg_catalog."default" NOT NULL,
+    CONSTRAINT audit_log_pkey PRIMARY KEY (seq)
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx
+    ON public.audit_log (entity_type, entity_id, seq);
+
+CREATE INDEX IF NOT EXISTS audit_log_transaction_id_idx
+    ON public.audit_log (transaction_id);
+
+-- the audit log is append only
+CREATE OR REPLACE FUNCTION public.audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append only';
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON public.audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON public.audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION public.audit_log_append_only();
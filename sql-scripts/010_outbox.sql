@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS public.outbox
+(
+    id bigserial NOT NULL,
+    event_id character varying COLLATE pg_catalog."default" NOT NULL,
+    event_type character varying COLLATE pg_catalog."default" NOT NULL,
+    account_id character varying COLLATE pg_catalog."default" NOT NULL,
+    aggregate_id character varying COLLATE pg_catalog."default" NOT NULL,
+    payload json NOT NULL,
+    occurred_at timestamp with time zone NOT NULL DEFAULT now(),
+    published_at timestamp with time zone,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_error character varying COLLATE pg_catalog."default",
+    CONSTRAINT outbox_pkey PRIMARY KEY (id),
+    CONSTRAINT outbox_event_id_key UNIQUE (event_id)
+);
+
+-- the relay only looks at the events which are not published yet
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx
+    ON public.outbox (account_id, id) WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS limit_offer_status_expiry_time_idx
+    ON public.limit_offer (status, offer_expiry_time);
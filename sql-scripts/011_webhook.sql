@@ -0,0 +1,53 @@
+CREATE TABLE IF NOT EXISTS public.webhook_subscription
+(
+    id character varying COLLATE pg_catalog."default" NOT NULL,
+    url character varying COLLATE pg_catalog."default" NOT NULL,
+    -- space separated event types, empty subscribes to every event
+    event_types character varying COLLATE pg_catalog."default" NOT NULL DEFAULT '',
+    -- the deliveries are signed with the secret, so it is kept in clear
+    secret character varying COLLATE pg_catalog."default" NOT NULL,
+    created_by character varying COLLATE pg_catalog."default" NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_subscription_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS public.webhook_delivery
+(
+    id character varying COLLATE pg_catalog."default" NOT NULL,
+    subscription_id character varying COLLATE pg_catalog."default" NOT NULL,
+    event_id character varying COLLATE pg_catalog."default" NOT NULL,
+    event_type character varying COLLATE pg_catalog."default" NOT NULL,
+    -- json rather than jsonb keeps the body byte for byte as it is signed
+    payload json NOT NULL,
+    status character varying COLLATE pg_catalog."default" NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_error character varying COLLATE pg_catalog."default",
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    delivered_at timestamp with time zone,
+    CONSTRAINT webhook_delivery_pkey PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_subscription_id_fkey FOREIGN KEY (subscription_id)
+        REFERENCES public.webhook_subscription (id),
+    -- an event relayed again by the outbox is not delivered twice to the same subscription
+    CONSTRAINT webhook_delivery_subscription_event_key UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx
+    ON public.webhook_delivery (next_attempt_at) WHERE status = 'PENDING';
+
+CREATE TABLE IF NOT EXISTS public.webhook_delivery_attempt
+(
+    id bigserial NOT NULL,
+    delivery_id character varying COLLATE pg_catalog."default" NOT NULL,
+    attempt integer NOT NULL,
+    attempted_at timestamp with time zone NOT NULL,
+    status_code integer,
+    error character varying COLLATE pg_catalog."default",
+    duration_ms bigint NOT NULL,
+    CONSTRAINT webhook_delivery_attempt_pkey PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_attempt_delivery_id_fkey FOREIGN KEY (delivery_id)
+        REFERENCES public.webhook_delivery (id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_attempt_delivery_id_idx
+    ON public.webhook_delivery_attempt (delivery_id, id);
@@ -0,0 +1,25 @@
+-- contact details of the customer for the notifications, accounts without email are not notified
+ALTER TABLE public.account ADD COLUMN IF NOT EXISTS email character varying COLLATE pg_catalog."default";
+ALTER TABLE public.account ADD COLUMN IF NOT EXISTS locale character varying COLLATE pg_catalog."default" NOT NULL DEFAULT 'en';
+
+CREATE TABLE IF NOT EXISTS public.notification
+(
+    id bigserial NOT NULL,
+    -- a notification is only ever queued once per key, e.g. offer_expiring:<offer id>:3, whichever replica queues it
+    dedup_key character varying COLLATE pg_catalog."default" NOT NULL,
+    kind character varying COLLATE pg_catalog."default" NOT NULL,
+    account_id character varying COLLATE pg_catalog."default" NOT NULL,
+    limit_offer_id character varying COLLATE pg_catalog."default" NOT NULL,
+    days_left integer,
+    status character varying COLLATE pg_catalog."default" NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_error character varying COLLATE pg_catalog."default",
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    sent_at timestamp with time zone,
+    CONSTRAINT notification_pkey PRIMARY KEY (id),
+    CONSTRAINT notification_dedup_key_key UNIQUE (dedup_key)
+);
+
+CREATE INDEX IF NOT EXISTS notification_due_idx
+    ON public.notification (next_attempt_at) WHERE status = 'PENDING';
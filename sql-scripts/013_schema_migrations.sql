@@ -0,0 +1,13 @@
+-- the scripts applied to the database, the readiness probe fails until the one the application expects is recorded.
+-- Every script from this one on records its own version last.
+CREATE TABLE IF NOT EXISTS public.schema_migrations
+(
+    version character varying COLLATE pg_catalog."default" NOT NULL,
+    applied_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT schema_migrations_pkey PRIMARY KEY (version)
+);
+
+-- the scripts are applied in order, so the earlier ones are in place as well
+INSERT INTO public.schema_migrations (version)
+VALUES ('001'), ('002'), ('003'), ('004'), ('005'), ('006'), ('007'), ('008'), ('009'), ('010'), ('011'), ('012'), ('013')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,7 @@
+-- the listing sorted by new_limit, as the other sort columns, reads the offers of an account in the order of the index
+CREATE INDEX IF NOT EXISTS limit_offer_account_new_limit_idx
+    ON public.limit_offer (account_id, new_limit, id);
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('014')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- the relays lease the events they publish instead of keeping them locked while publishing, an event whose lease
+-- ran out is claimed again by the next relay
+ALTER TABLE public.outbox
+    ADD COLUMN IF NOT EXISTS leased_until timestamp with time zone;
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('015')
+ON CONFLICT (version) DO NOTHING;
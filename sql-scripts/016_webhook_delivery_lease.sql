@@ -0,0 +1,8 @@
+-- the dispatchers lease the deliveries they post instead of keeping them locked while posting, a delivery whose
+-- lease ran out is posted again by the next dispatcher
+ALTER TABLE public.webhook_delivery
+    ADD COLUMN IF NOT EXISTS leased_until timestamp with time zone;
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('016')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- the dispatchers lease the notifications they send instead of keeping them locked while sending, a notification
+-- whose lease ran out is sent again by the next dispatcher
+ALTER TABLE public.notification
+    ADD COLUMN IF NOT EXISTS leased_until timestamp with time zone;
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('017')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,7 @@
+-- the relays purge the events published before the retention of the outbox, oldest first
+CREATE INDEX IF NOT EXISTS outbox_published_idx
+    ON public.outbox (published_at) WHERE published_at IS NOT NULL;
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('018')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,21 @@
+-- the listing sorts descending by default, with the NULLs last, which the ascending indexes of 003 and 014 can not be
+-- read in, they are created again in the order of that listing
+DROP INDEX IF EXISTS public.limit_offer_account_created_at_idx;
+CREATE INDEX IF NOT EXISTS limit_offer_account_created_at_idx
+    ON public.limit_offer (account_id, created_at DESC NULLS LAST, id DESC);
+
+DROP INDEX IF EXISTS public.limit_offer_account_activation_time_idx;
+CREATE INDEX IF NOT EXISTS limit_offer_account_activation_time_idx
+    ON public.limit_offer (account_id, offer_activation_time DESC NULLS LAST, id DESC);
+
+DROP INDEX IF EXISTS public.limit_offer_account_expiry_time_idx;
+CREATE INDEX IF NOT EXISTS limit_offer_account_expiry_time_idx
+    ON public.limit_offer (account_id, offer_expiry_time DESC NULLS LAST, id DESC);
+
+DROP INDEX IF EXISTS public.limit_offer_account_new_limit_idx;
+CREATE INDEX IF NOT EXISTS limit_offer_account_new_limit_idx
+    ON public.limit_offer (account_id, new_limit DESC NULLS LAST, id DESC);
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('019')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- a redelivered delivery keeps counting its attempts, so that the attempt log has no number twice, the attempts made
+-- before its last redelivery are not taken from its budget of attempts
+ALTER TABLE public.webhook_delivery
+    ADD COLUMN IF NOT EXISTS redelivered_attempts integer NOT NULL DEFAULT 0;
+
+INSERT INTO public.schema_migrations (version)
+VALUES ('020')
+ON CONFLICT (version) DO NOTHING;